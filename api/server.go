@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+
+	"tender_bot_go/db"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+// Server — REST API над тендерами, ставками и историей для внешних систем (ERP).
+// Все запросы требуют заголовок X-API-Key.
+type Server struct {
+	queries Store
+	apiKey  string
+
+	// OnCreated вызывается после создания тендера через API (уведомление админов)
+	OnCreated func(tender db.Tender)
+	// OnApproved вызывается после одобрения тендера через API (рассылка поставщикам)
+	OnApproved func(tenderID int32)
+}
+
+// Store — сгенерированные запросы, транзакционное создание тендера с лотами
+// и смена статуса тендера с записью в журнал
+type Store interface {
+	db.Querier
+	CreateTenderWithLots(ctx context.Context, arg db.CreateTenderParams, lots []db.CreateTenderLotParams, classifications []string) (db.Tender, []db.TenderLot, error)
+	ChangeTenderStatus(ctx context.Context, arg db.ChangeTenderStatusParams) (bool, error)
+}
+
+func NewServer(queries Store, apiKey string) *Server {
+	return &Server{queries: queries, apiKey: apiKey}
+}
+
+// App собирает fiber-приложение со всеми маршрутами
+func (s *Server) App() *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: errorHandler,
+	})
+
+	app.Use(s.authMiddleware)
+
+	app.Get("/tenders", s.listTenders)
+	app.Post("/tenders", s.createTender)
+	app.Get("/tenders/:id", s.getTender)
+	app.Get("/tenders/:id/lots", s.listTenderLots)
+	app.Get("/tenders/:id/bids", s.listTenderBids)
+	app.Post("/tenders/:id/approve", s.approveTender)
+	app.Get("/tenders/:id/events", s.listTenderEvents)
+	app.Get("/history", s.listHistory)
+
+	return app
+}
+
+// Listen запускает HTTP-сервер на указанном адресе, например ":80"
+func (s *Server) Listen(addr string) error {
+	return s.App().Listen(addr)
+}
+
+func (s *Server) authMiddleware(c *fiber.Ctx) error {
+	key := c.Get("X-API-Key")
+	if s.apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(s.apiKey)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid api key")
+	}
+	return c.Next()
+}
+
+func errorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	message := "internal error"
+
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &fiberErr):
+		code = fiberErr.Code
+		message = fiberErr.Message
+	case errors.Is(err, pgx.ErrNoRows):
+		code = fiber.StatusNotFound
+		message = "not found"
+	}
+
+	return c.Status(code).JSON(fiber.Map{"error": message})
+}
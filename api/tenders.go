@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"tender_bot_go/db"
+	"tender_bot_go/tender"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+)
+
+const requestTimeout = 5 * time.Second
+
+type createTenderRequest struct {
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	StartPrice     float64   `json:"start_price"`
+	StartAt        time.Time `json:"start_at"`
+	Classification string    `json:"classification"`
+	MinBidDecrease float64   `json:"min_bid_decrease"`
+	MinBidStepType string    `json:"min_bid_step_type"`
+	Type           string    `json:"type"`
+	EndAt          time.Time `json:"end_at"`
+	OrganizerID    int64     `json:"organizer_id"`
+	// ExtensionMinutes — продление открытого тендера при ставке в последние минуты перед end_at
+	ExtensionMinutes int32 `json:"extension_minutes"`
+	// Lots — лоты тендера. Без лотов тендер состоит из одного лота,
+	// собранного из полей верхнего уровня
+	Lots []tender.LotDraft `json:"lots"`
+	// Classifications — коды классификаций тендера помимо классификаций лотов
+	Classifications []string `json:"classifications"`
+}
+
+// tenderWithLots — тендер вместе с его лотами в ответе API
+type tenderWithLots struct {
+	db.Tender
+	Lots []db.TenderLot `json:"lots"`
+}
+
+func tenderIDParam(c *fiber.Ctx) (int32, error) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid tender id")
+	}
+	return int32(id), nil
+}
+
+// GET /tenders — тендеры, которые ещё не завершены
+func (s *Server) listTenders(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	tenders, err := s.queries.GetTenders(ctx)
+	if err != nil {
+		return err
+	}
+	return c.JSON(tenders)
+}
+
+// GET /tenders/:id
+func (s *Server) getTender(c *fiber.Ctx) error {
+	id, err := tenderIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	t, err := s.queries.GetTenderById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(t)
+}
+
+// GET /tenders/:id/lots — лоты тендера по порядку
+func (s *Server) listTenderLots(c *fiber.Ctx) error {
+	id, err := tenderIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	if _, err := s.queries.GetTenderById(ctx, id); err != nil {
+		return err
+	}
+
+	lots, err := s.queries.GetTenderLots(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(lots)
+}
+
+// GET /tenders/:id/bids — все ставки тендера по времени подачи.
+// Предложения закрытого тендера вскрываются только после его завершения.
+func (s *Server) listTenderBids(c *fiber.Ctx) error {
+	id, err := tenderIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	t, err := s.queries.GetTenderById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Type == db.TenderTypeSealed && t.Status != "completed" && t.Status != "failed" {
+		return fiber.NewError(fiber.StatusForbidden, "bids of a sealed tender are revealed after closing")
+	}
+
+	bids, err := s.queries.GetBidsHistoryByTenderID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(bids)
+}
+
+// GET /tenders/:id/events — журнал смены статусов тендера: кто, когда и почему
+func (s *Server) listTenderEvents(c *fiber.Ctx) error {
+	id, err := tenderIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	if _, err := s.queries.GetTenderById(ctx, id); err != nil {
+		return err
+	}
+
+	events, err := s.queries.GetTenderEvents(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(events)
+}
+
+// GET /history — архив завершённых тендеров с победителями
+func (s *Server) listHistory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	history, err := s.queries.GetTendersHistory(ctx)
+	if err != nil {
+		return err
+	}
+	return c.JSON(history)
+}
+
+// POST /tenders — создание тендера с теми же проверками, что и в мастере бота.
+// Тендер создаётся в статусе pending_approval и ждёт одобрения администратора.
+func (s *Server) createTender(c *fiber.Ctx) error {
+	var req createTenderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	// Без явного типа создаётся открытый аукцион, как до появления закрытых тендеров
+	if req.Type == "" {
+		req.Type = db.TenderTypeOpen
+	}
+
+	lots := req.Lots
+	if len(lots) == 0 {
+		lots = []tender.LotDraft{{
+			Title:          req.Title,
+			StartPrice:     req.StartPrice,
+			MinBidDecrease: req.MinBidDecrease,
+			MinBidStepType: req.MinBidStepType,
+			Classification: req.Classification,
+		}}
+	}
+
+	draft := tender.Draft{
+		Title:            req.Title,
+		Description:      req.Description,
+		StartAt:          req.StartAt,
+		Type:             req.Type,
+		EndAt:            req.EndAt,
+		OrganizerID:      req.OrganizerID,
+		Lots:             lots,
+		ExtensionMinutes: req.ExtensionMinutes,
+		Classifications:  req.Classifications,
+	}
+	if err := draft.Validate(time.Now()); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	// Классификации берутся из справочника; архивные для новых тендеров недоступны
+	for _, code := range draft.ClassificationCodes() {
+		classification, err := s.queries.GetClassification(ctx, code)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && classification.Archived) {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("unknown or archived classification %q", code))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Владельцем тендера может быть только пользователь с ролью организатора
+	organizer, err := s.queries.GetUserByTelegramID(ctx, req.OrganizerID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && organizer.Role != "organizer") {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "organizer_id is not an organizer")
+	}
+	if err != nil {
+		return err
+	}
+
+	created, createdLots, err := s.queries.CreateTenderWithLots(ctx, draft.CreateParams(), draft.LotParams(), draft.ClassificationCodes())
+	if err != nil {
+		return err
+	}
+
+	if s.OnCreated != nil {
+		go s.OnCreated(created)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(tenderWithLots{Tender: created, Lots: createdLots})
+}
+
+// POST /tenders/:id/approve — перевод тендера из pending_approval в active_pending
+func (s *Server) approveTender(c *fiber.Ctx) error {
+	id, err := tenderIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), requestTimeout)
+	defer cancel()
+
+	if _, err := s.queries.GetTenderById(ctx, id); err != nil {
+		return err
+	}
+
+	// Решение записывается в одной транзакции со сменой статуса: при ошибке тендер
+	// остаётся на модерации и клиент получает 500
+	_, err = tender.Review(ctx, s.queries, id, tender.ReviewApproved, tender.SystemActor, "одобрен через REST API")
+	if errors.Is(err, tender.ErrStatusChanged) {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("tender %d is not pending approval", id))
+	}
+	if err != nil {
+		return err
+	}
+
+	t, err := s.queries.GetTenderById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if s.OnApproved != nil {
+		go s.OnApproved(id)
+	}
+
+	return c.JSON(t)
+}
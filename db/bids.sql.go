@@ -29,9 +29,10 @@ func (q *Queries) CheckBidExists(ctx context.Context, arg CheckBidExistsParams)
 	return count, err
 }
 
-const createBid = `-- name: CreateBid :exec
-INSERT INTO tender_bids (tender_id, user_id, amount, bid_time) 
-VALUES ($1, $2, $3, $4)
+const createBid = `-- name: CreateBid :one
+INSERT INTO tender_bids (tender_id, user_id, amount, bid_time, lot_id) 
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, tender_id, user_id, amount, bid_time, lot_id
 `
 
 type CreateBidParams struct {
@@ -39,20 +40,31 @@ type CreateBidParams struct {
 	UserID   int64              `json:"user_id"`
 	Amount   float64            `json:"amount"`
 	BidTime  pgtype.Timestamptz `json:"bid_time"`
+	LotID    int32              `json:"lot_id"`
 }
 
-func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) error {
-	_, err := q.db.Exec(ctx, createBid,
+func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) (TenderBid, error) {
+	row := q.db.QueryRow(ctx, createBid,
 		arg.TenderID,
 		arg.UserID,
 		arg.Amount,
 		arg.BidTime,
+		arg.LotID,
 	)
-	return err
+	var i TenderBid
+	err := row.Scan(
+		&i.ID,
+		&i.TenderID,
+		&i.UserID,
+		&i.Amount,
+		&i.BidTime,
+		&i.LotID,
+	)
+	return i, err
 }
 
 const getBidsAfterTime = `-- name: GetBidsAfterTime :many
-SELECT id, tender_id, user_id, amount, bid_time FROM tender_bids 
+SELECT id, tender_id, user_id, amount, bid_time, lot_id FROM tender_bids 
 WHERE tender_id = $1 AND bid_time > $2 
 ORDER BY bid_time DESC
 `
@@ -77,6 +89,7 @@ func (q *Queries) GetBidsAfterTime(ctx context.Context, arg GetBidsAfterTimePara
 			&i.UserID,
 			&i.Amount,
 			&i.BidTime,
+			&i.LotID,
 		); err != nil {
 			return nil, err
 		}
@@ -88,13 +101,51 @@ func (q *Queries) GetBidsAfterTime(ctx context.Context, arg GetBidsAfterTimePara
 	return items, nil
 }
 
-const getBidsHistoryByTenderID = `-- name: GetBidsHistoryByTenderID :many
+const getBidsHistoryByLotID = `-- name: GetBidsHistoryByLotID :many
 SELECT 
     b.amount,
     b.bid_time,
     u.organization_name
 FROM tender_bids b
 JOIN users u ON b.user_id = u.telegram_id
+WHERE b.lot_id = $1
+ORDER BY b.bid_time ASC
+`
+
+type GetBidsHistoryByLotIDRow struct {
+	Amount           float64            `json:"amount"`
+	BidTime          pgtype.Timestamptz `json:"bid_time"`
+	OrganizationName pgtype.Text        `json:"organization_name"`
+}
+
+func (q *Queries) GetBidsHistoryByLotID(ctx context.Context, lotID int32) ([]GetBidsHistoryByLotIDRow, error) {
+	rows, err := q.db.Query(ctx, getBidsHistoryByLotID, lotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetBidsHistoryByLotIDRow{}
+	for rows.Next() {
+		var i GetBidsHistoryByLotIDRow
+		if err := rows.Scan(&i.Amount, &i.BidTime, &i.OrganizationName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBidsHistoryByTenderID = `-- name: GetBidsHistoryByTenderID :many
+SELECT 
+    b.amount,
+    b.bid_time,
+    u.organization_name,
+    b.lot_id
+FROM tender_bids b
+JOIN users u ON b.user_id = u.telegram_id
 WHERE b.tender_id = $1
 ORDER BY b.bid_time ASC
 `
@@ -103,6 +154,7 @@ type GetBidsHistoryByTenderIDRow struct {
 	Amount           float64            `json:"amount"`
 	BidTime          pgtype.Timestamptz `json:"bid_time"`
 	OrganizationName pgtype.Text        `json:"organization_name"`
+	LotID            int32              `json:"lot_id"`
 }
 
 func (q *Queries) GetBidsHistoryByTenderID(ctx context.Context, tenderID int32) ([]GetBidsHistoryByTenderIDRow, error) {
@@ -114,7 +166,12 @@ func (q *Queries) GetBidsHistoryByTenderID(ctx context.Context, tenderID int32)
 	items := []GetBidsHistoryByTenderIDRow{}
 	for rows.Next() {
 		var i GetBidsHistoryByTenderIDRow
-		if err := rows.Scan(&i.Amount, &i.BidTime, &i.OrganizationName); err != nil {
+		if err := rows.Scan(
+			&i.Amount,
+			&i.BidTime,
+			&i.OrganizationName,
+			&i.LotID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -125,6 +182,27 @@ func (q *Queries) GetBidsHistoryByTenderID(ctx context.Context, tenderID int32)
 	return items, nil
 }
 
+const getLowestLotBid = `-- name: GetLowestLotBid :one
+SELECT id, tender_id, user_id, amount, bid_time, lot_id FROM tender_bids
+WHERE lot_id = $1
+ORDER BY amount ASC, bid_time ASC
+LIMIT 1
+`
+
+func (q *Queries) GetLowestLotBid(ctx context.Context, lotID int32) (TenderBid, error) {
+	row := q.db.QueryRow(ctx, getLowestLotBid, lotID)
+	var i TenderBid
+	err := row.Scan(
+		&i.ID,
+		&i.TenderID,
+		&i.UserID,
+		&i.Amount,
+		&i.BidTime,
+		&i.LotID,
+	)
+	return i, err
+}
+
 const getUserBidCount = `-- name: GetUserBidCount :one
 SELECT COUNT(*) FROM tender_bids
 WHERE tender_id = $1 AND user_id = $2
@@ -142,8 +220,46 @@ func (q *Queries) GetUserBidCount(ctx context.Context, arg GetUserBidCountParams
 	return count, err
 }
 
+const getUserBidsForLot = `-- name: GetUserBidsForLot :many
+SELECT id, tender_id, user_id, amount, bid_time, lot_id FROM tender_bids
+WHERE lot_id = $1 AND user_id = $2
+ORDER BY bid_time DESC
+`
+
+type GetUserBidsForLotParams struct {
+	LotID  int32 `json:"lot_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) GetUserBidsForLot(ctx context.Context, arg GetUserBidsForLotParams) ([]TenderBid, error) {
+	rows, err := q.db.Query(ctx, getUserBidsForLot, arg.LotID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TenderBid{}
+	for rows.Next() {
+		var i TenderBid
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenderID,
+			&i.UserID,
+			&i.Amount,
+			&i.BidTime,
+			&i.LotID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserBidsForTender = `-- name: GetUserBidsForTender :many
-SELECT id, tender_id, user_id, amount, bid_time FROM tender_bids 
+SELECT id, tender_id, user_id, amount, bid_time, lot_id FROM tender_bids 
 WHERE tender_id = $1 AND user_id = $2 
 ORDER BY bid_time DESC
 `
@@ -168,6 +284,7 @@ func (q *Queries) GetUserBidsForTender(ctx context.Context, arg GetUserBidsForTe
 			&i.UserID,
 			&i.Amount,
 			&i.BidTime,
+			&i.LotID,
 		); err != nil {
 			return nil, err
 		}
@@ -179,18 +296,19 @@ func (q *Queries) GetUserBidsForTender(ctx context.Context, arg GetUserBidsForTe
 	return items, nil
 }
 
-const updateTenderCurrentPrice = `-- name: UpdateTenderCurrentPrice :exec
-UPDATE tenders 
-SET current_price = $2
-WHERE id = $1
+const getUserLotBidCount = `-- name: GetUserLotBidCount :one
+SELECT COUNT(*) FROM tender_bids
+WHERE lot_id = $1 AND user_id = $2
 `
 
-type UpdateTenderCurrentPriceParams struct {
-	ID           int32   `json:"id"`
-	CurrentPrice float64 `json:"current_price"`
+type GetUserLotBidCountParams struct {
+	LotID  int32 `json:"lot_id"`
+	UserID int64 `json:"user_id"`
 }
 
-func (q *Queries) UpdateTenderCurrentPrice(ctx context.Context, arg UpdateTenderCurrentPriceParams) error {
-	_, err := q.db.Exec(ctx, updateTenderCurrentPrice, arg.ID, arg.CurrentPrice)
-	return err
+func (q *Queries) GetUserLotBidCount(ctx context.Context, arg GetUserLotBidCountParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserLotBidCount, arg.LotID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
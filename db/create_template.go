@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateTemplateWithLots в одной транзакции создаёт шаблон тендера и его лоты.
+// Лоты нумеруются с единицы в порядке передачи, TemplateID заполняется автоматически.
+func (q *Queries) CreateTemplateWithLots(ctx context.Context, arg CreateTenderTemplateParams, lots []CreateTenderTemplateLotParams) (TenderTemplate, []TenderTemplateLot, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return TenderTemplate{}, nil, fmt.Errorf("create template: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return TenderTemplate{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	template, err := qtx.CreateTenderTemplate(ctx, arg)
+	if err != nil {
+		return TenderTemplate{}, nil, err
+	}
+
+	created := make([]TenderTemplateLot, 0, len(lots))
+	for i, lotArg := range lots {
+		lotArg.TemplateID = template.ID
+		lotArg.Number = int32(i + 1)
+		lot, err := qtx.CreateTenderTemplateLot(ctx, lotArg)
+		if err != nil {
+			return TenderTemplate{}, nil, err
+		}
+		created = append(created, lot)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return TenderTemplate{}, nil, err
+	}
+	return template, created, nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateTenderWithLots в одной транзакции создаёт тендер, его лоты и классификации.
+// Лоты нумеруются с единицы в порядке передачи, TenderID в параметрах лотов
+// заполняется автоматически.
+func (q *Queries) CreateTenderWithLots(ctx context.Context, arg CreateTenderParams, lots []CreateTenderLotParams, classifications []string) (Tender, []TenderLot, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return Tender{}, nil, fmt.Errorf("create tender: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return Tender{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	tender, err := qtx.CreateTender(ctx, arg)
+	if err != nil {
+		return Tender{}, nil, err
+	}
+
+	created := make([]TenderLot, 0, len(lots))
+	for i, lotArg := range lots {
+		lotArg.TenderID = tender.ID
+		lotArg.Number = int32(i + 1)
+		lot, err := qtx.CreateTenderLot(ctx, lotArg)
+		if err != nil {
+			return Tender{}, nil, err
+		}
+		created = append(created, lot)
+	}
+
+	if err := qtx.AddTenderClassifications(ctx, AddTenderClassificationsParams{
+		TenderID: tender.ID,
+		Codes:    classifications,
+	}); err != nil {
+		return Tender{}, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Tender{}, nil, err
+	}
+	return tender, created, nil
+}
+
+// UpdateTenderWithLots в одной транзакции заменяет поля тендера, его лоты и классификации
+// данными исправленного черновика. Используется при повторной отправке отклонённого
+// тендера, у которого ещё нет ставок и участников. Внутри транзакции смены статуса
+// выполняется как вложенная (savepoint).
+func (q *Queries) UpdateTenderWithLots(ctx context.Context, id int32, arg CreateTenderParams, lots []CreateTenderLotParams, classifications []string) (Tender, []TenderLot, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return Tender{}, nil, fmt.Errorf("update tender: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return Tender{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	tender, err := qtx.UpdateTenderDraft(ctx, UpdateTenderDraftParams{
+		ID:               id,
+		Title:            arg.Title,
+		Description:      arg.Description,
+		StartPrice:       arg.StartPrice,
+		StartAt:          arg.StartAt,
+		ConditionsPath:   arg.ConditionsPath,
+		CurrentPrice:     arg.CurrentPrice,
+		Classification:   arg.Classification,
+		MinBidDecrease:   arg.MinBidDecrease,
+		MinBidStepType:   arg.MinBidStepType,
+		Type:             arg.Type,
+		EndAt:            arg.EndAt,
+		ExtensionMinutes: arg.ExtensionMinutes,
+	})
+	if err != nil {
+		return Tender{}, nil, err
+	}
+
+	if err := qtx.DeleteTenderLots(ctx, id); err != nil {
+		return Tender{}, nil, err
+	}
+
+	updated := make([]TenderLot, 0, len(lots))
+	for i, lotArg := range lots {
+		lotArg.TenderID = tender.ID
+		lotArg.Number = int32(i + 1)
+		lot, err := qtx.CreateTenderLot(ctx, lotArg)
+		if err != nil {
+			return Tender{}, nil, err
+		}
+		updated = append(updated, lot)
+	}
+
+	if err := qtx.DeleteTenderClassifications(ctx, id); err != nil {
+		return Tender{}, nil, err
+	}
+	if err := qtx.AddTenderClassifications(ctx, AddTenderClassificationsParams{
+		TenderID: id,
+		Codes:    classifications,
+	}); err != nil {
+		return Tender{}, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Tender{}, nil, err
+	}
+	return tender, updated, nil
+}
@@ -12,9 +12,19 @@ import (
 const dropDb = `-- name: DropDb :exec
 TRUNCATE TABLE 
     history, 
+    winner_offers,
+    tender_events,
+    tender_reviews,
+    tender_template_lots,
+    tender_templates,
     tender_bids, 
+    tender_lots,
     tender_participants, 
     pending_users,
+    supplier_documents,
+    conversation_states,
+    user_classifications,
+    tender_classifications,
 	tenders,
 	users
 CASCADE
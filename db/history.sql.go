@@ -12,19 +12,21 @@ import (
 )
 
 const addToHistory = `-- name: AddToHistory :exec
-INSERT INTO history (tender_id, title, winner, phone_number, inn, fio, bid, start_price)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO history (tender_id, title, winner, phone_number, inn, fio, bid, start_price, lot_id, outcome)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 `
 
 type AddToHistoryParams struct {
-	TenderID    int32       `json:"tender_id"`
-	Title       string      `json:"title"`
-	Winner      pgtype.Text `json:"winner"`
-	PhoneNumber pgtype.Text `json:"phone_number"`
-	Inn         pgtype.Text `json:"inn"`
-	Fio         pgtype.Text `json:"fio"`
-	Bid         float64     `json:"bid"`
-	StartPrice  float64     `json:"start_price"`
+	TenderID    int32         `json:"tender_id"`
+	Title       string        `json:"title"`
+	Winner      pgtype.Text   `json:"winner"`
+	PhoneNumber pgtype.Text   `json:"phone_number"`
+	Inn         pgtype.Text   `json:"inn"`
+	Fio         pgtype.Text   `json:"fio"`
+	Bid         pgtype.Float8 `json:"bid"`
+	StartPrice  float64       `json:"start_price"`
+	LotID       int32         `json:"lot_id"`
+	Outcome     string        `json:"outcome"`
 }
 
 func (q *Queries) AddToHistory(ctx context.Context, arg AddToHistoryParams) error {
@@ -37,12 +39,56 @@ func (q *Queries) AddToHistory(ctx context.Context, arg AddToHistoryParams) erro
 		arg.Fio,
 		arg.Bid,
 		arg.StartPrice,
+		arg.LotID,
+		arg.Outcome,
 	)
 	return err
 }
 
+const getOrganizerTendersHistory = `-- name: GetOrganizerTendersHistory :many
+SELECT h.id, h.tender_id, h.title, h.winner, h.phone_number, h.inn, h.fio, h.bid, h.start_price, h.created_at, h.lot_id, h.outcome FROM history h
+JOIN tenders t ON t.id = h.tender_id
+WHERE t.organizer_id = $1 AND h.outcome IN ('completed', 'failed')
+ORDER BY h.created_at ASC
+`
+
+func (q *Queries) GetOrganizerTendersHistory(ctx context.Context, organizerID pgtype.Int8) ([]History, error) {
+	rows, err := q.db.Query(ctx, getOrganizerTendersHistory, organizerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []History{}
+	for rows.Next() {
+		var i History
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenderID,
+			&i.Title,
+			&i.Winner,
+			&i.PhoneNumber,
+			&i.Inn,
+			&i.Fio,
+			&i.Bid,
+			&i.StartPrice,
+			&i.CreatedAt,
+			&i.LotID,
+			&i.Outcome,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTendersHistory = `-- name: GetTendersHistory :many
-SELECT id, tender_id, title, winner, phone_number, inn, fio, bid, start_price, created_at FROM history ORDER BY created_at ASC
+SELECT id, tender_id, title, winner, phone_number, inn, fio, bid, start_price, created_at, lot_id, outcome FROM history
+WHERE outcome IN ('completed', 'failed')
+ORDER BY created_at ASC
 `
 
 func (q *Queries) GetTendersHistory(ctx context.Context) ([]History, error) {
@@ -65,6 +111,8 @@ func (q *Queries) GetTendersHistory(ctx context.Context) ([]History, error) {
 			&i.Bid,
 			&i.StartPrice,
 			&i.CreatedAt,
+			&i.LotID,
+			&i.Outcome,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// TenderWithLotsParams — тендер, его лоты и классификации для пакетного создания
+type TenderWithLotsParams struct {
+	Tender          CreateTenderParams
+	Lots            []CreateTenderLotParams
+	Classifications []string
+}
+
+// CreateTendersWithLots в одной транзакции создаёт несколько тендеров с лотами:
+// либо создаются все, либо ни одного. Лоты каждого тендера нумеруются с единицы.
+func (q *Queries) CreateTendersWithLots(ctx context.Context, batch []TenderWithLotsParams) ([]Tender, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return nil, fmt.Errorf("create tenders: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	created := make([]Tender, 0, len(batch))
+	for _, item := range batch {
+		tender, err := qtx.CreateTender(ctx, item.Tender)
+		if err != nil {
+			return nil, err
+		}
+		for i, lotArg := range item.Lots {
+			lotArg.TenderID = tender.ID
+			lotArg.Number = int32(i + 1)
+			if _, err := qtx.CreateTenderLot(ctx, lotArg); err != nil {
+				return nil, err
+			}
+		}
+		if err := qtx.AddTenderClassifications(ctx, AddTenderClassificationsParams{
+			TenderID: tender.ID,
+			Codes:    item.Classifications,
+		}); err != nil {
+			return nil, err
+		}
+		created = append(created, tender)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
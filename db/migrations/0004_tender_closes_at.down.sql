@@ -0,0 +1,2 @@
+ALTER TABLE tenders
+DROP COLUMN closes_at;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE tenders
+ADD COLUMN closes_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS conversation_states;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE tenders
+DROP COLUMN min_bid_step_type;
@@ -0,0 +1,5 @@
+ALTER TABLE tenders
+ADD COLUMN min_bid_step_type VARCHAR(8) NOT NULL DEFAULT 'amount';
+
+-- До появления настройки все тендеры работали с шагом 1% от текущей цены
+UPDATE tenders SET min_bid_step_type = 'percent', min_bid_decrease = 1.0;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tenders_organizer_id;
+
+ALTER TABLE tenders
+DROP COLUMN organizer_id;
@@ -0,0 +1,9 @@
+ALTER TABLE tenders
+ADD COLUMN organizer_id BIGINT REFERENCES users(telegram_id) ON DELETE SET NULL;
+
+-- Если организатор был один, все существующие тендеры принадлежат ему
+UPDATE tenders
+SET organizer_id = (SELECT telegram_id FROM users WHERE role = 'organizer')
+WHERE (SELECT COUNT(*) FROM users WHERE role = 'organizer') = 1;
+
+CREATE INDEX idx_tenders_organizer_id ON tenders(organizer_id);
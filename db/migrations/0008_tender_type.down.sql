@@ -0,0 +1,8 @@
+ALTER TABLE tenders
+DROP CONSTRAINT IF EXISTS tenders_sealed_end_at;
+
+ALTER TABLE tenders
+DROP COLUMN end_at;
+
+ALTER TABLE tenders
+DROP COLUMN type;
@@ -0,0 +1,9 @@
+ALTER TABLE tenders
+ADD COLUMN type VARCHAR(8) NOT NULL DEFAULT 'open';
+
+-- Срок приёма предложений закрытого тендера
+ALTER TABLE tenders
+ADD COLUMN end_at TIMESTAMPTZ;
+
+ALTER TABLE tenders
+ADD CONSTRAINT tenders_sealed_end_at CHECK (type <> 'sealed' OR end_at IS NOT NULL);
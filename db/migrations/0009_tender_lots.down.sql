@@ -0,0 +1,7 @@
+ALTER TABLE history
+DROP COLUMN lot_id;
+
+ALTER TABLE tender_bids
+DROP COLUMN lot_id;
+
+DROP TABLE IF EXISTS tender_lots;
@@ -0,0 +1,45 @@
+CREATE TABLE tender_lots (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    start_price FLOAT NOT NULL,
+    current_price FLOAT NOT NULL,
+    min_bid_decrease FLOAT NOT NULL DEFAULT 0,
+    min_bid_step_type VARCHAR(8) NOT NULL DEFAULT 'amount',
+    classification VARCHAR(255),
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    last_bid_at TIMESTAMPTZ,
+    closes_at TIMESTAMPTZ,
+    CONSTRAINT unique_tender_lot_number UNIQUE (tender_id, number)
+);
+
+-- Каждый существующий тендер становится тендером из одного лота
+INSERT INTO tender_lots (tender_id, number, title, start_price, current_price, min_bid_decrease, min_bid_step_type, classification, status, last_bid_at, closes_at)
+SELECT id, 1, title, start_price, current_price, min_bid_decrease, min_bid_step_type, classification,
+       CASE WHEN status = 'completed' THEN 'completed' ELSE 'open' END,
+       last_bid_at, closes_at
+FROM tenders;
+
+ALTER TABLE tender_bids
+ADD COLUMN lot_id INTEGER REFERENCES tender_lots(id) ON DELETE CASCADE;
+
+UPDATE tender_bids b SET lot_id = l.id
+FROM tender_lots l
+WHERE l.tender_id = b.tender_id;
+
+ALTER TABLE tender_bids
+ALTER COLUMN lot_id SET NOT NULL;
+
+ALTER TABLE history
+ADD COLUMN lot_id INTEGER REFERENCES tender_lots(id) ON DELETE CASCADE;
+
+UPDATE history h SET lot_id = l.id
+FROM tender_lots l
+WHERE l.tender_id = h.tender_id;
+
+ALTER TABLE history
+ALTER COLUMN lot_id SET NOT NULL;
+
+CREATE INDEX idx_tender_lots_tender_id ON tender_lots(tender_id);
+CREATE INDEX idx_tender_bids_lot_id ON tender_bids(lot_id);
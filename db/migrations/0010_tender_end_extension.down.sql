@@ -0,0 +1,8 @@
+-- До этой миграции несостоявшиеся торги считались завершёнными
+UPDATE tenders SET status = 'completed' WHERE status = 'failed';
+UPDATE tender_lots SET status = 'completed' WHERE status = 'failed';
+
+DROP INDEX IF EXISTS idx_tenders_end_at;
+
+ALTER TABLE tenders
+DROP COLUMN extension_minutes;
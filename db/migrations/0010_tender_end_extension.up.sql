@@ -0,0 +1,7 @@
+-- Антиснайпинг: ставка в последние extension_minutes минут до end_at
+-- продлевает срок тендера на extension_minutes минут
+ALTER TABLE tenders
+ADD COLUMN extension_minutes INTEGER NOT NULL DEFAULT 0;
+
+-- Тендеры, по которым нужно завершить торги по сроку end_at
+CREATE INDEX idx_tenders_end_at ON tenders(end_at) WHERE status = 'active';
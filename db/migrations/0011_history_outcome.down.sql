@@ -0,0 +1,7 @@
+DELETE FROM history WHERE bid IS NULL;
+
+ALTER TABLE history
+ALTER COLUMN bid SET NOT NULL;
+
+ALTER TABLE history
+DROP COLUMN outcome;
@@ -0,0 +1,7 @@
+-- Итог торгов по лоту: completed — есть победитель, failed — ставок не поступило
+ALTER TABLE history
+ADD COLUMN outcome VARCHAR(16) NOT NULL DEFAULT 'completed';
+
+-- У несостоявшихся торгов нет выигрышной ставки
+ALTER TABLE history
+ALTER COLUMN bid DROP NOT NULL;
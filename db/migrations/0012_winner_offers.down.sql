@@ -0,0 +1,4 @@
+-- Лоты, ожидавшие подтверждения победителя, считаются несостоявшимися
+UPDATE tender_lots SET status = 'failed' WHERE status = 'confirming';
+
+DROP TABLE IF EXISTS winner_offers;
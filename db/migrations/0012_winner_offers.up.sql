@@ -0,0 +1,17 @@
+-- Предложения заключить договор по лоту: победитель подтверждает результат,
+-- а при отказе или истечении срока лот предлагается следующему участнику
+CREATE TABLE winner_offers (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    lot_id INTEGER NOT NULL REFERENCES tender_lots(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    amount FLOAT NOT NULL,
+    rank INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    offered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    CONSTRAINT unique_lot_offer_user UNIQUE (lot_id, user_id)
+);
+
+CREATE INDEX idx_winner_offers_pending ON winner_offers(expires_at) WHERE status = 'pending';
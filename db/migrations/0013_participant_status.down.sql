@@ -0,0 +1,6 @@
+DELETE FROM tender_participants WHERE status <> 'active';
+
+DROP INDEX IF EXISTS idx_tender_participants_user_id;
+
+ALTER TABLE tender_participants
+DROP COLUMN status;
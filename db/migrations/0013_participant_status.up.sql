@@ -0,0 +1,6 @@
+-- Участие в тендере больше не удаляется после завершения: итог хранится в status
+-- (active — тендер идёт, won, lost, withdrew — отказался от участия, no_bid — не делал ставок)
+ALTER TABLE tender_participants
+ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active';
+
+CREATE INDEX idx_tender_participants_user_id ON tender_participants(user_id);
@@ -0,0 +1,13 @@
+-- Журнал смены статусов тендера: кто, когда и почему перевёл тендер в новый статус.
+-- actor_id пустой, если статус сменила система (фоновая задача, таймер, REST API)
+CREATE TABLE tender_events (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    actor_id BIGINT,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_tender_events_tender_id ON tender_events(tender_id);
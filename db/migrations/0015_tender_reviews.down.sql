@@ -0,0 +1,4 @@
+-- Отклонённые тендеры возвращаются на модерацию
+UPDATE tenders SET status = 'pending_approval' WHERE status = 'rejected';
+
+DROP TABLE IF EXISTS tender_reviews;
@@ -0,0 +1,12 @@
+-- Решения администратора по тендеру: каждая отправка на модерацию — отдельный раунд
+-- (round), отклонённый тендер после исправления проходит модерацию заново
+CREATE TABLE tender_reviews (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    decision VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    reviewer_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_tender_review_round UNIQUE (tender_id, round)
+);
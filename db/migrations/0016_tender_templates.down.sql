@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tender_template_lots;
+DROP TABLE IF EXISTS tender_templates;
@@ -0,0 +1,27 @@
+-- Шаблоны тендеров для повторяющихся закупок: условия и лоты без дат и статусов.
+-- duration_minutes — срок торгов от даты начала до окончания, если он был задан
+CREATE TABLE tender_templates (
+    id SERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    type VARCHAR(8) NOT NULL DEFAULT 'open',
+    duration_minutes INTEGER,
+    extension_minutes INTEGER NOT NULL DEFAULT 0,
+    conditions_path VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_organizer_template_name UNIQUE (organizer_id, name)
+);
+
+CREATE TABLE tender_template_lots (
+    id SERIAL PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES tender_templates(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    start_price FLOAT NOT NULL,
+    min_bid_decrease FLOAT NOT NULL DEFAULT 0,
+    min_bid_step_type VARCHAR(8) NOT NULL DEFAULT 'amount',
+    classification VARCHAR(255),
+    CONSTRAINT unique_template_lot_number UNIQUE (template_id, number)
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_pending_users_inn;
+
+-- users.ogrn остаётся VARCHAR(15): после одобрения ИП в колонке уже могут быть
+-- 15-значные ОГРНИП, и сужение типа на них упадёт
+
+ALTER TABLE pending_users
+DROP COLUMN IF EXISTS ogrn;
+
+ALTER TABLE pending_users
+DROP CONSTRAINT IF EXISTS pending_users_telegram_id_key;
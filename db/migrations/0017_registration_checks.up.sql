@@ -0,0 +1,16 @@
+-- У пользователя может быть только одна заявка на регистрацию: оставляем самую свежую
+DELETE FROM pending_users p
+USING pending_users newer
+WHERE newer.telegram_id = p.telegram_id AND newer.id > p.id;
+
+ALTER TABLE pending_users
+ADD CONSTRAINT pending_users_telegram_id_key UNIQUE (telegram_id);
+
+-- ОГРН организации (13 цифр) или ОГРНИП индивидуального предпринимателя (15 цифр)
+ALTER TABLE pending_users
+ADD COLUMN ogrn VARCHAR(15);
+
+ALTER TABLE users
+ALTER COLUMN ogrn TYPE VARCHAR(15);
+
+CREATE INDEX idx_pending_users_inn ON pending_users(inn);
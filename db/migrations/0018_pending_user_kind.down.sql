@@ -0,0 +1,2 @@
+ALTER TABLE pending_users
+DROP COLUMN IF EXISTS kind;
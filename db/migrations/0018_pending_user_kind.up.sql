@@ -0,0 +1,4 @@
+-- Вид заявки: регистрация нового поставщика или изменение реквизитов
+-- (название организации, ИНН, ОГРН) уже зарегистрированного
+ALTER TABLE pending_users
+ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'registration';
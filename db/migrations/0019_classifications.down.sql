@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN classification VARCHAR(255);
+
+UPDATE users u
+SET classification = uc.codes
+FROM (
+    SELECT user_id, string_agg(classification_code, ',' ORDER BY classification_code) AS codes
+    FROM user_classifications
+    GROUP BY user_id
+) uc
+WHERE uc.user_id = u.telegram_id;
+
+DROP TABLE IF EXISTS user_classifications;
+DROP TABLE IF EXISTS classifications;
+DROP SEQUENCE IF EXISTS classification_code_seq;
@@ -0,0 +1,53 @@
+-- Справочник классификаций вместо списка в коде. Коды 1–21 сохраняются, чтобы
+-- лоты, тендеры и шаблоны продолжали ссылаться на них; новые коды выдаёт последовательность
+CREATE SEQUENCE classification_code_seq START 22;
+
+CREATE TABLE classifications (
+    code VARCHAR(16) PRIMARY KEY DEFAULT nextval('classification_code_seq')::text,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    position INTEGER NOT NULL,
+    archived BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER SEQUENCE classification_code_seq OWNED BY classifications.code;
+
+INSERT INTO classifications (code, name, position) VALUES
+    ('1', 'Сантехника', 1),
+    ('2', 'Вентиляция и кондиционирование', 2),
+    ('3', 'Отопление', 3),
+    ('4', 'Освещение', 4),
+    ('5', 'Розетки/выключатели', 5),
+    ('6', 'Камень натуральный', 6),
+    ('7', 'Керамогранит', 7),
+    ('8', 'Краска', 8),
+    ('9', 'Декоративная штукатурка', 9),
+    ('10', 'Стеклянные перегородки и зеркала', 10),
+    ('11', 'Двери', 11),
+    ('12', 'Мебель индивидуального изготовления', 12),
+    ('13', 'Мебель', 13),
+    ('14', 'Портьеры', 14),
+    ('15', 'Постельное белье', 15),
+    ('16', 'Декор', 16),
+    ('17', 'Обои', 17),
+    ('18', 'Камины', 18),
+    ('19', 'Посуда', 19),
+    ('20', 'Озеленение', 20),
+    ('21', 'Ковры', 21);
+
+-- Классификации поставщика вместо строки кодов через запятую в users.classification
+CREATE TABLE user_classifications (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    classification_code VARCHAR(16) NOT NULL REFERENCES classifications(code),
+    PRIMARY KEY (user_id, classification_code)
+);
+
+CREATE INDEX idx_user_classifications_code ON user_classifications(classification_code);
+
+INSERT INTO user_classifications (user_id, classification_code)
+SELECT DISTINCT u.telegram_id, trim(code)
+FROM users u
+CROSS JOIN LATERAL unnest(string_to_array(u.classification, ',')) AS code
+WHERE trim(code) IN (SELECT c.code FROM classifications c);
+
+ALTER TABLE users DROP COLUMN classification;
@@ -0,0 +1,9 @@
+ALTER TABLE pending_users ALTER COLUMN classification TYPE VARCHAR(255) USING left(classification, 255);
+
+ALTER TABLE tender_templates DROP COLUMN IF EXISTS classifications;
+
+DROP TABLE IF EXISTS tender_classifications;
+
+-- Группы остаются в справочнике обычными классификациями
+DROP INDEX IF EXISTS idx_classifications_parent;
+ALTER TABLE classifications DROP COLUMN IF EXISTS parent_code;
@@ -0,0 +1,56 @@
+-- Двухуровневое дерево классификаций: у подкатегории есть родительская группа
+ALTER TABLE classifications
+    ADD COLUMN parent_code VARCHAR(16) REFERENCES classifications(code),
+    ADD CONSTRAINT classifications_parent_not_self CHECK (parent_code <> code);
+
+CREATE INDEX idx_classifications_parent ON classifications(parent_code);
+
+-- Группы для исходных категорий. Порядок групп задаёт position, подкатегории
+-- внутри группы сохраняют прежний взаимный порядок
+INSERT INTO classifications (name, position) VALUES
+    ('Инженерные системы', 1),
+    ('Электрика', 2),
+    ('Отделочные материалы', 3),
+    ('Мебель и интерьер', 4)
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE classifications c
+SET parent_code = g.code
+FROM classifications g
+WHERE g.parent_code IS NULL
+AND (
+    (g.name = 'Инженерные системы' AND c.code IN ('1', '2', '3'))
+    OR (g.name = 'Электрика' AND c.code IN ('4', '5'))
+    OR (g.name = 'Отделочные материалы' AND c.code IN ('6', '7', '8', '9', '17'))
+    OR (g.name = 'Мебель и интерьер' AND c.code IN ('10', '11', '12', '13', '14', '15', '16', '18', '19', '21'))
+)
+AND c.code <> g.code;
+
+UPDATE classifications SET position = 5 WHERE code = '20';
+
+-- Классификации тендера: объединение классификаций лотов и дополнительных,
+-- выбранных организатором. Подбор тендеров для поставщиков идёт по этой таблице
+CREATE TABLE tender_classifications (
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    classification_code VARCHAR(16) NOT NULL REFERENCES classifications(code),
+    PRIMARY KEY (tender_id, classification_code)
+);
+
+CREATE INDEX idx_tender_classifications_code ON tender_classifications(classification_code);
+
+INSERT INTO tender_classifications (tender_id, classification_code)
+SELECT DISTINCT l.tender_id, l.classification
+FROM tender_lots l
+JOIN classifications c ON c.code = l.classification;
+
+INSERT INTO tender_classifications (tender_id, classification_code)
+SELECT t.id, t.classification
+FROM tenders t
+JOIN classifications c ON c.code = t.classification
+ON CONFLICT DO NOTHING;
+
+-- Классификации тендера, по которому сохранён шаблон
+ALTER TABLE tender_templates ADD COLUMN classifications VARCHAR(16)[] NOT NULL DEFAULT '{}';
+
+-- Число классификаций поставщика больше не ограничено, коды в заявке могут не уместиться в 255 символов
+ALTER TABLE pending_users ALTER COLUMN classification TYPE TEXT;
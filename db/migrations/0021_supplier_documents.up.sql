@@ -0,0 +1,19 @@
+-- Документы поставщика для проверки администратором: выписка ЕГРЮЛ, свидетельство
+-- СРО, доверенность. Файлы лежат в FILES_DIR, здесь — путь, вид и срок действия.
+-- После решения по заявке pending_user_id обнуляется, документ остаётся у поставщика
+CREATE TABLE supplier_documents (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    pending_user_id INTEGER REFERENCES pending_users(id) ON DELETE SET NULL,
+    kind VARCHAR(32) NOT NULL,
+    file_path VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    expires_at DATE,
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reupload_requested_at TIMESTAMPTZ,
+    expiry_notified_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_supplier_documents_user_id ON supplier_documents(user_id);
+CREATE INDEX idx_supplier_documents_pending_user_id ON supplier_documents(pending_user_id);
+CREATE INDEX idx_supplier_documents_expires_at ON supplier_documents(expires_at) WHERE expiry_notified_at IS NULL;
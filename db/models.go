@@ -8,6 +8,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Classification struct {
+	Code       string             `json:"code"`
+	Name       string             `json:"name"`
+	Position   int32              `json:"position"`
+	Archived   bool               `json:"archived"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ParentCode pgtype.Text        `json:"parent_code"`
+}
+
+type ConversationState struct {
+	UserID    int64              `json:"user_id"`
+	Flow      string             `json:"flow"`
+	Step      int32              `json:"step"`
+	Data      []byte             `json:"data"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type History struct {
 	ID          int32              `json:"id"`
 	TenderID    int32              `json:"tender_id"`
@@ -16,9 +34,11 @@ type History struct {
 	PhoneNumber pgtype.Text        `json:"phone_number"`
 	Inn         pgtype.Text        `json:"inn"`
 	Fio         pgtype.Text        `json:"fio"`
-	Bid         float64            `json:"bid"`
+	Bid         pgtype.Float8      `json:"bid"`
 	StartPrice  float64            `json:"start_price"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	LotID       int32              `json:"lot_id"`
+	Outcome     string             `json:"outcome"`
 }
 
 type PendingUser struct {
@@ -26,10 +46,25 @@ type PendingUser struct {
 	TelegramID       int64              `json:"telegram_id"`
 	OrganizationName pgtype.Text        `json:"organization_name"`
 	Inn              pgtype.Text        `json:"inn"`
+	Ogrn             pgtype.Text        `json:"ogrn"`
 	PhoneNumber      pgtype.Text        `json:"phone_number"`
 	Name             pgtype.Text        `json:"name"`
 	Classification   pgtype.Text        `json:"classification"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	Kind             string             `json:"kind"`
+}
+
+type SupplierDocument struct {
+	ID                  int32              `json:"id"`
+	UserID              int64              `json:"user_id"`
+	PendingUserID       pgtype.Int4        `json:"pending_user_id"`
+	Kind                string             `json:"kind"`
+	FilePath            string             `json:"file_path"`
+	FileName            string             `json:"file_name"`
+	ExpiresAt           pgtype.Date        `json:"expires_at"`
+	UploadedAt          pgtype.Timestamptz `json:"uploaded_at"`
+	ReuploadRequestedAt pgtype.Timestamptz `json:"reupload_requested_at"`
+	ExpiryNotifiedAt    pgtype.Timestamptz `json:"expiry_notified_at"`
 }
 
 type Tender struct {
@@ -47,6 +82,12 @@ type Tender struct {
 	LastBidAt         pgtype.Timestamptz `json:"last_bid_at"`
 	CurrentPrice      float64            `json:"current_price"`
 	MinBidDecrease    float64            `json:"min_bid_decrease"`
+	ClosesAt          pgtype.Timestamptz `json:"closes_at"`
+	MinBidStepType    string             `json:"min_bid_step_type"`
+	OrganizerID       pgtype.Int8        `json:"organizer_id"`
+	Type              string             `json:"type"`
+	EndAt             pgtype.Timestamptz `json:"end_at"`
+	ExtensionMinutes  int32              `json:"extension_minutes"`
 }
 
 type TenderBid struct {
@@ -55,6 +96,37 @@ type TenderBid struct {
 	UserID   int64              `json:"user_id"`
 	Amount   float64            `json:"amount"`
 	BidTime  pgtype.Timestamptz `json:"bid_time"`
+	LotID    int32              `json:"lot_id"`
+}
+
+type TenderClassification struct {
+	TenderID           int32  `json:"tender_id"`
+	ClassificationCode string `json:"classification_code"`
+}
+
+type TenderEvent struct {
+	ID         int32              `json:"id"`
+	TenderID   int32              `json:"tender_id"`
+	FromStatus string             `json:"from_status"`
+	ToStatus   string             `json:"to_status"`
+	ActorID    pgtype.Int8        `json:"actor_id"`
+	Reason     string             `json:"reason"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type TenderLot struct {
+	ID             int32              `json:"id"`
+	TenderID       int32              `json:"tender_id"`
+	Number         int32              `json:"number"`
+	Title          string             `json:"title"`
+	StartPrice     float64            `json:"start_price"`
+	CurrentPrice   float64            `json:"current_price"`
+	MinBidDecrease float64            `json:"min_bid_decrease"`
+	MinBidStepType string             `json:"min_bid_step_type"`
+	Classification pgtype.Text        `json:"classification"`
+	Status         string             `json:"status"`
+	LastBidAt      pgtype.Timestamptz `json:"last_bid_at"`
+	ClosesAt       pgtype.Timestamptz `json:"closes_at"`
 }
 
 type TenderParticipant struct {
@@ -62,6 +134,42 @@ type TenderParticipant struct {
 	TenderID int32              `json:"tender_id"`
 	UserID   int64              `json:"user_id"`
 	JoinedAt pgtype.Timestamptz `json:"joined_at"`
+	Status   string             `json:"status"`
+}
+
+type TenderReview struct {
+	ID         int32              `json:"id"`
+	TenderID   int32              `json:"tender_id"`
+	Round      int32              `json:"round"`
+	Decision   string             `json:"decision"`
+	Reason     string             `json:"reason"`
+	ReviewerID pgtype.Int8        `json:"reviewer_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type TenderTemplate struct {
+	ID               int32              `json:"id"`
+	OrganizerID      int64              `json:"organizer_id"`
+	Name             string             `json:"name"`
+	Title            string             `json:"title"`
+	Description      pgtype.Text        `json:"description"`
+	Type             string             `json:"type"`
+	DurationMinutes  pgtype.Int4        `json:"duration_minutes"`
+	ExtensionMinutes int32              `json:"extension_minutes"`
+	ConditionsPath   pgtype.Text        `json:"conditions_path"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	Classifications  []string           `json:"classifications"`
+}
+
+type TenderTemplateLot struct {
+	ID             int32       `json:"id"`
+	TemplateID     int32       `json:"template_id"`
+	Number         int32       `json:"number"`
+	Title          string      `json:"title"`
+	StartPrice     float64     `json:"start_price"`
+	MinBidDecrease float64     `json:"min_bid_decrease"`
+	MinBidStepType string      `json:"min_bid_step_type"`
+	Classification pgtype.Text `json:"classification"`
 }
 
 type User struct {
@@ -70,8 +178,25 @@ type User struct {
 	Inn              pgtype.Text `json:"inn"`
 	Ogrn             pgtype.Text `json:"ogrn"`
 	PhoneNumber      pgtype.Text `json:"phone_number"`
-	Classification   pgtype.Text `json:"classification"`
 	Role             string      `json:"role"`
 	Banned           pgtype.Bool `json:"banned"`
 	Name             pgtype.Text `json:"name"`
 }
+
+type UserClassification struct {
+	UserID             int64  `json:"user_id"`
+	ClassificationCode string `json:"classification_code"`
+}
+
+type WinnerOffer struct {
+	ID          int32              `json:"id"`
+	TenderID    int32              `json:"tender_id"`
+	LotID       int32              `json:"lot_id"`
+	UserID      int64              `json:"user_id"`
+	Amount      float64            `json:"amount"`
+	Rank        int32              `json:"rank"`
+	Status      string             `json:"status"`
+	OfferedAt   pgtype.Timestamptz `json:"offered_at"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	RespondedAt pgtype.Timestamptz `json:"responded_at"`
+}
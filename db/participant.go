@@ -0,0 +1,13 @@
+package db
+
+// Статус участия поставщика в тендере (tender_participants.status): тендер идёт,
+// поставщик победил хотя бы по одному лоту, проиграл, отказался от участия,
+// не сделал ни одной ставки или тендер отменён организатором
+const (
+	ParticipantStatusActive    = "active"
+	ParticipantStatusWon       = "won"
+	ParticipantStatusLost      = "lost"
+	ParticipantStatusWithdrew  = "withdrew"
+	ParticipantStatusNoBid     = "no_bid"
+	ParticipantStatusCancelled = "cancelled"
+)
@@ -7,8 +7,41 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cancelParticipants = `-- name: CancelParticipants :exec
+UPDATE tender_participants SET status = 'cancelled'
+WHERE tender_id = $1 AND status = 'active'
+`
+
+func (q *Queries) CancelParticipants(ctx context.Context, tenderID int32) error {
+	_, err := q.db.Exec(ctx, cancelParticipants, tenderID)
+	return err
+}
+
+const finishParticipants = `-- name: FinishParticipants :exec
+UPDATE tender_participants tp
+SET status = CASE
+    WHEN EXISTS (
+        SELECT 1 FROM winner_offers o
+        WHERE o.tender_id = tp.tender_id AND o.user_id = tp.user_id AND o.status = 'accepted'
+    ) THEN 'won'
+    WHEN EXISTS (
+        SELECT 1 FROM tender_bids b
+        WHERE b.tender_id = tp.tender_id AND b.user_id = tp.user_id
+    ) THEN 'lost'
+    ELSE 'no_bid'
+END
+WHERE tp.tender_id = $1 AND tp.status = 'active'
+`
+
+func (q *Queries) FinishParticipants(ctx context.Context, tenderID int32) error {
+	_, err := q.db.Exec(ctx, finishParticipants, tenderID)
+	return err
+}
+
 const getParticipantNumber = `-- name: GetParticipantNumber :one
 SELECT COUNT(*) + 1 as participant_number
 FROM tender_participants tp1
@@ -34,7 +67,7 @@ func (q *Queries) GetParticipantNumber(ctx context.Context, arg GetParticipantNu
 
 const getParticipantsForTender = `-- name: GetParticipantsForTender :many
 SELECT user_id FROM tender_participants 
-WHERE tender_id = $1
+WHERE tender_id = $1 AND status = 'active'
 `
 
 func (q *Queries) GetParticipantsForTender(ctx context.Context, tenderID int32) ([]int64, error) {
@@ -57,23 +90,73 @@ func (q *Queries) GetParticipantsForTender(ctx context.Context, tenderID int32)
 	return items, nil
 }
 
-const getTenderFromParticipants = `-- name: GetTenderFromParticipants :one
-select (tender_id) from tender_participants 
-where user_id = $1
+const getSupplierParticipations = `-- name: GetSupplierParticipations :many
+SELECT t.id AS tender_id, t.title AS tender_title, t.status AS tender_status,
+       tp.status AS participation_status, tp.joined_at,
+       l.number AS lot_number, l.title AS lot_title,
+       my.amount AS best_bid,
+       (
+           SELECT COUNT(*) + 1 FROM (
+               SELECT MIN(b.amount) AS amount FROM tender_bids b
+               WHERE b.lot_id = l.id
+               GROUP BY b.user_id
+           ) r
+           WHERE r.amount < my.amount
+       ) AS bid_rank,
+       w.amount AS winning_price
+FROM tender_participants tp
+JOIN tenders t ON t.id = tp.tender_id
+JOIN tender_lots l ON l.tender_id = t.id
+LEFT JOIN (
+    SELECT lot_id, MIN(amount) AS amount FROM tender_bids
+    WHERE user_id = $1
+    GROUP BY lot_id
+) my ON my.lot_id = l.id
+LEFT JOIN winner_offers w ON w.lot_id = l.id AND w.status = 'accepted'
+WHERE tp.user_id = $1 AND tp.status <> 'active'
+ORDER BY tp.joined_at DESC, t.id, l.number
 `
 
-func (q *Queries) GetTenderFromParticipants(ctx context.Context, userID int64) (int32, error) {
-	row := q.db.QueryRow(ctx, getTenderFromParticipants, userID)
-	var tender_id int32
-	err := row.Scan(&tender_id)
-	return tender_id, err
+type GetSupplierParticipationsRow struct {
+	TenderID            int32              `json:"tender_id"`
+	TenderTitle         string             `json:"tender_title"`
+	TenderStatus        string             `json:"tender_status"`
+	ParticipationStatus string             `json:"participation_status"`
+	JoinedAt            pgtype.Timestamptz `json:"joined_at"`
+	LotNumber           int32              `json:"lot_number"`
+	LotTitle            string             `json:"lot_title"`
+	BestBid             pgtype.Float8      `json:"best_bid"`
+	BidRank             int64              `json:"bid_rank"`
+	WinningPrice        pgtype.Float8      `json:"winning_price"`
 }
 
-const removeParticipants = `-- name: RemoveParticipants :exec
-DELETE FROM tender_participants WHERE tender_id = $1
-`
-
-func (q *Queries) RemoveParticipants(ctx context.Context, tenderID int32) error {
-	_, err := q.db.Exec(ctx, removeParticipants, tenderID)
-	return err
+func (q *Queries) GetSupplierParticipations(ctx context.Context, userID int64) ([]GetSupplierParticipationsRow, error) {
+	rows, err := q.db.Query(ctx, getSupplierParticipations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetSupplierParticipationsRow{}
+	for rows.Next() {
+		var i GetSupplierParticipationsRow
+		if err := rows.Scan(
+			&i.TenderID,
+			&i.TenderTitle,
+			&i.TenderStatus,
+			&i.ParticipationStatus,
+			&i.JoinedAt,
+			&i.LotNumber,
+			&i.LotTitle,
+			&i.BestBid,
+			&i.BidRank,
+			&i.WinningPrice,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
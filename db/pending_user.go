@@ -0,0 +1,8 @@
+package db
+
+// Вид заявки в pending_users (pending_users.kind): регистрация нового поставщика
+// или изменение реквизитов уже зарегистрированного, которое тоже одобряет админ
+const (
+	PendingKindRegistration = "registration"
+	PendingKindRequisites   = "requisites"
+)
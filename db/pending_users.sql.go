@@ -25,20 +25,24 @@ INSERT INTO pending_users (
     telegram_id, 
     organization_name, 
     inn, 
+    ogrn,
     phone_number, 
     name, 
     classification,
+    kind,
     created_at
-) VALUES ($1, $2, $3, $4, $5, $6, NOW())
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
 `
 
 type CreatePendingUserParams struct {
 	TelegramID       int64       `json:"telegram_id"`
 	OrganizationName pgtype.Text `json:"organization_name"`
 	Inn              pgtype.Text `json:"inn"`
+	Ogrn             pgtype.Text `json:"ogrn"`
 	PhoneNumber      pgtype.Text `json:"phone_number"`
 	Name             pgtype.Text `json:"name"`
 	Classification   pgtype.Text `json:"classification"`
+	Kind             string      `json:"kind"`
 }
 
 func (q *Queries) CreatePendingUser(ctx context.Context, arg CreatePendingUserParams) error {
@@ -46,15 +50,17 @@ func (q *Queries) CreatePendingUser(ctx context.Context, arg CreatePendingUserPa
 		arg.TelegramID,
 		arg.OrganizationName,
 		arg.Inn,
+		arg.Ogrn,
 		arg.PhoneNumber,
 		arg.Name,
 		arg.Classification,
+		arg.Kind,
 	)
 	return err
 }
 
 const getAllPendingUsers = `-- name: GetAllPendingUsers :many
-SELECT id, telegram_id, organization_name, inn, phone_number, name, classification, created_at FROM pending_users ORDER BY created_at DESC
+SELECT id, telegram_id, organization_name, inn, ogrn, phone_number, name, classification, created_at, kind FROM pending_users ORDER BY created_at DESC
 `
 
 func (q *Queries) GetAllPendingUsers(ctx context.Context) ([]PendingUser, error) {
@@ -71,10 +77,12 @@ func (q *Queries) GetAllPendingUsers(ctx context.Context) ([]PendingUser, error)
 			&i.TelegramID,
 			&i.OrganizationName,
 			&i.Inn,
+			&i.Ogrn,
 			&i.PhoneNumber,
 			&i.Name,
 			&i.Classification,
 			&i.CreatedAt,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -87,7 +95,7 @@ func (q *Queries) GetAllPendingUsers(ctx context.Context) ([]PendingUser, error)
 }
 
 const getPendingUser = `-- name: GetPendingUser :one
-SELECT id, telegram_id, organization_name, inn, phone_number, name, classification, created_at FROM pending_users WHERE telegram_id = $1
+SELECT id, telegram_id, organization_name, inn, ogrn, phone_number, name, classification, created_at, kind FROM pending_users WHERE telegram_id = $1
 `
 
 func (q *Queries) GetPendingUser(ctx context.Context, telegramID int64) (PendingUser, error) {
@@ -98,10 +106,48 @@ func (q *Queries) GetPendingUser(ctx context.Context, telegramID int64) (Pending
 		&i.TelegramID,
 		&i.OrganizationName,
 		&i.Inn,
+		&i.Ogrn,
 		&i.PhoneNumber,
 		&i.Name,
 		&i.Classification,
 		&i.CreatedAt,
+		&i.Kind,
 	)
 	return i, err
 }
+
+const getPendingUserIDByInn = `-- name: GetPendingUserIDByInn :one
+SELECT telegram_id FROM pending_users
+WHERE inn = $1 AND telegram_id <> $2
+LIMIT 1
+`
+
+type GetPendingUserIDByInnParams struct {
+	Inn        pgtype.Text `json:"inn"`
+	TelegramID int64       `json:"telegram_id"`
+}
+
+func (q *Queries) GetPendingUserIDByInn(ctx context.Context, arg GetPendingUserIDByInnParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getPendingUserIDByInn, arg.Inn, arg.TelegramID)
+	var telegram_id int64
+	err := row.Scan(&telegram_id)
+	return telegram_id, err
+}
+
+const getPendingUserIDByOgrn = `-- name: GetPendingUserIDByOgrn :one
+SELECT telegram_id FROM pending_users
+WHERE ogrn = $1 AND telegram_id <> $2
+LIMIT 1
+`
+
+type GetPendingUserIDByOgrnParams struct {
+	Ogrn       pgtype.Text `json:"ogrn"`
+	TelegramID int64       `json:"telegram_id"`
+}
+
+func (q *Queries) GetPendingUserIDByOgrn(ctx context.Context, arg GetPendingUserIDByOgrnParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getPendingUserIDByOgrn, arg.Ogrn, arg.TelegramID)
+	var telegram_id int64
+	err := row.Scan(&telegram_id)
+	return telegram_id, err
+}
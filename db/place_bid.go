@@ -0,0 +1,264 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Тип шага понижения ставки (tenders.min_bid_step_type): min_bid_decrease
+// хранит либо сумму в рублях, либо процент от текущей цены
+const (
+	BidStepAmount  = "amount"
+	BidStepPercent = "percent"
+)
+
+// Тип тендера (tenders.type): открытый аукцион на понижение, где ставки видны
+// всем участникам, или закрытый — одно скрытое предложение от участника до end_at
+const (
+	TenderTypeOpen   = "open"
+	TenderTypeSealed = "sealed"
+)
+
+// BidRejection — причина, по которой ставка не была принята
+type BidRejection int
+
+const (
+	BidAccepted BidRejection = iota
+	BidRejectedTenderNotFound
+	BidRejectedTenderNotActive
+	BidRejectedNotParticipant
+	BidRejectedInvalidAmount
+	BidRejectedStepViolation
+	BidRejectedAlreadyBid
+	BidRejectedLotClosed
+)
+
+// Статус лота (tender_lots.status): по лоту идут торги, торги окончены и ждут
+// подтверждения победителя, они завершены с победителем, не состоялись или тендер отменён
+const (
+	LotStatusOpen       = "open"
+	LotStatusConfirming = "confirming"
+	LotStatusCompleted  = "completed"
+	LotStatusFailed     = "failed"
+	LotStatusCancelled  = "cancelled"
+)
+
+type PlaceBidParams struct {
+	TenderID int32
+	LotID    int32
+	UserID   int64
+	Amount   float64
+	BidTime  time.Time
+	// ClosesAt — новый срок завершения лота, если ставка будет принята.
+	// Для закрытого тендера не используется: срок фиксирован (end_at)
+	ClosesAt time.Time
+}
+
+type PlaceBidResult struct {
+	Rejection BidRejection
+	// Tender и Lot — состояние тендера и лота после ставки или на момент отказа
+	Tender Tender
+	Lot    TenderLot
+	Bid    TenderBid
+	// NextAllowedBid — максимальная сумма ставки, которую лот примет сейчас
+	NextAllowedBid float64
+	// EndAtExtended — ставка пришлась на окно антиснайпинга и продлила срок тендера
+	EndAtExtended bool
+}
+
+// BidStep возвращает минимальное понижение ставки в рублях при текущей цене лота
+func BidStep(lot TenderLot) float64 {
+	if lot.MinBidStepType == BidStepPercent {
+		return lot.CurrentPrice * lot.MinBidDecrease / 100
+	}
+	return lot.MinBidDecrease
+}
+
+// NextAllowedBid возвращает максимальную допустимую ставку по лоту: current_price - шаг.
+// Это единое правило шага для проверки в боте и при сохранении ставки.
+// В закрытом тендере шага нет: предложение не должно превышать стартовую цену лота.
+func NextAllowedBid(tender Tender, lot TenderLot) float64 {
+	if tender.Type == TenderTypeSealed {
+		return lot.StartPrice
+	}
+	next := lot.CurrentPrice - BidStep(lot)
+	// Округляем вниз до копеек, чтобы показанная сумма точно проходила проверку.
+	// Допуск не даёт погрешности float (1360.36 - 50 = 1310.3599…) отнять лишнюю копейку.
+	return math.Floor(next*100+1e-6) / 100
+}
+
+type txStarter interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// PlaceBid атомарно принимает ставку по лоту: блокирует строки тендера и лота,
+// проверяет статус, участие и шаг понижения, сохраняет ставку и обновляет
+// текущую цену и срок завершения лота. Если ставка не прошла проверку,
+// возвращается результат с причиной отказа и без ошибки.
+func (q *Queries) PlaceBid(ctx context.Context, arg PlaceBidParams) (PlaceBidResult, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return PlaceBidResult{}, fmt.Errorf("place bid: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	tender, err := qtx.GetTenderForUpdate(ctx, arg.TenderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PlaceBidResult{Rejection: BidRejectedTenderNotFound}, nil
+	}
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+
+	lot, err := qtx.GetTenderLotForUpdate(ctx, arg.LotID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && lot.TenderID != arg.TenderID) {
+		return PlaceBidResult{Rejection: BidRejectedTenderNotFound, Tender: tender}, nil
+	}
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+
+	result := PlaceBidResult{
+		Tender:         tender,
+		Lot:            lot,
+		NextAllowedBid: NextAllowedBid(tender, lot),
+	}
+
+	if tender.Status != "active" {
+		result.Rejection = BidRejectedTenderNotActive
+		return result, nil
+	}
+
+	if lot.Status != LotStatusOpen {
+		result.Rejection = BidRejectedLotClosed
+		return result, nil
+	}
+
+	// После end_at ставки не принимаются, даже если торги ещё не закрыты фоновой задачей
+	sealed := tender.Type == TenderTypeSealed
+	if tender.EndAt.Valid && !arg.BidTime.Before(tender.EndAt.Time) {
+		result.Rejection = BidRejectedTenderNotActive
+		return result, nil
+	}
+
+	isParticipating, err := qtx.CheckTenderParticipation(ctx, CheckTenderParticipationParams{
+		TenderID: arg.TenderID,
+		UserID:   arg.UserID,
+	})
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+	if !isParticipating {
+		result.Rejection = BidRejectedNotParticipant
+		return result, nil
+	}
+
+	if arg.Amount <= 0 {
+		result.Rejection = BidRejectedInvalidAmount
+		return result, nil
+	}
+
+	if arg.Amount > result.NextAllowedBid {
+		result.Rejection = BidRejectedStepViolation
+		return result, nil
+	}
+
+	// В закрытом тендере у участника одно предложение на каждый лот
+	if sealed {
+		bidCount, err := qtx.GetUserLotBidCount(ctx, GetUserLotBidCountParams{
+			LotID:  arg.LotID,
+			UserID: arg.UserID,
+		})
+		if err != nil {
+			return PlaceBidResult{}, err
+		}
+		if bidCount > 0 {
+			result.Rejection = BidRejectedAlreadyBid
+			return result, nil
+		}
+	}
+
+	bid, err := qtx.CreateBid(ctx, CreateBidParams{
+		TenderID: arg.TenderID,
+		UserID:   arg.UserID,
+		Amount:   arg.Amount,
+		BidTime:  pgtype.Timestamptz{Time: arg.BidTime, Valid: true},
+		LotID:    arg.LotID,
+	})
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+
+	// Предложения закрытого тендера скрыты: текущая цена и срок не меняются
+	if sealed {
+		if err := tx.Commit(ctx); err != nil {
+			return PlaceBidResult{}, err
+		}
+		return PlaceBidResult{
+			Rejection:      BidAccepted,
+			Tender:         tender,
+			Lot:            lot,
+			Bid:            bid,
+			NextAllowedBid: NextAllowedBid(tender, lot),
+		}, nil
+	}
+
+	lot, err = qtx.UpdateLotAfterBid(ctx, UpdateLotAfterBidParams{
+		ID:           arg.LotID,
+		CurrentPrice: arg.Amount,
+		LastBidAt:    pgtype.Timestamptz{Time: arg.BidTime, Valid: true},
+		ClosesAt:     pgtype.Timestamptz{Time: arg.ClosesAt, Valid: true},
+	})
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+
+	// Текущая цена тендера — сумма текущих цен его лотов
+	tender, err = qtx.UpdateTenderAfterBid(ctx, UpdateTenderAfterBidParams{
+		ID:        arg.TenderID,
+		LastBidAt: pgtype.Timestamptz{Time: arg.BidTime, Valid: true},
+	})
+	if err != nil {
+		return PlaceBidResult{}, err
+	}
+
+	// Антиснайпинг: ставка незадолго до end_at продлевает срок тендера
+	window := time.Duration(tender.ExtensionMinutes) * time.Minute
+	extended := false
+	if tender.EndAt.Valid && window > 0 && tender.EndAt.Time.Sub(arg.BidTime) < window {
+		tender, err = qtx.ExtendTenderEndAt(ctx, ExtendTenderEndAtParams{
+			ID:    arg.TenderID,
+			EndAt: pgtype.Timestamptz{Time: arg.BidTime.Add(window), Valid: true},
+		})
+		if err != nil {
+			return PlaceBidResult{}, err
+		}
+		extended = true
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PlaceBidResult{}, err
+	}
+
+	return PlaceBidResult{
+		Rejection:      BidAccepted,
+		Tender:         tender,
+		Lot:            lot,
+		Bid:            bid,
+		NextAllowedBid: NextAllowedBid(tender, lot),
+		EndAtExtended:  extended,
+	}, nil
+}
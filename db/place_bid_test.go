@@ -0,0 +1,27 @@
+package db
+
+import "testing"
+
+func TestNextAllowedBid(t *testing.T) {
+	tests := []struct {
+		name       string
+		tenderType string
+		lot        TenderLot
+		want       float64
+	}{
+		{"фиксированный шаг", TenderTypeOpen, TenderLot{StartPrice: 1000, CurrentPrice: 1000, MinBidDecrease: 50, MinBidStepType: BidStepAmount}, 950},
+		{"процентный шаг", TenderTypeOpen, TenderLot{StartPrice: 1000, CurrentPrice: 800, MinBidDecrease: 5, MinBidStepType: BidStepPercent}, 760},
+		{"процентный шаг вниз до копеек", TenderTypeOpen, TenderLot{StartPrice: 200, CurrentPrice: 100.05, MinBidDecrease: 1, MinBidStepType: BidStepPercent}, 99.04},
+		{"фиксированный шаг без погрешности float", TenderTypeOpen, TenderLot{StartPrice: 2000, CurrentPrice: 1360.36, MinBidDecrease: 50, MinBidStepType: BidStepAmount}, 1310.36},
+		{"процентный шаг без погрешности float", TenderTypeOpen, TenderLot{StartPrice: 2000, CurrentPrice: 1000.1, MinBidDecrease: 10, MinBidStepType: BidStepPercent}, 900.09},
+		{"закрытый тендер — стартовая цена", TenderTypeSealed, TenderLot{StartPrice: 1000, CurrentPrice: 800, MinBidDecrease: 50, MinBidStepType: BidStepAmount}, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextAllowedBid(Tender{Type: tt.tenderType}, tt.lot); got != tt.want {
+				t.Errorf("NextAllowedBid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
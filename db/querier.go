@@ -11,50 +11,130 @@ import (
 )
 
 type Querier interface {
-	ActivatePendingTenders(ctx context.Context) error
+	AddTenderClassifications(ctx context.Context, arg AddTenderClassificationsParams) error
+	AddTenderEvent(ctx context.Context, arg AddTenderEventParams) error
+	AddTenderReview(ctx context.Context, arg AddTenderReviewParams) (TenderReview, error)
 	AddToHistory(ctx context.Context, arg AddToHistoryParams) error
+	AddUserClassifications(ctx context.Context, arg AddUserClassificationsParams) error
 	ApprovePendingUser(ctx context.Context, telegramID int64) error
-	ApproveTender(ctx context.Context, id int32) error
 	BlockUser(ctx context.Context, telegramID int64) error
+	CancelParticipants(ctx context.Context, tenderID int32) error
+	CancelTenderLots(ctx context.Context, tenderID int32) error
+	CancelTenderWinnerOffers(ctx context.Context, tenderID int32) error
 	CheckBidExists(ctx context.Context, arg CheckBidExistsParams) (int64, error)
 	CheckTenderParticipation(ctx context.Context, arg CheckTenderParticipationParams) (bool, error)
 	CheckUserHasAnyTenderParticipation(ctx context.Context, arg CheckUserHasAnyTenderParticipationParams) (bool, error)
-	CreateBid(ctx context.Context, arg CreateBidParams) error
+	CloseLot(ctx context.Context, arg CloseLotParams) (int64, error)
+	CompleteConfirmingLot(ctx context.Context, id int32) (int64, error)
+	CountAdmins(ctx context.Context) (int64, error)
+	CountCompletedLots(ctx context.Context, tenderID int32) (int64, error)
+	CountUnfinishedLots(ctx context.Context, tenderID int32) (int64, error)
+	CreateBid(ctx context.Context, arg CreateBidParams) (TenderBid, error)
+	CreateClassification(ctx context.Context, arg CreateClassificationParams) (Classification, error)
 	CreatePendingUser(ctx context.Context, arg CreatePendingUserParams) error
+	CreateSupplierDocument(ctx context.Context, arg CreateSupplierDocumentParams) (SupplierDocument, error)
 	CreateTender(ctx context.Context, arg CreateTenderParams) (Tender, error)
+	CreateTenderLot(ctx context.Context, arg CreateTenderLotParams) (TenderLot, error)
+	CreateTenderTemplate(ctx context.Context, arg CreateTenderTemplateParams) (TenderTemplate, error)
+	CreateTenderTemplateLot(ctx context.Context, arg CreateTenderTemplateLotParams) (TenderTemplateLot, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteTender(ctx context.Context, id int32) error
+	CreateWinnerOffer(ctx context.Context, arg CreateWinnerOfferParams) (WinnerOffer, error)
+	DeleteConversationState(ctx context.Context, arg DeleteConversationStateParams) error
+	DeleteExpiredConversationStates(ctx context.Context) error
+	DeleteOrganizerTemplate(ctx context.Context, arg DeleteOrganizerTemplateParams) (int64, error)
+	DeleteOrganizerTender(ctx context.Context, arg DeleteOrganizerTenderParams) (int64, error)
+	DeletePendingUserDocuments(ctx context.Context, pendingUserID pgtype.Int4) ([]string, error)
+	DeleteTender(ctx context.Context, id int32) (int64, error)
+	DeleteTenderClassifications(ctx context.Context, tenderID int32) error
+	DeleteTenderLots(ctx context.Context, tenderID int32) error
+	DeleteUserClassifications(ctx context.Context, userID int64) error
 	DropDb(ctx context.Context) error
+	ExtendTenderEndAt(ctx context.Context, arg ExtendTenderEndAtParams) (Tender, error)
+	FinishParticipants(ctx context.Context, tenderID int32) error
+	GetActiveTendersForParticipant(ctx context.Context, userID int64) ([]Tender, error)
 	GetAllPendingUsers(ctx context.Context) ([]PendingUser, error)
 	GetAllUsers(ctx context.Context) ([]User, error)
 	GetBidsAfterTime(ctx context.Context, arg GetBidsAfterTimeParams) ([]TenderBid, error)
+	GetBidsHistoryByLotID(ctx context.Context, lotID int32) ([]GetBidsHistoryByLotIDRow, error)
 	GetBidsHistoryByTenderID(ctx context.Context, tenderID int32) ([]GetBidsHistoryByTenderIDRow, error)
+	GetClassification(ctx context.Context, code string) (Classification, error)
+	GetConversationState(ctx context.Context, arg GetConversationStateParams) (ConversationState, error)
+	GetExpiredTenders(ctx context.Context) ([]Tender, error)
+	GetExpiredWinnerOffers(ctx context.Context) ([]WinnerOffer, error)
+	GetExpiringSupplierDocuments(ctx context.Context, expiresAt pgtype.Date) ([]SupplierDocument, error)
 	GetHistory(ctx context.Context) ([]Tender, error)
+	GetLastTenderReview(ctx context.Context, tenderID int32) (TenderReview, error)
+	GetLotBidRanking(ctx context.Context, lotID int32) ([]GetLotBidRankingRow, error)
+	GetLotWinnerOffers(ctx context.Context, lotID int32) ([]WinnerOffer, error)
+	GetLowestLotBid(ctx context.Context, lotID int32) (TenderBid, error)
+	GetOpenLotsWithDeadline(ctx context.Context) ([]TenderLot, error)
+	GetOpenTenderLots(ctx context.Context, tenderID int32) ([]TenderLot, error)
+	GetOrganizerTemplates(ctx context.Context, organizerID int64) ([]TenderTemplate, error)
+	GetOrganizerTenders(ctx context.Context, organizerID pgtype.Int8) ([]Tender, error)
+	GetOrganizerTendersHistory(ctx context.Context, organizerID pgtype.Int8) ([]History, error)
 	GetParticipantNumber(ctx context.Context, arg GetParticipantNumberParams) (int32, error)
 	GetParticipantsForTender(ctx context.Context, tenderID int32) ([]int64, error)
 	GetPendingUser(ctx context.Context, telegramID int64) (PendingUser, error)
+	GetPendingUserDocuments(ctx context.Context, pendingUserID pgtype.Int4) ([]SupplierDocument, error)
+	GetPendingUserIDByInn(ctx context.Context, arg GetPendingUserIDByInnParams) (int64, error)
+	GetPendingUserIDByOgrn(ctx context.Context, arg GetPendingUserIDByOgrnParams) (int64, error)
 	GetStartingTenders(ctx context.Context) ([]GetStartingTendersRow, error)
+	GetSupplierDocument(ctx context.Context, id int32) (SupplierDocument, error)
+	GetSupplierParticipations(ctx context.Context, userID int64) ([]GetSupplierParticipationsRow, error)
 	GetTender(ctx context.Context, id int32) (Tender, error)
 	GetTenderById(ctx context.Context, id int32) (Tender, error)
-	GetTenderFromParticipants(ctx context.Context, userID int64) (int32, error)
+	GetTenderClassifications(ctx context.Context, tenderID int32) ([]Classification, error)
+	GetTenderEvents(ctx context.Context, tenderID int32) ([]TenderEvent, error)
+	GetTenderForUpdate(ctx context.Context, id int32) (Tender, error)
+	GetTenderLot(ctx context.Context, id int32) (TenderLot, error)
+	GetTenderLotForUpdate(ctx context.Context, id int32) (TenderLot, error)
+	GetTenderLots(ctx context.Context, tenderID int32) ([]TenderLot, error)
+	GetTenderReviews(ctx context.Context, tenderID int32) ([]TenderReview, error)
+	GetTenderTemplate(ctx context.Context, id int32) (TenderTemplate, error)
+	GetTenderTemplateLots(ctx context.Context, templateID int32) ([]TenderTemplateLot, error)
 	GetTenders(ctx context.Context) ([]Tender, error)
 	GetTendersForDeletion(ctx context.Context) ([]Tender, error)
-	GetTendersForSuppliers(ctx context.Context, arg GetTendersForSuppliersParams) ([]Tender, error)
+	GetTendersForSuppliers(ctx context.Context, userID int64) ([]Tender, error)
 	GetTendersHistory(ctx context.Context) ([]History, error)
 	GetTendersStartingIn10Minutes(ctx context.Context) ([]GetTendersStartingIn10MinutesRow, error)
+	GetTendersToActivate(ctx context.Context) ([]Tender, error)
 	GetUserBidCount(ctx context.Context, arg GetUserBidCountParams) (int64, error)
+	GetUserBidsForLot(ctx context.Context, arg GetUserBidsForLotParams) ([]TenderBid, error)
 	GetUserBidsForTender(ctx context.Context, arg GetUserBidsForTenderParams) ([]TenderBid, error)
 	GetUserByTelegramID(ctx context.Context, telegramID int64) (User, error)
-	GetUsersByClassification(ctx context.Context, classification pgtype.Text) ([]int64, error)
+	GetUserClassifications(ctx context.Context, userID int64) ([]Classification, error)
+	GetUserIDByInn(ctx context.Context, arg GetUserIDByInnParams) (int64, error)
+	GetUserIDByOgrn(ctx context.Context, arg GetUserIDByOgrnParams) (int64, error)
+	GetUserIDsByRole(ctx context.Context, role string) ([]int64, error)
+	GetUserLotBidCount(ctx context.Context, arg GetUserLotBidCountParams) (int64, error)
+	GetUsersByTenderClassifications(ctx context.Context, tenderID int32) ([]int64, error)
+	GetWinnerOffer(ctx context.Context, id int32) (WinnerOffer, error)
+	IsConditionsFileInUse(ctx context.Context, conditionsPath pgtype.Text) (bool, error)
 	JoinTender(ctx context.Context, arg JoinTenderParams) error
 	LeaveTender(ctx context.Context, arg LeaveTenderParams) error
+	ListClassifications(ctx context.Context) ([]Classification, error)
+	MarkSupplierDocumentExpiryNotified(ctx context.Context, id int32) error
 	MessageSent(ctx context.Context, id int32) error
-	RemoveParticipants(ctx context.Context, tenderID int32) error
+	RenameClassification(ctx context.Context, arg RenameClassificationParams) (Classification, error)
+	ReplaceSupplierDocument(ctx context.Context, arg ReplaceSupplierDocumentParams) (SupplierDocument, error)
+	RequestSupplierDocumentReupload(ctx context.Context, id int32) error
+	RespondWinnerOffer(ctx context.Context, arg RespondWinnerOfferParams) (int64, error)
+	SetClassificationArchived(ctx context.Context, arg SetClassificationArchivedParams) (Classification, error)
+	SetClassificationParent(ctx context.Context, arg SetClassificationParentParams) (Classification, error)
+	SetLotStatus(ctx context.Context, arg SetLotStatusParams) error
+	SetSealedTenderDeadlines(ctx context.Context, id int32) error
+	SwapClassificationPositions(ctx context.Context, arg SwapClassificationPositionsParams) error
 	TimeZone(ctx context.Context) (string, error)
 	UnblockUser(ctx context.Context, telegramID int64) error
-	UpdateTenderCurrentPrice(ctx context.Context, arg UpdateTenderCurrentPriceParams) error
-	UpdateTenderStatus(ctx context.Context, arg UpdateTenderStatusParams) error
+	UpdateLotAfterBid(ctx context.Context, arg UpdateLotAfterBidParams) (TenderLot, error)
+	UpdateTenderAfterBid(ctx context.Context, arg UpdateTenderAfterBidParams) (Tender, error)
+	UpdateTenderDetails(ctx context.Context, arg UpdateTenderDetailsParams) (Tender, error)
+	UpdateTenderDraft(ctx context.Context, arg UpdateTenderDraftParams) (Tender, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) error
+	UpdateUserRequisites(ctx context.Context, arg UpdateUserRequisitesParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error
+	UpsertConversationState(ctx context.Context, arg UpsertConversationStateParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -3,19 +3,24 @@ SELECT * FROM tender_bids
 WHERE tender_id = $1 AND user_id = $2 
 ORDER BY bid_time DESC;
 
--- name: CreateBid :exec
-INSERT INTO tender_bids (tender_id, user_id, amount, bid_time) 
-VALUES ($1, $2, $3, $4);
+-- name: GetUserBidsForLot :many
+SELECT * FROM tender_bids
+WHERE lot_id = $1 AND user_id = $2
+ORDER BY bid_time DESC;
+
+-- name: CreateBid :one
+INSERT INTO tender_bids (tender_id, user_id, amount, bid_time, lot_id) 
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
 
 -- name: GetUserBidCount :one
 SELECT COUNT(*) FROM tender_bids
 WHERE tender_id = $1 AND user_id = $2;
 
+-- name: GetUserLotBidCount :one
+SELECT COUNT(*) FROM tender_bids
+WHERE lot_id = $1 AND user_id = $2;
 
--- name: UpdateTenderCurrentPrice :exec
-UPDATE tenders 
-SET current_price = $2
-WHERE id = $1;
 
 -- name: GetBidsAfterTime :many
 SELECT * FROM tender_bids 
@@ -27,16 +32,33 @@ ORDER BY bid_time DESC;
 SELECT 
     b.amount,
     b.bid_time,
-    u.organization_name
+    u.organization_name,
+    b.lot_id
 FROM tender_bids b
 JOIN users u ON b.user_id = u.telegram_id
 WHERE b.tender_id = $1
 ORDER BY b.bid_time ASC;
 
+-- name: GetBidsHistoryByLotID :many
+SELECT 
+    b.amount,
+    b.bid_time,
+    u.organization_name
+FROM tender_bids b
+JOIN users u ON b.user_id = u.telegram_id
+WHERE b.lot_id = $1
+ORDER BY b.bid_time ASC;
+
 
 
 
 -- name: CheckBidExists :one
 SELECT COUNT(*) as count
 FROM tender_bids 
-WHERE tender_id = $1 AND amount = $2;
\ No newline at end of file
+WHERE tender_id = $1 AND amount = $2;
+
+-- name: GetLowestLotBid :one
+SELECT * FROM tender_bids
+WHERE lot_id = $1
+ORDER BY amount ASC, bid_time ASC
+LIMIT 1;
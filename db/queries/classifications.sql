@@ -0,0 +1,64 @@
+-- name: ListClassifications :many
+SELECT * FROM classifications
+ORDER BY position, code;
+
+-- name: GetClassification :one
+SELECT * FROM classifications
+WHERE code = $1;
+
+-- name: CreateClassification :one
+INSERT INTO classifications (name, parent_code, position)
+VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM classifications))
+RETURNING *;
+
+-- name: RenameClassification :one
+UPDATE classifications SET name = $2
+WHERE code = $1
+RETURNING *;
+
+-- name: SetClassificationArchived :one
+UPDATE classifications SET archived = $2
+WHERE code = $1
+RETURNING *;
+
+-- name: SwapClassificationPositions :exec
+UPDATE classifications c
+SET position = CASE WHEN c.code = $1 THEN other.position ELSE self.position END
+FROM classifications self, classifications other
+WHERE self.code = $1 AND other.code = $2
+AND c.code IN ($1, $2);
+
+-- name: GetUserClassifications :many
+SELECT c.* FROM classifications c
+JOIN user_classifications uc ON uc.classification_code = c.code
+WHERE uc.user_id = $1
+ORDER BY c.position, c.code;
+
+-- name: DeleteUserClassifications :exec
+DELETE FROM user_classifications
+WHERE user_id = $1;
+
+-- name: AddUserClassifications :exec
+INSERT INTO user_classifications (user_id, classification_code)
+SELECT sqlc.arg(user_id), unnest(sqlc.arg(codes)::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: SetClassificationParent :one
+UPDATE classifications SET parent_code = $2
+WHERE code = $1
+RETURNING *;
+
+-- name: GetTenderClassifications :many
+SELECT c.* FROM classifications c
+JOIN tender_classifications tc ON tc.classification_code = c.code
+WHERE tc.tender_id = $1
+ORDER BY c.position, c.code;
+
+-- name: DeleteTenderClassifications :exec
+DELETE FROM tender_classifications
+WHERE tender_id = $1;
+
+-- name: AddTenderClassifications :exec
+INSERT INTO tender_classifications (tender_id, classification_code)
+SELECT sqlc.arg(tender_id), unnest(sqlc.arg(codes)::text[])
+ON CONFLICT DO NOTHING;
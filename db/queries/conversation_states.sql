@@ -0,0 +1,20 @@
+-- name: GetConversationState :one
+SELECT * FROM conversation_states
+WHERE user_id = $1 AND flow = $2 AND expires_at > NOW();
+
+-- name: UpsertConversationState :exec
+INSERT INTO conversation_states (user_id, flow, step, data, expires_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (user_id, flow) DO UPDATE
+SET step = EXCLUDED.step,
+    data = EXCLUDED.data,
+    expires_at = EXCLUDED.expires_at,
+    updated_at = NOW();
+
+-- name: DeleteConversationState :exec
+DELETE FROM conversation_states
+WHERE user_id = $1 AND flow = $2;
+
+-- name: DeleteExpiredConversationStates :exec
+DELETE FROM conversation_states
+WHERE expires_at <= NOW();
@@ -1,6 +1,14 @@
 -- name: AddToHistory :exec
-INSERT INTO history (tender_id, title, winner, phone_number, inn, fio, bid, start_price)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+INSERT INTO history (tender_id, title, winner, phone_number, inn, fio, bid, start_price, lot_id, outcome)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
 
 -- name: GetTendersHistory :many
-SELECT * FROM history ORDER BY created_at ASC;
\ No newline at end of file
+SELECT * FROM history
+WHERE outcome IN ('completed', 'failed')
+ORDER BY created_at ASC;
+
+-- name: GetOrganizerTendersHistory :many
+SELECT h.* FROM history h
+JOIN tenders t ON t.id = h.tender_id
+WHERE t.organizer_id = $1 AND h.outcome IN ('completed', 'failed')
+ORDER BY h.created_at ASC;
@@ -0,0 +1,58 @@
+-- name: CreateTenderLot :one
+INSERT INTO tender_lots (tender_id, number, title, start_price, current_price, min_bid_decrease, min_bid_step_type, classification)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetTenderLots :many
+SELECT * FROM tender_lots
+WHERE tender_id = $1
+ORDER BY number;
+
+-- name: GetOpenTenderLots :many
+SELECT * FROM tender_lots
+WHERE tender_id = $1 AND status = 'open'
+ORDER BY number;
+
+-- name: GetTenderLot :one
+SELECT * FROM tender_lots WHERE id = $1;
+
+-- name: GetTenderLotForUpdate :one
+SELECT * FROM tender_lots WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateLotAfterBid :one
+UPDATE tender_lots
+SET current_price = $2, last_bid_at = $3, closes_at = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: CloseLot :execrows
+UPDATE tender_lots SET status = $2
+WHERE id = $1 AND status = 'open';
+
+-- name: CompleteConfirmingLot :execrows
+UPDATE tender_lots SET status = 'completed'
+WHERE id = $1 AND status = 'confirming';
+
+-- name: SetLotStatus :exec
+UPDATE tender_lots SET status = $2
+WHERE id = $1;
+
+-- name: CancelTenderLots :exec
+UPDATE tender_lots SET status = 'cancelled'
+WHERE tender_id = $1 AND status IN ('open', 'confirming');
+
+-- name: CountUnfinishedLots :one
+SELECT COUNT(*) FROM tender_lots
+WHERE tender_id = $1 AND status IN ('open', 'confirming');
+
+-- name: CountCompletedLots :one
+SELECT COUNT(*) FROM tender_lots
+WHERE tender_id = $1 AND status = 'completed';
+
+-- name: GetOpenLotsWithDeadline :many
+SELECT l.* FROM tender_lots l
+JOIN tenders t ON t.id = l.tender_id
+WHERE t.status = 'active' AND l.status = 'open' AND l.closes_at IS NOT NULL;
+
+-- name: DeleteTenderLots :exec
+DELETE FROM tender_lots WHERE tender_id = $1;
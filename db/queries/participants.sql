@@ -1,13 +1,25 @@
 -- name: GetParticipantsForTender :many
 SELECT user_id FROM tender_participants 
-WHERE tender_id = $1;
+WHERE tender_id = $1 AND status = 'active';
 
--- name: GetTenderFromParticipants :one
-select (tender_id) from tender_participants 
-where user_id = $1;
+-- name: CancelParticipants :exec
+UPDATE tender_participants SET status = 'cancelled'
+WHERE tender_id = $1 AND status = 'active';
 
--- name: RemoveParticipants :exec
-DELETE FROM tender_participants WHERE tender_id = $1;
+-- name: FinishParticipants :exec
+UPDATE tender_participants tp
+SET status = CASE
+    WHEN EXISTS (
+        SELECT 1 FROM winner_offers o
+        WHERE o.tender_id = tp.tender_id AND o.user_id = tp.user_id AND o.status = 'accepted'
+    ) THEN 'won'
+    WHEN EXISTS (
+        SELECT 1 FROM tender_bids b
+        WHERE b.tender_id = tp.tender_id AND b.user_id = tp.user_id
+    ) THEN 'lost'
+    ELSE 'no_bid'
+END
+WHERE tp.tender_id = $1 AND tp.status = 'active';
 
 -- name: GetParticipantNumber :one
 SELECT COUNT(*) + 1 as participant_number
@@ -17,4 +29,30 @@ AND tp1.joined_at < (
     SELECT joined_at 
     FROM tender_participants tp2 
     WHERE tp2.tender_id = $1 AND tp2.user_id = $2
-);
\ No newline at end of file
+);;
+
+-- name: GetSupplierParticipations :many
+SELECT t.id AS tender_id, t.title AS tender_title, t.status AS tender_status,
+       tp.status AS participation_status, tp.joined_at,
+       l.number AS lot_number, l.title AS lot_title,
+       my.amount AS best_bid,
+       (
+           SELECT COUNT(*) + 1 FROM (
+               SELECT MIN(b.amount) AS amount FROM tender_bids b
+               WHERE b.lot_id = l.id
+               GROUP BY b.user_id
+           ) r
+           WHERE r.amount < my.amount
+       ) AS bid_rank,
+       w.amount AS winning_price
+FROM tender_participants tp
+JOIN tenders t ON t.id = tp.tender_id
+JOIN tender_lots l ON l.tender_id = t.id
+LEFT JOIN (
+    SELECT lot_id, MIN(amount) AS amount FROM tender_bids
+    WHERE user_id = $1
+    GROUP BY lot_id
+) my ON my.lot_id = l.id
+LEFT JOIN winner_offers w ON w.lot_id = l.id AND w.status = 'accepted'
+WHERE tp.user_id = $1 AND tp.status <> 'active'
+ORDER BY tp.joined_at DESC, t.id, l.number;
@@ -3,11 +3,13 @@ INSERT INTO pending_users (
     telegram_id, 
     organization_name, 
     inn, 
+    ogrn,
     phone_number, 
     name, 
     classification,
+    kind,
     created_at
-) VALUES ($1, $2, $3, $4, $5, $6, NOW());
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW());
 
 -- name: GetPendingUser :one
 SELECT * FROM pending_users WHERE telegram_id = $1;
@@ -16,4 +18,14 @@ SELECT * FROM pending_users WHERE telegram_id = $1;
 DELETE FROM pending_users WHERE telegram_id = $1;
 
 -- name: GetAllPendingUsers :many
-SELECT * FROM pending_users ORDER BY created_at DESC;
\ No newline at end of file
+SELECT * FROM pending_users ORDER BY created_at DESC;
+
+-- name: GetPendingUserIDByInn :one
+SELECT telegram_id FROM pending_users
+WHERE inn = $1 AND telegram_id <> $2
+LIMIT 1;
+
+-- name: GetPendingUserIDByOgrn :one
+SELECT telegram_id FROM pending_users
+WHERE ogrn = $1 AND telegram_id <> $2
+LIMIT 1;
\ No newline at end of file
@@ -0,0 +1,38 @@
+-- name: CreateSupplierDocument :one
+INSERT INTO supplier_documents (user_id, pending_user_id, kind, file_path, file_name, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetSupplierDocument :one
+SELECT * FROM supplier_documents WHERE id = $1;
+
+-- name: GetPendingUserDocuments :many
+SELECT * FROM supplier_documents
+WHERE pending_user_id = $1
+ORDER BY uploaded_at, id;
+
+-- name: ReplaceSupplierDocument :one
+UPDATE supplier_documents
+SET file_path = $2, file_name = $3, expires_at = $4, uploaded_at = NOW(),
+    reupload_requested_at = NULL, expiry_notified_at = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: RequestSupplierDocumentReupload :exec
+UPDATE supplier_documents SET reupload_requested_at = NOW()
+WHERE id = $1;
+
+-- name: GetExpiringSupplierDocuments :many
+SELECT * FROM supplier_documents
+WHERE expires_at <= $1
+AND expiry_notified_at IS NULL
+ORDER BY expires_at, id;
+
+-- name: MarkSupplierDocumentExpiryNotified :exec
+UPDATE supplier_documents SET expiry_notified_at = NOW()
+WHERE id = $1;
+
+-- name: DeletePendingUserDocuments :many
+DELETE FROM supplier_documents
+WHERE pending_user_id = $1
+RETURNING file_path;
@@ -0,0 +1,8 @@
+-- name: AddTenderEvent :exec
+INSERT INTO tender_events (tender_id, from_status, to_status, actor_id, reason)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetTenderEvents :many
+SELECT * FROM tender_events
+WHERE tender_id = $1
+ORDER BY created_at, id;
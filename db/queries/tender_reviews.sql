@@ -0,0 +1,15 @@
+-- name: AddTenderReview :one
+INSERT INTO tender_reviews (tender_id, round, decision, reason, reviewer_id)
+VALUES ($1, (SELECT COUNT(*) + 1 FROM tender_reviews WHERE tender_id = $1), $2, $3, $4)
+RETURNING *;
+
+-- name: GetTenderReviews :many
+SELECT * FROM tender_reviews
+WHERE tender_id = $1
+ORDER BY round;
+
+-- name: GetLastTenderReview :one
+SELECT * FROM tender_reviews
+WHERE tender_id = $1
+ORDER BY round DESC
+LIMIT 1;
@@ -0,0 +1,25 @@
+-- name: CreateTenderTemplate :one
+INSERT INTO tender_templates (organizer_id, name, title, description, type, duration_minutes, extension_minutes, conditions_path, classifications)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: CreateTenderTemplateLot :one
+INSERT INTO tender_template_lots (template_id, number, title, start_price, min_bid_decrease, min_bid_step_type, classification)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetTenderTemplate :one
+SELECT * FROM tender_templates WHERE id = $1;
+
+-- name: GetOrganizerTemplates :many
+SELECT * FROM tender_templates
+WHERE organizer_id = $1
+ORDER BY name;
+
+-- name: GetTenderTemplateLots :many
+SELECT * FROM tender_template_lots
+WHERE template_id = $1
+ORDER BY number;
+
+-- name: DeleteOrganizerTemplate :execrows
+DELETE FROM tender_templates WHERE id = $1 AND organizer_id = $2;
@@ -1,10 +1,10 @@
 -- name: CreateTender :one 
-INSERT INTO tenders(title, description, start_price, start_at, conditions_path, current_price, classification)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO tenders(title, description, start_price, start_at, conditions_path, current_price, classification, min_bid_decrease, min_bid_step_type, organizer_id, type, end_at, extension_minutes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 RETURNING *;
 
 -- name: GetTenders :many
-SELECT * FROM tenders WHERE status != 'completed' ORDER BY created_at DESC;
+SELECT * FROM tenders WHERE status NOT IN ('completed', 'failed', 'cancelled', 'rejected') ORDER BY created_at DESC;
 
 -- name: GetTenderById :one
 SELECT * FROM tenders WHERE id = $1;
@@ -19,24 +19,21 @@ ORDER BY start_at ASC;
 
 
 -- name: GetHistory :many 
-SELECT * FROM tenders WHERE status = 'completed' ORDER BY created_at DESC;
+SELECT * FROM tenders WHERE status IN ('completed', 'failed') ORDER BY created_at DESC;
 
 
 -- name: GetTendersForDeletion :many
 SELECT * FROM tenders 
-WHERE status != 'completed' 
+WHERE status NOT IN ('completed', 'failed', 'cancelled') 
 ORDER BY created_at DESC;
 
--- name: DeleteTender :exec
-DELETE FROM tenders WHERE id = $1;
-
--- name: ApproveTender :exec
-UPDATE tenders 
-SET status = 'active_pending'
-WHERE id = $1;
+-- name: DeleteTender :execrows
+DELETE FROM tenders
+WHERE id = $1 AND status = 'pending_approval'
+  AND NOT EXISTS (SELECT 1 FROM tender_events e WHERE e.tender_id = tenders.id);
 
 -- name: GetStartingTenders :many
-SELECT title, id, current_price, start_price 
+SELECT title, id, current_price, start_price, organizer_id, type, closes_at
 FROM tenders WHERE start_at <= NOW()
 AND status = 'active' AND message_sent != true;
 
@@ -44,23 +41,44 @@ AND status = 'active' AND message_sent != true;
 UPDATE tenders SET message_sent = true 
 WHERE id = $1;
 
--- name: ActivatePendingTenders :exec
-UPDATE tenders 
-SET status = 'active' 
-WHERE status = 'active_pending' 
-AND start_at <= NOW();
+-- name: GetTendersToActivate :many
+SELECT * FROM tenders
+WHERE status = 'active_pending' AND start_at <= NOW()
+ORDER BY start_at, id;
+
+-- name: SetSealedTenderDeadlines :exec
+WITH sealed AS (
+    UPDATE tenders
+    SET closes_at = end_at
+    WHERE id = $1 AND type = 'sealed'
+    RETURNING id, end_at
+)
+UPDATE tender_lots
+SET closes_at = sealed.end_at
+FROM sealed
+WHERE tender_lots.tender_id = sealed.id;
 
 -- name: GetTendersForSuppliers :many
 SELECT * FROM tenders 
 WHERE (status = 'active' OR status = 'active_pending')
-AND (classification = $1 OR classification = $2);
+AND EXISTS (
+    -- Подписка на группу охватывает её подкатегории, а тендер по всей группе
+    -- видят поставщики каждой её подкатегории
+    SELECT 1 FROM tender_classifications tc
+    JOIN classifications t ON t.code = tc.classification_code
+    JOIN user_classifications uc ON uc.user_id = $1
+    JOIN classifications s ON s.code = uc.classification_code
+    WHERE tc.tender_id = tenders.id
+    AND (s.code = t.code OR s.code = t.parent_code OR s.parent_code = t.code)
+);
 
 
 -- name: JoinTender :exec
 WITH inserted AS (
     INSERT INTO tender_participants (tender_id, user_id)
     VALUES ($1, $2)
-    ON CONFLICT (tender_id, user_id) DO NOTHING
+    ON CONFLICT (tender_id, user_id) DO UPDATE SET status = 'active'
+    WHERE tender_participants.status = 'withdrew'
     RETURNING 1
 )
 UPDATE tenders
@@ -69,19 +87,19 @@ WHERE tenders.id = $1 AND EXISTS (SELECT 1 FROM inserted);
 
 
 -- name: LeaveTender :exec
-WITH deleted AS (
-    DELETE FROM tender_participants 
-    WHERE tender_id = $1 AND user_id = $2
+WITH withdrawn AS (
+    UPDATE tender_participants SET status = 'withdrew'
+    WHERE tender_id = $1 AND user_id = $2 AND status = 'active'
     RETURNING 1
 )
 UPDATE tenders
 SET participants_count = participants_count - 1
-WHERE tenders.id = $1 AND EXISTS (SELECT 1 FROM deleted);
+WHERE tenders.id = $1 AND EXISTS (SELECT 1 FROM withdrawn);
 
 -- name: CheckTenderParticipation :one
 SELECT EXISTS(
     SELECT 1 FROM tender_participants 
-    WHERE tender_id = $1 AND user_id = $2
+    WHERE tender_id = $1 AND user_id = $2 AND status = 'active'
 ) as is_participating;
 
 -- name: GetTender :one
@@ -91,12 +109,66 @@ SELECT * FROM tenders WHERE id = $1;
 -- name: CheckUserHasAnyTenderParticipation :one
 SELECT EXISTS(
     SELECT 1 FROM tender_participants 
-    WHERE user_id = $1 
+    WHERE user_id = $1 AND status = 'active'
     AND tender_id != $2  -- исключаем текущий тендер
 ) as has_participation;
 
--- name: UpdateTenderStatus :exec
-UPDATE tenders SET status = $2 WHERE id = $1;
-
 -- name: TimeZone :one
-SELECT current_setting('TIMEZONE');
\ No newline at end of file
+SELECT current_setting('TIMEZONE');
+
+-- name: GetTenderForUpdate :one
+SELECT * FROM tenders WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateTenderAfterBid :one
+UPDATE tenders
+SET current_price = (SELECT SUM(current_price) FROM tender_lots WHERE tender_id = $1),
+    last_bid_at = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: GetOrganizerTenders :many
+SELECT * FROM tenders
+WHERE organizer_id = $1 AND status NOT IN ('completed', 'failed', 'cancelled')
+ORDER BY created_at DESC;
+
+-- name: DeleteOrganizerTender :execrows
+DELETE FROM tenders
+WHERE id = $1 AND organizer_id = $2 AND status = 'pending_approval'
+  AND NOT EXISTS (SELECT 1 FROM tender_events e WHERE e.tender_id = tenders.id);
+
+-- name: GetActiveTendersForParticipant :many
+SELECT t.* FROM tenders t
+JOIN tender_participants tp ON tp.tender_id = t.id
+WHERE tp.user_id = $1 AND tp.status = 'active' AND t.status = 'active'
+ORDER BY t.start_at, t.id;
+
+-- name: GetExpiredTenders :many
+SELECT * FROM tenders
+WHERE status = 'active' AND end_at IS NOT NULL AND end_at <= NOW()
+ORDER BY end_at;
+
+-- name: ExtendTenderEndAt :one
+UPDATE tenders SET end_at = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateTenderDetails :one
+UPDATE tenders
+SET title = $2, description = $3, start_at = $4, end_at = $5, extension_minutes = $6, conditions_path = $7
+WHERE id = $1 AND status IN ('pending_approval', 'active_pending')
+RETURNING *;
+
+-- name: UpdateTenderDraft :one
+UPDATE tenders
+SET title = $2, description = $3, start_price = $4, start_at = $5, conditions_path = $6,
+    current_price = $7, classification = $8, min_bid_decrease = $9, min_bid_step_type = $10,
+    type = $11, end_at = $12, extension_minutes = $13
+WHERE id = $1
+RETURNING *;
+
+-- name: IsConditionsFileInUse :one
+SELECT EXISTS(
+    SELECT 1 FROM tenders WHERE conditions_path = $1
+) OR EXISTS(
+    SELECT 1 FROM tender_templates WHERE conditions_path = $1
+) as in_use;
@@ -1,11 +1,11 @@
 -- name: CreateUser :one
-INSERT INTO users (telegram_id, organization_name, inn, ogrn, phone_number, classification, role, banned, name)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+INSERT INTO users (telegram_id, organization_name, inn, ogrn, phone_number, role, banned, name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 
 -- name: GetUserByTelegramID :one
-SELECT telegram_id, organization_name, inn, ogrn, phone_number, classification, role, banned, name
+SELECT telegram_id, organization_name, inn, ogrn, phone_number, role, banned, name
 FROM users
 WHERE telegram_id = $1;
 
@@ -16,20 +16,58 @@ SET
     inn = $3,
     ogrn = $4,
     phone_number = $5,
-    name = $6,
-    classification = $7
+    name = $6
 WHERE telegram_id = $1;
 
--- name: GetUsersByClassification :many
-SELECT telegram_id FROM users 
-WHERE $1 = ANY(string_to_array(classification, ','));
+-- name: UpdateUserProfile :exec
+UPDATE users
+SET
+    phone_number = $2,
+    name = $3
+WHERE telegram_id = $1;
+
+-- name: UpdateUserRequisites :exec
+UPDATE users
+SET
+    organization_name = $2,
+    inn = $3,
+    ogrn = $4
+WHERE telegram_id = $1;
+
+-- name: GetUsersByTenderClassifications :many
+SELECT DISTINCT u.telegram_id FROM users u
+JOIN user_classifications uc ON uc.user_id = u.telegram_id
+JOIN classifications s ON s.code = uc.classification_code
+JOIN classifications t ON s.code = t.code OR s.code = t.parent_code OR s.parent_code = t.code
+JOIN tender_classifications tc ON tc.classification_code = t.code
+WHERE tc.tender_id = $1;
 
 
 -- name: GetAllUsers :many
-SELECT * FROM users WHERE role = 'supplier';
+SELECT * FROM users ORDER BY role, telegram_id;
 
 -- name: BlockUser :exec
 UPDATE users SET banned = true WHERE telegram_id = $1;
 
 -- name: UnblockUser :exec
-UPDATE users SET banned = false WHERE telegram_id = $1;
\ No newline at end of file
+UPDATE users SET banned = false WHERE telegram_id = $1;
+
+-- name: UpdateUserRole :exec
+UPDATE users SET role = $2 WHERE telegram_id = $1;
+
+-- name: GetUserIDsByRole :many
+SELECT telegram_id FROM users
+WHERE role = $1 AND banned IS NOT TRUE
+ORDER BY telegram_id;
+
+-- name: CountAdmins :one
+SELECT COUNT(*) FROM users
+WHERE role = 'admin' AND banned IS NOT TRUE;
+
+-- name: GetUserIDByInn :one
+SELECT telegram_id FROM users
+WHERE inn = $1 AND telegram_id <> $2;
+
+-- name: GetUserIDByOgrn :one
+SELECT telegram_id FROM users
+WHERE ogrn = $1 AND telegram_id <> $2;
@@ -0,0 +1,35 @@
+-- name: CreateWinnerOffer :one
+INSERT INTO winner_offers (tender_id, lot_id, user_id, amount, rank, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetWinnerOffer :one
+SELECT * FROM winner_offers WHERE id = $1;
+
+-- name: GetLotWinnerOffers :many
+SELECT * FROM winner_offers
+WHERE lot_id = $1
+ORDER BY rank;
+
+-- name: RespondWinnerOffer :execrows
+UPDATE winner_offers
+SET status = $2, responded_at = NOW()
+WHERE id = $1 AND status = 'pending';
+
+-- name: CancelTenderWinnerOffers :exec
+UPDATE winner_offers
+SET status = 'cancelled', responded_at = NOW()
+WHERE tender_id = $1 AND status = 'pending';
+
+-- name: GetExpiredWinnerOffers :many
+SELECT * FROM winner_offers
+WHERE status = 'pending' AND expires_at <= NOW()
+ORDER BY expires_at;
+
+-- name: GetLotBidRanking :many
+SELECT b.user_id, MIN(b.amount)::FLOAT AS amount
+FROM tender_bids b
+JOIN tender_participants tp ON tp.tender_id = b.tender_id AND tp.user_id = b.user_id
+WHERE b.lot_id = $1 AND tp.status = 'active'
+GROUP BY b.user_id
+ORDER BY MIN(b.amount), MIN(b.bid_time);
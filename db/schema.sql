@@ -2,9 +2,8 @@ CREATE TABLE users (
     telegram_id        BIGINT PRIMARY KEY,
     organization_name  VARCHAR(255),
     inn                VARCHAR(12)  UNIQUE,
-    ogrn               VARCHAR(13)  UNIQUE,
+    ogrn               VARCHAR(15)  UNIQUE,
     phone_number       VARCHAR(20),
-    classification     VARCHAR(255),
     role               VARCHAR(15) NOT NULL,
     banned             BOOLEAN DEFAULT false, 
     name               VARCHAR(255) 
@@ -26,25 +25,59 @@ CREATE TABLE tenders (
     
     last_bid_at TIMESTAMPTZ,              
     current_price FLOAT NOT NULL,
-    min_bid_decrease FLOAT NOT NULL DEFAULT 10000.0
+    min_bid_decrease FLOAT NOT NULL DEFAULT 10000.0,
+    closes_at TIMESTAMPTZ,
+    min_bid_step_type VARCHAR(8) NOT NULL DEFAULT 'amount',
+    organizer_id BIGINT REFERENCES users(telegram_id) ON DELETE SET NULL,
+    type VARCHAR(8) NOT NULL DEFAULT 'open',
+    end_at TIMESTAMPTZ,
+    extension_minutes INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT tenders_sealed_end_at CHECK (type <> 'sealed' OR end_at IS NOT NULL)
 );
 
+CREATE INDEX idx_tenders_organizer_id ON tenders(organizer_id);
+CREATE INDEX idx_tenders_end_at ON tenders(end_at) WHERE status = 'active';
+
+CREATE TABLE tender_lots (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    start_price FLOAT NOT NULL,
+    current_price FLOAT NOT NULL,
+    min_bid_decrease FLOAT NOT NULL DEFAULT 0,
+    min_bid_step_type VARCHAR(8) NOT NULL DEFAULT 'amount',
+    classification VARCHAR(255),
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    last_bid_at TIMESTAMPTZ,
+    closes_at TIMESTAMPTZ,
+    CONSTRAINT unique_tender_lot_number UNIQUE (tender_id, number)
+);
+
+CREATE INDEX idx_tender_lots_tender_id ON tender_lots(tender_id);
+
 CREATE TABLE tender_participants (
     id SERIAL PRIMARY KEY,
     tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
     user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
     joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
     CONSTRAINT unique_event_participant UNIQUE(tender_id, user_id)
 );
 
+CREATE INDEX idx_tender_participants_user_id ON tender_participants(user_id);
+
 CREATE TABLE tender_bids (
     id SERIAL PRIMARY KEY,
     tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
     user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
     amount FLOAT NOT NULL,
-    bid_time TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    bid_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lot_id INTEGER NOT NULL REFERENCES tender_lots(id) ON DELETE CASCADE
 );
 
+CREATE INDEX idx_tender_bids_lot_id ON tender_bids(lot_id);
+
 CREATE TABLE history (
     id SERIAL PRIMARY KEY,
     tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
@@ -53,19 +86,148 @@ CREATE TABLE history (
     phone_number VARCHAR(20),
     inn VARCHAR(12),
     fio VARCHAR(255),
-    bid FLOAT NOT NULL,
+    bid FLOAT,
     start_price FLOAT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lot_id INTEGER NOT NULL REFERENCES tender_lots(id) ON DELETE CASCADE,
+    outcome VARCHAR(16) NOT NULL DEFAULT 'completed'
+);
+
+CREATE TABLE winner_offers (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    lot_id INTEGER NOT NULL REFERENCES tender_lots(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    amount FLOAT NOT NULL,
+    rank INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    offered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    CONSTRAINT unique_lot_offer_user UNIQUE (lot_id, user_id)
+);
+
+CREATE INDEX idx_winner_offers_pending ON winner_offers(expires_at) WHERE status = 'pending';
+
+CREATE TABLE tender_events (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    actor_id BIGINT,
+    reason TEXT NOT NULL DEFAULT '',
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 
+CREATE INDEX idx_tender_events_tender_id ON tender_events(tender_id);
+
+CREATE TABLE tender_reviews (
+    id SERIAL PRIMARY KEY,
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    decision VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    reviewer_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_tender_review_round UNIQUE (tender_id, round)
+);
+
+CREATE TABLE tender_templates (
+    id SERIAL PRIMARY KEY,
+    organizer_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    type VARCHAR(8) NOT NULL DEFAULT 'open',
+    duration_minutes INTEGER,
+    extension_minutes INTEGER NOT NULL DEFAULT 0,
+    conditions_path VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    classifications VARCHAR(16)[] NOT NULL DEFAULT '{}',
+    CONSTRAINT unique_organizer_template_name UNIQUE (organizer_id, name)
+);
+
+CREATE TABLE tender_template_lots (
+    id SERIAL PRIMARY KEY,
+    template_id INTEGER NOT NULL REFERENCES tender_templates(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    start_price FLOAT NOT NULL,
+    min_bid_decrease FLOAT NOT NULL DEFAULT 0,
+    min_bid_step_type VARCHAR(8) NOT NULL DEFAULT 'amount',
+    classification VARCHAR(255),
+    CONSTRAINT unique_template_lot_number UNIQUE (template_id, number)
+);
+
 
 CREATE TABLE pending_users (
     id SERIAL PRIMARY KEY,
-    telegram_id BIGINT NOT NULL,
+    telegram_id BIGINT NOT NULL UNIQUE,
     organization_name VARCHAR(255),
     inn VARCHAR(12),
+    ogrn VARCHAR(15),
     phone_number VARCHAR(20),
     name VARCHAR(255),
-    classification VARCHAR(255),
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
\ No newline at end of file
+    classification TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    kind VARCHAR(16) NOT NULL DEFAULT 'registration'
+);
+
+CREATE INDEX idx_pending_users_inn ON pending_users(inn);
+
+CREATE TABLE supplier_documents (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    pending_user_id INTEGER REFERENCES pending_users(id) ON DELETE SET NULL,
+    kind VARCHAR(32) NOT NULL,
+    file_path VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    expires_at DATE,
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reupload_requested_at TIMESTAMPTZ,
+    expiry_notified_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_supplier_documents_user_id ON supplier_documents(user_id);
+CREATE INDEX idx_supplier_documents_pending_user_id ON supplier_documents(pending_user_id);
+CREATE INDEX idx_supplier_documents_expires_at ON supplier_documents(expires_at) WHERE expiry_notified_at IS NULL;
+
+CREATE TABLE conversation_states (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    flow VARCHAR(32) NOT NULL,
+    step INTEGER NOT NULL DEFAULT 0,
+    data JSONB NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, flow)
+);
+
+CREATE SEQUENCE classification_code_seq START 22;
+
+CREATE TABLE classifications (
+    code VARCHAR(16) PRIMARY KEY DEFAULT nextval('classification_code_seq')::text,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    position INTEGER NOT NULL,
+    archived BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    parent_code VARCHAR(16) REFERENCES classifications(code),
+    CONSTRAINT classifications_parent_not_self CHECK (parent_code <> code)
+);
+
+CREATE INDEX idx_classifications_parent ON classifications(parent_code);
+
+CREATE TABLE user_classifications (
+    user_id BIGINT NOT NULL REFERENCES users(telegram_id) ON DELETE CASCADE,
+    classification_code VARCHAR(16) NOT NULL REFERENCES classifications(code),
+    PRIMARY KEY (user_id, classification_code)
+);
+
+CREATE INDEX idx_user_classifications_code ON user_classifications(classification_code);
+
+CREATE TABLE tender_classifications (
+    tender_id INTEGER NOT NULL REFERENCES tenders(id) ON DELETE CASCADE,
+    classification_code VARCHAR(16) NOT NULL REFERENCES classifications(code),
+    PRIMARY KEY (tender_id, classification_code)
+);
+
+CREATE INDEX idx_tender_classifications_code ON tender_classifications(classification_code);
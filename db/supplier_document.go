@@ -0,0 +1,8 @@
+package db
+
+// Вид документа поставщика (supplier_documents.kind)
+const (
+	DocumentKindEGRUL           = "egrul"
+	DocumentKindSRO             = "sro"
+	DocumentKindPowerOfAttorney = "power_of_attorney"
+)
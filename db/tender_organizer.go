@@ -0,0 +1,16 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TenderOrganizerIDs возвращает получателей уведомлений организатора по тендеру:
+// владельца, а для тендеров, созданных до появления владельца, — всех организаторов
+func (q *Queries) TenderOrganizerIDs(ctx context.Context, organizerID pgtype.Int8) ([]int64, error) {
+	if organizerID.Valid {
+		return []int64{organizerID.Int64}, nil
+	}
+	return q.GetUserIDsByRole(ctx, "organizer")
+}
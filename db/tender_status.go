@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// setTenderStatus меняет статус тендера, только если он всё ещё прежний. Запроса нет
+// в Querier: статус меняется только через ChangeTenderStatus вместе с записью в tender_events.
+const setTenderStatus = `UPDATE tenders SET status = $1
+WHERE id = $2 AND status = $3`
+
+type ChangeTenderStatusParams struct {
+	TenderID   int32
+	FromStatus string
+	ToStatus   string
+	// ActorID — Telegram ID пользователя, сменившего статус; пустой для системы
+	ActorID pgtype.Int8
+	Reason  string
+	// Apply, если задан, выполняется в той же транзакции после смены статуса; его
+	// ошибка отменяет и переход
+	Apply func(q *Queries) error
+}
+
+// ChangeTenderStatus в одной транзакции переводит тендер из FromStatus в ToStatus и
+// записывает событие в tender_events. Возвращает false, если статус тендера уже
+// другой. Допустимость перехода не проверяется — статус меняется через tender.Transition.
+func (q *Queries) ChangeTenderStatus(ctx context.Context, arg ChangeTenderStatusParams) (bool, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return false, fmt.Errorf("change tender status: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	result, err := tx.Exec(ctx, setTenderStatus, arg.ToStatus, arg.TenderID, arg.FromStatus)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	err = qtx.AddTenderEvent(ctx, AddTenderEventParams{
+		TenderID:   arg.TenderID,
+		FromStatus: arg.FromStatus,
+		ToStatus:   arg.ToStatus,
+		ActorID:    arg.ActorID,
+		Reason:     arg.Reason,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if arg.Apply != nil {
+		if err := arg.Apply(qtx); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -11,33 +11,10 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const activatePendingTenders = `-- name: ActivatePendingTenders :exec
-UPDATE tenders 
-SET status = 'active' 
-WHERE status = 'active_pending' 
-AND start_at <= NOW()
-`
-
-func (q *Queries) ActivatePendingTenders(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, activatePendingTenders)
-	return err
-}
-
-const approveTender = `-- name: ApproveTender :exec
-UPDATE tenders 
-SET status = 'active_pending'
-WHERE id = $1
-`
-
-func (q *Queries) ApproveTender(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, approveTender, id)
-	return err
-}
-
 const checkTenderParticipation = `-- name: CheckTenderParticipation :one
 SELECT EXISTS(
     SELECT 1 FROM tender_participants 
-    WHERE tender_id = $1 AND user_id = $2
+    WHERE tender_id = $1 AND user_id = $2 AND status = 'active'
 ) as is_participating
 `
 
@@ -56,7 +33,7 @@ func (q *Queries) CheckTenderParticipation(ctx context.Context, arg CheckTenderP
 const checkUserHasAnyTenderParticipation = `-- name: CheckUserHasAnyTenderParticipation :one
 SELECT EXISTS(
     SELECT 1 FROM tender_participants 
-    WHERE user_id = $1 
+    WHERE user_id = $1 AND status = 'active'
     AND tender_id != $2  -- исключаем текущий тендер
 ) as has_participation
 `
@@ -74,19 +51,25 @@ func (q *Queries) CheckUserHasAnyTenderParticipation(ctx context.Context, arg Ch
 }
 
 const createTender = `-- name: CreateTender :one
-INSERT INTO tenders(title, description, start_price, start_at, conditions_path, current_price, classification)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease
+INSERT INTO tenders(title, description, start_price, start_at, conditions_path, current_price, classification, min_bid_decrease, min_bid_step_type, organizer_id, type, end_at, extension_minutes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes
 `
 
 type CreateTenderParams struct {
-	Title          string             `json:"title"`
-	Description    pgtype.Text        `json:"description"`
-	StartPrice     float64            `json:"start_price"`
-	StartAt        pgtype.Timestamptz `json:"start_at"`
-	ConditionsPath pgtype.Text        `json:"conditions_path"`
-	CurrentPrice   float64            `json:"current_price"`
-	Classification pgtype.Text        `json:"classification"`
+	Title            string             `json:"title"`
+	Description      pgtype.Text        `json:"description"`
+	StartPrice       float64            `json:"start_price"`
+	StartAt          pgtype.Timestamptz `json:"start_at"`
+	ConditionsPath   pgtype.Text        `json:"conditions_path"`
+	CurrentPrice     float64            `json:"current_price"`
+	Classification   pgtype.Text        `json:"classification"`
+	MinBidDecrease   float64            `json:"min_bid_decrease"`
+	MinBidStepType   string             `json:"min_bid_step_type"`
+	OrganizerID      pgtype.Int8        `json:"organizer_id"`
+	Type             string             `json:"type"`
+	EndAt            pgtype.Timestamptz `json:"end_at"`
+	ExtensionMinutes int32              `json:"extension_minutes"`
 }
 
 func (q *Queries) CreateTender(ctx context.Context, arg CreateTenderParams) (Tender, error) {
@@ -98,6 +81,12 @@ func (q *Queries) CreateTender(ctx context.Context, arg CreateTenderParams) (Ten
 		arg.ConditionsPath,
 		arg.CurrentPrice,
 		arg.Classification,
+		arg.MinBidDecrease,
+		arg.MinBidStepType,
+		arg.OrganizerID,
+		arg.Type,
+		arg.EndAt,
+		arg.ExtensionMinutes,
 	)
 	var i Tender
 	err := row.Scan(
@@ -115,21 +104,185 @@ func (q *Queries) CreateTender(ctx context.Context, arg CreateTenderParams) (Ten
 		&i.LastBidAt,
 		&i.CurrentPrice,
 		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
 	)
 	return i, err
 }
 
-const deleteTender = `-- name: DeleteTender :exec
-DELETE FROM tenders WHERE id = $1
+const deleteOrganizerTender = `-- name: DeleteOrganizerTender :execrows
+DELETE FROM tenders
+WHERE id = $1 AND organizer_id = $2 AND status = 'pending_approval'
+  AND NOT EXISTS (SELECT 1 FROM tender_events e WHERE e.tender_id = tenders.id)
 `
 
-func (q *Queries) DeleteTender(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteTender, id)
-	return err
+type DeleteOrganizerTenderParams struct {
+	ID          int32       `json:"id"`
+	OrganizerID pgtype.Int8 `json:"organizer_id"`
+}
+
+func (q *Queries) DeleteOrganizerTender(ctx context.Context, arg DeleteOrganizerTenderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrganizerTender, arg.ID, arg.OrganizerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTender = `-- name: DeleteTender :execrows
+DELETE FROM tenders
+WHERE id = $1 AND status = 'pending_approval'
+  AND NOT EXISTS (SELECT 1 FROM tender_events e WHERE e.tender_id = tenders.id)
+`
+
+func (q *Queries) DeleteTender(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTender, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const extendTenderEndAt = `-- name: ExtendTenderEndAt :one
+UPDATE tenders SET end_at = $2
+WHERE id = $1
+RETURNING id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes
+`
+
+type ExtendTenderEndAtParams struct {
+	ID    int32              `json:"id"`
+	EndAt pgtype.Timestamptz `json:"end_at"`
+}
+
+func (q *Queries) ExtendTenderEndAt(ctx context.Context, arg ExtendTenderEndAtParams) (Tender, error) {
+	row := q.db.QueryRow(ctx, extendTenderEndAt, arg.ID, arg.EndAt)
+	var i Tender
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.StartPrice,
+		&i.StartAt,
+		&i.Status,
+		&i.ConditionsPath,
+		&i.CreatedAt,
+		&i.Classification,
+		&i.ParticipantsCount,
+		&i.MessageSent,
+		&i.LastBidAt,
+		&i.CurrentPrice,
+		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
+	)
+	return i, err
+}
+
+const getActiveTendersForParticipant = `-- name: GetActiveTendersForParticipant :many
+SELECT t.id, t.title, t.description, t.start_price, t.start_at, t.status, t.conditions_path, t.created_at, t.classification, t.participants_count, t.message_sent, t.last_bid_at, t.current_price, t.min_bid_decrease, t.closes_at, t.min_bid_step_type, t.organizer_id, t.type, t.end_at, t.extension_minutes FROM tenders t
+JOIN tender_participants tp ON tp.tender_id = t.id
+WHERE tp.user_id = $1 AND tp.status = 'active' AND t.status = 'active'
+ORDER BY t.start_at, t.id
+`
+
+func (q *Queries) GetActiveTendersForParticipant(ctx context.Context, userID int64) ([]Tender, error) {
+	rows, err := q.db.Query(ctx, getActiveTendersForParticipant, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tender{}
+	for rows.Next() {
+		var i Tender
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.StartPrice,
+			&i.StartAt,
+			&i.Status,
+			&i.ConditionsPath,
+			&i.CreatedAt,
+			&i.Classification,
+			&i.ParticipantsCount,
+			&i.MessageSent,
+			&i.LastBidAt,
+			&i.CurrentPrice,
+			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredTenders = `-- name: GetExpiredTenders :many
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders
+WHERE status = 'active' AND end_at IS NOT NULL AND end_at <= NOW()
+ORDER BY end_at
+`
+
+func (q *Queries) GetExpiredTenders(ctx context.Context) ([]Tender, error) {
+	rows, err := q.db.Query(ctx, getExpiredTenders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tender{}
+	for rows.Next() {
+		var i Tender
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.StartPrice,
+			&i.StartAt,
+			&i.Status,
+			&i.ConditionsPath,
+			&i.CreatedAt,
+			&i.Classification,
+			&i.ParticipantsCount,
+			&i.MessageSent,
+			&i.LastBidAt,
+			&i.CurrentPrice,
+			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getHistory = `-- name: GetHistory :many
-SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease FROM tenders WHERE status = 'completed' ORDER BY created_at DESC
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders WHERE status IN ('completed', 'failed') ORDER BY created_at DESC
 `
 
 func (q *Queries) GetHistory(ctx context.Context) ([]Tender, error) {
@@ -156,6 +309,59 @@ func (q *Queries) GetHistory(ctx context.Context) ([]Tender, error) {
 			&i.LastBidAt,
 			&i.CurrentPrice,
 			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrganizerTenders = `-- name: GetOrganizerTenders :many
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders
+WHERE organizer_id = $1 AND status NOT IN ('completed', 'failed', 'cancelled')
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetOrganizerTenders(ctx context.Context, organizerID pgtype.Int8) ([]Tender, error) {
+	rows, err := q.db.Query(ctx, getOrganizerTenders, organizerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tender{}
+	for rows.Next() {
+		var i Tender
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.StartPrice,
+			&i.StartAt,
+			&i.Status,
+			&i.ConditionsPath,
+			&i.CreatedAt,
+			&i.Classification,
+			&i.ParticipantsCount,
+			&i.MessageSent,
+			&i.LastBidAt,
+			&i.CurrentPrice,
+			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -168,16 +374,19 @@ func (q *Queries) GetHistory(ctx context.Context) ([]Tender, error) {
 }
 
 const getStartingTenders = `-- name: GetStartingTenders :many
-SELECT title, id, current_price, start_price 
+SELECT title, id, current_price, start_price, organizer_id, type, closes_at
 FROM tenders WHERE start_at <= NOW()
 AND status = 'active' AND message_sent != true
 `
 
 type GetStartingTendersRow struct {
-	Title        string  `json:"title"`
-	ID           int32   `json:"id"`
-	CurrentPrice float64 `json:"current_price"`
-	StartPrice   float64 `json:"start_price"`
+	Title        string             `json:"title"`
+	ID           int32              `json:"id"`
+	CurrentPrice float64            `json:"current_price"`
+	StartPrice   float64            `json:"start_price"`
+	OrganizerID  pgtype.Int8        `json:"organizer_id"`
+	Type         string             `json:"type"`
+	ClosesAt     pgtype.Timestamptz `json:"closes_at"`
 }
 
 func (q *Queries) GetStartingTenders(ctx context.Context) ([]GetStartingTendersRow, error) {
@@ -194,6 +403,9 @@ func (q *Queries) GetStartingTenders(ctx context.Context) ([]GetStartingTendersR
 			&i.ID,
 			&i.CurrentPrice,
 			&i.StartPrice,
+			&i.OrganizerID,
+			&i.Type,
+			&i.ClosesAt,
 		); err != nil {
 			return nil, err
 		}
@@ -206,7 +418,7 @@ func (q *Queries) GetStartingTenders(ctx context.Context) ([]GetStartingTendersR
 }
 
 const getTender = `-- name: GetTender :one
-SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease FROM tenders WHERE id = $1
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders WHERE id = $1
 `
 
 func (q *Queries) GetTender(ctx context.Context, id int32) (Tender, error) {
@@ -227,12 +439,18 @@ func (q *Queries) GetTender(ctx context.Context, id int32) (Tender, error) {
 		&i.LastBidAt,
 		&i.CurrentPrice,
 		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
 	)
 	return i, err
 }
 
 const getTenderById = `-- name: GetTenderById :one
-SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease FROM tenders WHERE id = $1
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders WHERE id = $1
 `
 
 func (q *Queries) GetTenderById(ctx context.Context, id int32) (Tender, error) {
@@ -253,12 +471,50 @@ func (q *Queries) GetTenderById(ctx context.Context, id int32) (Tender, error) {
 		&i.LastBidAt,
 		&i.CurrentPrice,
 		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
+	)
+	return i, err
+}
+
+const getTenderForUpdate = `-- name: GetTenderForUpdate :one
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetTenderForUpdate(ctx context.Context, id int32) (Tender, error) {
+	row := q.db.QueryRow(ctx, getTenderForUpdate, id)
+	var i Tender
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.StartPrice,
+		&i.StartAt,
+		&i.Status,
+		&i.ConditionsPath,
+		&i.CreatedAt,
+		&i.Classification,
+		&i.ParticipantsCount,
+		&i.MessageSent,
+		&i.LastBidAt,
+		&i.CurrentPrice,
+		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
 	)
 	return i, err
 }
 
 const getTenders = `-- name: GetTenders :many
-SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease FROM tenders WHERE status != 'completed' ORDER BY created_at DESC
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders WHERE status NOT IN ('completed', 'failed', 'cancelled', 'rejected') ORDER BY created_at DESC
 `
 
 func (q *Queries) GetTenders(ctx context.Context) ([]Tender, error) {
@@ -285,6 +541,12 @@ func (q *Queries) GetTenders(ctx context.Context) ([]Tender, error) {
 			&i.LastBidAt,
 			&i.CurrentPrice,
 			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -297,8 +559,8 @@ func (q *Queries) GetTenders(ctx context.Context) ([]Tender, error) {
 }
 
 const getTendersForDeletion = `-- name: GetTendersForDeletion :many
-SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease FROM tenders 
-WHERE status != 'completed' 
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders 
+WHERE status NOT IN ('completed', 'failed', 'cancelled') 
 ORDER BY created_at DESC
 `
 
@@ -326,6 +588,12 @@ func (q *Queries) GetTendersForDeletion(ctx context.Context) ([]Tender, error) {
 			&i.LastBidAt,
 			&i.CurrentPrice,
 			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -338,18 +606,22 @@ func (q *Queries) GetTendersForDeletion(ctx context.Context) ([]Tender, error) {
 }
 
 const getTendersForSuppliers = `-- name: GetTendersForSuppliers :many
-SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease FROM tenders 
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders 
 WHERE (status = 'active' OR status = 'active_pending')
-AND (classification = $1 OR classification = $2)
+AND EXISTS (
+    -- Подписка на группу охватывает её подкатегории, а тендер по всей группе
+    -- видят поставщики каждой её подкатегории
+    SELECT 1 FROM tender_classifications tc
+    JOIN classifications t ON t.code = tc.classification_code
+    JOIN user_classifications uc ON uc.user_id = $1
+    JOIN classifications s ON s.code = uc.classification_code
+    WHERE tc.tender_id = tenders.id
+    AND (s.code = t.code OR s.code = t.parent_code OR s.parent_code = t.code)
+)
 `
 
-type GetTendersForSuppliersParams struct {
-	Classification   pgtype.Text `json:"classification"`
-	Classification_2 pgtype.Text `json:"classification_2"`
-}
-
-func (q *Queries) GetTendersForSuppliers(ctx context.Context, arg GetTendersForSuppliersParams) ([]Tender, error) {
-	rows, err := q.db.Query(ctx, getTendersForSuppliers, arg.Classification, arg.Classification_2)
+func (q *Queries) GetTendersForSuppliers(ctx context.Context, userID int64) ([]Tender, error) {
+	rows, err := q.db.Query(ctx, getTendersForSuppliers, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -372,6 +644,12 @@ func (q *Queries) GetTendersForSuppliers(ctx context.Context, arg GetTendersForS
 			&i.LastBidAt,
 			&i.CurrentPrice,
 			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -418,11 +696,74 @@ func (q *Queries) GetTendersStartingIn10Minutes(ctx context.Context) ([]GetTende
 	return items, nil
 }
 
+const getTendersToActivate = `-- name: GetTendersToActivate :many
+SELECT id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes FROM tenders
+WHERE status = 'active_pending' AND start_at <= NOW()
+ORDER BY start_at, id
+`
+
+func (q *Queries) GetTendersToActivate(ctx context.Context) ([]Tender, error) {
+	rows, err := q.db.Query(ctx, getTendersToActivate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tender{}
+	for rows.Next() {
+		var i Tender
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.StartPrice,
+			&i.StartAt,
+			&i.Status,
+			&i.ConditionsPath,
+			&i.CreatedAt,
+			&i.Classification,
+			&i.ParticipantsCount,
+			&i.MessageSent,
+			&i.LastBidAt,
+			&i.CurrentPrice,
+			&i.MinBidDecrease,
+			&i.ClosesAt,
+			&i.MinBidStepType,
+			&i.OrganizerID,
+			&i.Type,
+			&i.EndAt,
+			&i.ExtensionMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isConditionsFileInUse = `-- name: IsConditionsFileInUse :one
+SELECT EXISTS(
+    SELECT 1 FROM tenders WHERE conditions_path = $1
+) OR EXISTS(
+    SELECT 1 FROM tender_templates WHERE conditions_path = $1
+) as in_use
+`
+
+func (q *Queries) IsConditionsFileInUse(ctx context.Context, conditionsPath pgtype.Text) (bool, error) {
+	row := q.db.QueryRow(ctx, isConditionsFileInUse, conditionsPath)
+	var in_use bool
+	err := row.Scan(&in_use)
+	return in_use, err
+}
+
 const joinTender = `-- name: JoinTender :exec
 WITH inserted AS (
     INSERT INTO tender_participants (tender_id, user_id)
     VALUES ($1, $2)
-    ON CONFLICT (tender_id, user_id) DO NOTHING
+    ON CONFLICT (tender_id, user_id) DO UPDATE SET status = 'active'
+    WHERE tender_participants.status = 'withdrew'
     RETURNING 1
 )
 UPDATE tenders
@@ -441,14 +782,14 @@ func (q *Queries) JoinTender(ctx context.Context, arg JoinTenderParams) error {
 }
 
 const leaveTender = `-- name: LeaveTender :exec
-WITH deleted AS (
-    DELETE FROM tender_participants 
-    WHERE tender_id = $1 AND user_id = $2
+WITH withdrawn AS (
+    UPDATE tender_participants SET status = 'withdrew'
+    WHERE tender_id = $1 AND user_id = $2 AND status = 'active'
     RETURNING 1
 )
 UPDATE tenders
 SET participants_count = participants_count - 1
-WHERE tenders.id = $1 AND EXISTS (SELECT 1 FROM deleted)
+WHERE tenders.id = $1 AND EXISTS (SELECT 1 FROM withdrawn)
 `
 
 type LeaveTenderParams struct {
@@ -471,6 +812,24 @@ func (q *Queries) MessageSent(ctx context.Context, id int32) error {
 	return err
 }
 
+const setSealedTenderDeadlines = `-- name: SetSealedTenderDeadlines :exec
+WITH sealed AS (
+    UPDATE tenders
+    SET closes_at = end_at
+    WHERE id = $1 AND type = 'sealed'
+    RETURNING id, end_at
+)
+UPDATE tender_lots
+SET closes_at = sealed.end_at
+FROM sealed
+WHERE tender_lots.tender_id = sealed.id
+`
+
+func (q *Queries) SetSealedTenderDeadlines(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, setSealedTenderDeadlines, id)
+	return err
+}
+
 const timeZone = `-- name: TimeZone :one
 SELECT current_setting('TIMEZONE')
 `
@@ -482,16 +841,163 @@ func (q *Queries) TimeZone(ctx context.Context) (string, error) {
 	return current_setting, err
 }
 
-const updateTenderStatus = `-- name: UpdateTenderStatus :exec
-UPDATE tenders SET status = $2 WHERE id = $1
+const updateTenderAfterBid = `-- name: UpdateTenderAfterBid :one
+UPDATE tenders
+SET current_price = (SELECT SUM(current_price) FROM tender_lots WHERE tender_id = $1),
+    last_bid_at = $2
+WHERE id = $1
+RETURNING id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes
 `
 
-type UpdateTenderStatusParams struct {
-	ID     int32  `json:"id"`
-	Status string `json:"status"`
+type UpdateTenderAfterBidParams struct {
+	ID        int32              `json:"id"`
+	LastBidAt pgtype.Timestamptz `json:"last_bid_at"`
 }
 
-func (q *Queries) UpdateTenderStatus(ctx context.Context, arg UpdateTenderStatusParams) error {
-	_, err := q.db.Exec(ctx, updateTenderStatus, arg.ID, arg.Status)
-	return err
+func (q *Queries) UpdateTenderAfterBid(ctx context.Context, arg UpdateTenderAfterBidParams) (Tender, error) {
+	row := q.db.QueryRow(ctx, updateTenderAfterBid, arg.ID, arg.LastBidAt)
+	var i Tender
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.StartPrice,
+		&i.StartAt,
+		&i.Status,
+		&i.ConditionsPath,
+		&i.CreatedAt,
+		&i.Classification,
+		&i.ParticipantsCount,
+		&i.MessageSent,
+		&i.LastBidAt,
+		&i.CurrentPrice,
+		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
+	)
+	return i, err
+}
+
+const updateTenderDetails = `-- name: UpdateTenderDetails :one
+UPDATE tenders
+SET title = $2, description = $3, start_at = $4, end_at = $5, extension_minutes = $6, conditions_path = $7
+WHERE id = $1 AND status IN ('pending_approval', 'active_pending')
+RETURNING id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes
+`
+
+type UpdateTenderDetailsParams struct {
+	ID               int32              `json:"id"`
+	Title            string             `json:"title"`
+	Description      pgtype.Text        `json:"description"`
+	StartAt          pgtype.Timestamptz `json:"start_at"`
+	EndAt            pgtype.Timestamptz `json:"end_at"`
+	ExtensionMinutes int32              `json:"extension_minutes"`
+	ConditionsPath   pgtype.Text        `json:"conditions_path"`
+}
+
+func (q *Queries) UpdateTenderDetails(ctx context.Context, arg UpdateTenderDetailsParams) (Tender, error) {
+	row := q.db.QueryRow(ctx, updateTenderDetails,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.StartAt,
+		arg.EndAt,
+		arg.ExtensionMinutes,
+		arg.ConditionsPath,
+	)
+	var i Tender
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.StartPrice,
+		&i.StartAt,
+		&i.Status,
+		&i.ConditionsPath,
+		&i.CreatedAt,
+		&i.Classification,
+		&i.ParticipantsCount,
+		&i.MessageSent,
+		&i.LastBidAt,
+		&i.CurrentPrice,
+		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
+	)
+	return i, err
+}
+
+const updateTenderDraft = `-- name: UpdateTenderDraft :one
+UPDATE tenders
+SET title = $2, description = $3, start_price = $4, start_at = $5, conditions_path = $6,
+    current_price = $7, classification = $8, min_bid_decrease = $9, min_bid_step_type = $10,
+    type = $11, end_at = $12, extension_minutes = $13
+WHERE id = $1
+RETURNING id, title, description, start_price, start_at, status, conditions_path, created_at, classification, participants_count, message_sent, last_bid_at, current_price, min_bid_decrease, closes_at, min_bid_step_type, organizer_id, type, end_at, extension_minutes
+`
+
+type UpdateTenderDraftParams struct {
+	ID               int32              `json:"id"`
+	Title            string             `json:"title"`
+	Description      pgtype.Text        `json:"description"`
+	StartPrice       float64            `json:"start_price"`
+	StartAt          pgtype.Timestamptz `json:"start_at"`
+	ConditionsPath   pgtype.Text        `json:"conditions_path"`
+	CurrentPrice     float64            `json:"current_price"`
+	Classification   pgtype.Text        `json:"classification"`
+	MinBidDecrease   float64            `json:"min_bid_decrease"`
+	MinBidStepType   string             `json:"min_bid_step_type"`
+	Type             string             `json:"type"`
+	EndAt            pgtype.Timestamptz `json:"end_at"`
+	ExtensionMinutes int32              `json:"extension_minutes"`
+}
+
+func (q *Queries) UpdateTenderDraft(ctx context.Context, arg UpdateTenderDraftParams) (Tender, error) {
+	row := q.db.QueryRow(ctx, updateTenderDraft,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.StartPrice,
+		arg.StartAt,
+		arg.ConditionsPath,
+		arg.CurrentPrice,
+		arg.Classification,
+		arg.MinBidDecrease,
+		arg.MinBidStepType,
+		arg.Type,
+		arg.EndAt,
+		arg.ExtensionMinutes,
+	)
+	var i Tender
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.StartPrice,
+		&i.StartAt,
+		&i.Status,
+		&i.ConditionsPath,
+		&i.CreatedAt,
+		&i.Classification,
+		&i.ParticipantsCount,
+		&i.MessageSent,
+		&i.LastBidAt,
+		&i.CurrentPrice,
+		&i.MinBidDecrease,
+		&i.ClosesAt,
+		&i.MinBidStepType,
+		&i.OrganizerID,
+		&i.Type,
+		&i.EndAt,
+		&i.ExtensionMinutes,
+	)
+	return i, err
 }
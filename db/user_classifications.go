@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetUserClassifications в одной транзакции заменяет классификации пользователя
+// списком кодов
+func (q *Queries) SetUserClassifications(ctx context.Context, userID int64, codes []string) error {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return fmt.Errorf("set user classifications: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	if err := qtx.DeleteUserClassifications(ctx, userID); err != nil {
+		return err
+	}
+	if err := qtx.AddUserClassifications(ctx, AddUserClassificationsParams{
+		UserID: userID,
+		Codes:  codes,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
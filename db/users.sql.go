@@ -20,10 +20,22 @@ func (q *Queries) BlockUser(ctx context.Context, telegramID int64) error {
 	return err
 }
 
+const countAdmins = `-- name: CountAdmins :one
+SELECT COUNT(*) FROM users
+WHERE role = 'admin' AND banned IS NOT TRUE
+`
+
+func (q *Queries) CountAdmins(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countAdmins)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (telegram_id, organization_name, inn, ogrn, phone_number, classification, role, banned, name)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING telegram_id, organization_name, inn, ogrn, phone_number, classification, role, banned, name
+INSERT INTO users (telegram_id, organization_name, inn, ogrn, phone_number, role, banned, name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING telegram_id, organization_name, inn, ogrn, phone_number, role, banned, name
 `
 
 type CreateUserParams struct {
@@ -32,7 +44,6 @@ type CreateUserParams struct {
 	Inn              pgtype.Text `json:"inn"`
 	Ogrn             pgtype.Text `json:"ogrn"`
 	PhoneNumber      pgtype.Text `json:"phone_number"`
-	Classification   pgtype.Text `json:"classification"`
 	Role             string      `json:"role"`
 	Banned           pgtype.Bool `json:"banned"`
 	Name             pgtype.Text `json:"name"`
@@ -45,7 +56,6 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Inn,
 		arg.Ogrn,
 		arg.PhoneNumber,
-		arg.Classification,
 		arg.Role,
 		arg.Banned,
 		arg.Name,
@@ -57,7 +67,6 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Inn,
 		&i.Ogrn,
 		&i.PhoneNumber,
-		&i.Classification,
 		&i.Role,
 		&i.Banned,
 		&i.Name,
@@ -66,7 +75,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 }
 
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT telegram_id, organization_name, inn, ogrn, phone_number, classification, role, banned, name FROM users WHERE role = 'supplier'
+SELECT telegram_id, organization_name, inn, ogrn, phone_number, role, banned, name FROM users ORDER BY role, telegram_id
 `
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
@@ -84,7 +93,6 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 			&i.Inn,
 			&i.Ogrn,
 			&i.PhoneNumber,
-			&i.Classification,
 			&i.Role,
 			&i.Banned,
 			&i.Name,
@@ -100,7 +108,7 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 }
 
 const getUserByTelegramID = `-- name: GetUserByTelegramID :one
-SELECT telegram_id, organization_name, inn, ogrn, phone_number, classification, role, banned, name
+SELECT telegram_id, organization_name, inn, ogrn, phone_number, role, banned, name
 FROM users
 WHERE telegram_id = $1
 `
@@ -114,7 +122,6 @@ func (q *Queries) GetUserByTelegramID(ctx context.Context, telegramID int64) (Us
 		&i.Inn,
 		&i.Ogrn,
 		&i.PhoneNumber,
-		&i.Classification,
 		&i.Role,
 		&i.Banned,
 		&i.Name,
@@ -122,13 +129,77 @@ func (q *Queries) GetUserByTelegramID(ctx context.Context, telegramID int64) (Us
 	return i, err
 }
 
-const getUsersByClassification = `-- name: GetUsersByClassification :many
-SELECT telegram_id FROM users 
-WHERE $1 = ANY(string_to_array(classification, ','))
+const getUserIDByInn = `-- name: GetUserIDByInn :one
+SELECT telegram_id FROM users
+WHERE inn = $1 AND telegram_id <> $2
+`
+
+type GetUserIDByInnParams struct {
+	Inn        pgtype.Text `json:"inn"`
+	TelegramID int64       `json:"telegram_id"`
+}
+
+func (q *Queries) GetUserIDByInn(ctx context.Context, arg GetUserIDByInnParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserIDByInn, arg.Inn, arg.TelegramID)
+	var telegram_id int64
+	err := row.Scan(&telegram_id)
+	return telegram_id, err
+}
+
+const getUserIDByOgrn = `-- name: GetUserIDByOgrn :one
+SELECT telegram_id FROM users
+WHERE ogrn = $1 AND telegram_id <> $2
 `
 
-func (q *Queries) GetUsersByClassification(ctx context.Context, classification pgtype.Text) ([]int64, error) {
-	rows, err := q.db.Query(ctx, getUsersByClassification, classification)
+type GetUserIDByOgrnParams struct {
+	Ogrn       pgtype.Text `json:"ogrn"`
+	TelegramID int64       `json:"telegram_id"`
+}
+
+func (q *Queries) GetUserIDByOgrn(ctx context.Context, arg GetUserIDByOgrnParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserIDByOgrn, arg.Ogrn, arg.TelegramID)
+	var telegram_id int64
+	err := row.Scan(&telegram_id)
+	return telegram_id, err
+}
+
+const getUserIDsByRole = `-- name: GetUserIDsByRole :many
+SELECT telegram_id FROM users
+WHERE role = $1 AND banned IS NOT TRUE
+ORDER BY telegram_id
+`
+
+func (q *Queries) GetUserIDsByRole(ctx context.Context, role string) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getUserIDsByRole, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var telegram_id int64
+		if err := rows.Scan(&telegram_id); err != nil {
+			return nil, err
+		}
+		items = append(items, telegram_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByTenderClassifications = `-- name: GetUsersByTenderClassifications :many
+SELECT DISTINCT u.telegram_id FROM users u
+JOIN user_classifications uc ON uc.user_id = u.telegram_id
+JOIN classifications s ON s.code = uc.classification_code
+JOIN classifications t ON s.code = t.code OR s.code = t.parent_code OR s.parent_code = t.code
+JOIN tender_classifications tc ON tc.classification_code = t.code
+WHERE tc.tender_id = $1
+`
+
+func (q *Queries) GetUsersByTenderClassifications(ctx context.Context, tenderID int32) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getUsersByTenderClassifications, tenderID)
 	if err != nil {
 		return nil, err
 	}
@@ -163,8 +234,7 @@ SET
     inn = $3,
     ogrn = $4,
     phone_number = $5,
-    name = $6,
-    classification = $7
+    name = $6
 WHERE telegram_id = $1
 `
 
@@ -175,7 +245,6 @@ type UpdateUserParams struct {
 	Ogrn             pgtype.Text `json:"ogrn"`
 	PhoneNumber      pgtype.Text `json:"phone_number"`
 	Name             pgtype.Text `json:"name"`
-	Classification   pgtype.Text `json:"classification"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
@@ -186,7 +255,65 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
 		arg.Ogrn,
 		arg.PhoneNumber,
 		arg.Name,
-		arg.Classification,
 	)
 	return err
 }
+
+const updateUserProfile = `-- name: UpdateUserProfile :exec
+UPDATE users
+SET
+    phone_number = $2,
+    name = $3
+WHERE telegram_id = $1
+`
+
+type UpdateUserProfileParams struct {
+	TelegramID  int64       `json:"telegram_id"`
+	PhoneNumber pgtype.Text `json:"phone_number"`
+	Name        pgtype.Text `json:"name"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) error {
+	_, err := q.db.Exec(ctx, updateUserProfile, arg.TelegramID, arg.PhoneNumber, arg.Name)
+	return err
+}
+
+const updateUserRequisites = `-- name: UpdateUserRequisites :exec
+UPDATE users
+SET
+    organization_name = $2,
+    inn = $3,
+    ogrn = $4
+WHERE telegram_id = $1
+`
+
+type UpdateUserRequisitesParams struct {
+	TelegramID       int64       `json:"telegram_id"`
+	OrganizationName pgtype.Text `json:"organization_name"`
+	Inn              pgtype.Text `json:"inn"`
+	Ogrn             pgtype.Text `json:"ogrn"`
+}
+
+func (q *Queries) UpdateUserRequisites(ctx context.Context, arg UpdateUserRequisitesParams) error {
+	_, err := q.db.Exec(ctx, updateUserRequisites,
+		arg.TelegramID,
+		arg.OrganizationName,
+		arg.Inn,
+		arg.Ogrn,
+	)
+	return err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users SET role = $2 WHERE telegram_id = $1
+`
+
+type UpdateUserRoleParams struct {
+	TelegramID int64  `json:"telegram_id"`
+	Role       string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error {
+	_, err := q.db.Exec(ctx, updateUserRole, arg.TelegramID, arg.Role)
+	return err
+}
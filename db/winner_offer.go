@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// Статус предложения победителю (winner_offers.status): ждёт ответа, принято,
+// отклонено участником, не подтверждено в срок или снято при отмене тендера
+const (
+	WinnerOfferPending   = "pending"
+	WinnerOfferAccepted  = "accepted"
+	WinnerOfferDeclined  = "declined"
+	WinnerOfferExpired   = "expired"
+	WinnerOfferCancelled = "cancelled"
+)
+
+// HistoryOutcomeOffered — запись архива о том, что лот предложен участнику.
+// Отказ и истечение срока записываются с outcome WinnerOfferDeclined и WinnerOfferExpired.
+// Шаги подтверждения хранятся в history для разбора, но в архив итогов
+// (GetTendersHistory, GetOrganizerTendersHistory) не попадают.
+const HistoryOutcomeOffered = "offered"
+
+// AcceptWinnerOffer атомарно принимает предложение победителю и завершает лот.
+// Возвращает false без ошибки, если предложение уже не ждёт ответа или лот больше
+// не ждёт подтверждения (например, тендер отменён): тогда ничего не меняется.
+func (q *Queries) AcceptWinnerOffer(ctx context.Context, offerID, lotID int32) (bool, error) {
+	starter, ok := q.db.(txStarter)
+	if !ok {
+		return false, fmt.Errorf("accept winner offer: connection does not support transactions")
+	}
+
+	tx, err := starter.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := q.WithTx(tx)
+
+	updated, err := qtx.RespondWinnerOffer(ctx, RespondWinnerOfferParams{
+		ID:     offerID,
+		Status: WinnerOfferAccepted,
+	})
+	if err != nil || updated == 0 {
+		return false, err
+	}
+
+	completed, err := qtx.CompleteConfirmingLot(ctx, lotID)
+	if err != nil || completed == 0 {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
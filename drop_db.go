@@ -35,9 +35,19 @@ func main() {
 	_, err = db.ExecContext(ctx, `
 		TRUNCATE TABLE 
 			history, 
+			winner_offers,
+			tender_events,
+			tender_reviews,
+			tender_template_lots,
+			tender_templates,
 			tender_bids, 
+			tender_lots,
 			tender_participants, 
 			pending_users,
+			supplier_documents,
+			conversation_states,
+			user_classifications,
+			tender_classifications,
 			tenders,
 			users
 		CASCADE;
@@ -52,8 +62,15 @@ func main() {
 		"tenders_id_seq",
 		"tender_participants_id_seq",
 		"tender_bids_id_seq",
+		"tender_lots_id_seq",
 		"history_id_seq",
+		"winner_offers_id_seq",
+		"tender_events_id_seq",
+		"tender_reviews_id_seq",
+		"tender_templates_id_seq",
+		"tender_template_lots_id_seq",
 		"pending_users_id_seq",
+		"supplier_documents_id_seq",
 	}
 
 	for _, seq := range sequences {
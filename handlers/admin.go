@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,12 +10,24 @@ import (
 	"strings"
 	"tender_bot_go/db"
 	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"tender_bot_go/tender"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gopkg.in/telebot.v3"
 )
 
+type AdminState int
+
+const (
+	AdminStateNone AdminState = iota
+	AdminStateRejectReason
+	AdminStateClassificationName
+	AdminStateClassificationRename
+)
+
 func RegisterAdminHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 	queries := db.New(pool)
 
@@ -22,10 +35,22 @@ func RegisterAdminHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 		return handleApproveTender(c, queries, bot)
 	})
 
+	bot.Handle(&telebot.InlineButton{Unique: "reject_tender"}, func(c telebot.Context) error {
+		return handleRejectTender(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "request_document_reupload"}, func(c telebot.Context) error {
+		return handleRequestDocumentReupload(c, queries)
+	})
+
 	bot.Handle(&telebot.InlineButton{Unique: "user_management"}, func(c telebot.Context) error {
 		return handleUserManagement(c, queries, bot)
 	})
 
+	bot.Handle(&telebot.InlineButton{Unique: "change_role"}, func(c telebot.Context) error {
+		return handleChangeRole(c, queries, bot)
+	})
+
 	bot.Handle(&telebot.InlineButton{Unique: "approve_registration"}, func(c telebot.Context) error {
 		return handleApproveRegistration(c, queries, bot)
 	})
@@ -33,19 +58,48 @@ func RegisterAdminHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 	bot.Handle(&telebot.InlineButton{Unique: "reject_registration"}, func(c telebot.Context) error {
 		return handleRejectRegistration(c, queries, bot)
 	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin"}, func(c telebot.Context) error {
+		return handleClassificationAdmin(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_list"}, func(c telebot.Context) error {
+		return handleClassificationAdminList(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_up"}, func(c telebot.Context) error {
+		return handleClassificationMove(c, queries, -1)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_down"}, func(c telebot.Context) error {
+		return handleClassificationMove(c, queries, 1)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_archive"}, func(c telebot.Context) error {
+		return handleClassificationArchive(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_add"}, func(c telebot.Context) error {
+		return handleClassificationAdd(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_rename"}, func(c telebot.Context) error {
+		return handleClassificationRename(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_group"}, func(c telebot.Context) error {
+		return handleClassificationGroup(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "class_admin_set_group"}, func(c telebot.Context) error {
+		return handleClassificationSetGroup(c, queries)
+	})
 }
 
 func handleApproveRegistration(c telebot.Context, queries *db.Queries, bot *telebot.Bot) error {
 	// Проверка прав администратора
 	userID := c.Sender().ID
-	isAdmin := false
-	for _, adminID := range config.AdminIDs {
-		if adminID == userID {
-			isAdmin = true
-			break
-		}
-	}
-	if !isAdmin {
+	if !isAdmin(userID, queries) {
 		return c.Respond(&telebot.CallbackResponse{
 			Text:      "❌ У вас нет прав для одобрения регистраций",
 			ShowAlert: true,
@@ -81,15 +135,34 @@ func handleApproveRegistration(c telebot.Context, queries *db.Queries, bot *tele
 		})
 	}
 
-	// Регистрируем пользователя
-	err = queries.UpdateUser(ctx, db.UpdateUserParams{
-		TelegramID:       targetUserID,
-		OrganizationName: pendingUser.OrganizationName,
-		Inn:              pendingUser.Inn,
-		PhoneNumber:      pendingUser.PhoneNumber,
-		Name:             pendingUser.Name,
-		Classification:   pendingUser.Classification,
-	})
+	// Регистрируем пользователя или меняем только реквизиты: телефон и классификации
+	// поставщик мог изменить, пока заявка ждала решения
+	if pendingUser.Kind == db.PendingKindRequisites {
+		err = queries.UpdateUserRequisites(ctx, db.UpdateUserRequisitesParams{
+			TelegramID:       targetUserID,
+			OrganizationName: pendingUser.OrganizationName,
+			Inn:              pendingUser.Inn,
+			Ogrn:             pendingUser.Ogrn,
+		})
+	} else {
+		err = queries.UpdateUser(ctx, db.UpdateUserParams{
+			TelegramID:       targetUserID,
+			OrganizationName: pendingUser.OrganizationName,
+			Inn:              pendingUser.Inn,
+			Ogrn:             pendingUser.Ogrn,
+			PhoneNumber:      pendingUser.PhoneNumber,
+			Name:             pendingUser.Name,
+		})
+		if err == nil {
+			err = queries.SetUserClassifications(ctx, targetUserID, splitCodes(pendingUser.Classification.String))
+		}
+	}
+	if isUniqueViolation(err) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ ИНН или ОГРН из заявки уже зарегистрирован у другого пользователя",
+			ShowAlert: true,
+		})
+	}
 	if err != nil {
 		fmt.Printf("Ошибка регистрации пользователя: %v\n", err)
 		return c.Respond(&telebot.CallbackResponse{
@@ -105,8 +178,12 @@ func handleApproveRegistration(c telebot.Context, queries *db.Queries, bot *tele
 	}
 
 	// Уведомляем пользователя
+	approvalMessage := "✅ *Ваша регистрация одобрена!*\n\nТеперь вы можете участвовать в тендерах."
+	if pendingUser.Kind == db.PendingKindRequisites {
+		approvalMessage = "✅ *Новые реквизиты одобрены!*\n\nОни уже сохранены в вашем профиле."
+	}
 	msg, err := bot.Send(&telebot.User{ID: targetUserID},
-		"✅ *Ваша регистрация одобрена!*\n\nТеперь вы можете участвовать в тендерах.",
+		approvalMessage,
 		&telebot.SendOptions{
 			ParseMode:   telebot.ModeMarkdown,
 			ReplyMarkup: menu.MenuSupplierRegistered,
@@ -137,14 +214,7 @@ func handleApproveRegistration(c telebot.Context, queries *db.Queries, bot *tele
 func handleRejectRegistration(c telebot.Context, queries *db.Queries, bot *telebot.Bot) error {
 	// Проверка прав администратора
 	userID := c.Sender().ID
-	isAdmin := false
-	for _, adminID := range config.AdminIDs {
-		if adminID == userID {
-			isAdmin = true
-			break
-		}
-	}
-	if !isAdmin {
+	if !isAdmin(userID, queries) {
 		return c.Respond(&telebot.CallbackResponse{
 			Text:      "❌ У вас нет прав для отклонения регистраций",
 			ShowAlert: true,
@@ -178,6 +248,11 @@ func handleRejectRegistration(c telebot.Context, queries *db.Queries, bot *teleb
 		// Все равно продолжаем, чтобы очистить запись
 	}
 
+	// Документы отклонённой заявки больше не нужны
+	if pendingUser.ID != 0 {
+		deletePendingUserDocuments(ctx, queries, pendingUser.ID)
+	}
+
 	// Удаляем pending запись
 	err = queries.ApprovePendingUser(ctx, targetUserID)
 	if err != nil {
@@ -191,10 +266,16 @@ func handleRejectRegistration(c telebot.Context, queries *db.Queries, bot *teleb
 	// Уведомляем пользователя об отклонении
 	rejectionMessage := "❌ *Ваша заявка на регистрацию отклонена администратором.*\n\n" +
 		"По вопросам обращайтесь к администрации."
+	rejectionMenu := menu.MenuSupplierUnregistered
+	if pendingUser.Kind == db.PendingKindRequisites {
+		rejectionMessage = "❌ *Изменение реквизитов отклонено администратором.*\n\n" +
+			"В профиле остались прежние данные. По вопросам обращайтесь к администрации."
+		rejectionMenu = menu.MenuSupplierRegistered
+	}
 
 	_, err = bot.Send(&telebot.User{ID: targetUserID}, rejectionMessage, &telebot.SendOptions{
 		ParseMode:   telebot.ModeMarkdown,
-		ReplyMarkup: menu.MenuSupplierUnregistered,
+		ReplyMarkup: rejectionMenu,
 	})
 	if err != nil {
 		fmt.Printf("Ошибка уведомления пользователя об отклонении: %v\n", err)
@@ -250,15 +331,7 @@ func handleRejectRegistration(c telebot.Context, queries *db.Queries, bot *teleb
 
 func handleUserManagement(c telebot.Context, queries *db.Queries, bot *telebot.Bot) error {
 	userID := c.Sender().ID
-	isAdmin := false
-	for _, adminID := range config.AdminIDs {
-		if adminID == userID {
-			isAdmin = true
-			break
-		}
-	}
-
-	if !isAdmin {
+	if !isAdmin(userID, queries) {
 		return c.Respond(&telebot.CallbackResponse{
 			Text:      "❌ У вас нет прав для управления пользователями",
 			ShowAlert: true,
@@ -287,10 +360,25 @@ func handleUserManagement(c telebot.Context, queries *db.Queries, bot *telebot.B
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	targetUser, err := queries.GetUserByTelegramID(ctx, targetUserID)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Пользователь не найден",
+			ShowAlert: true,
+		})
+	}
+
 	var resultMessage string
 
 	switch action {
 	case "block_user":
+		if isLastAdmin(ctx, queries, targetUser) {
+			return c.Respond(&telebot.CallbackResponse{
+				Text:      "❌ Нельзя заблокировать последнего администратора",
+				ShowAlert: true,
+			})
+		}
+
 		err = queries.BlockUser(ctx, targetUserID)
 		if err != nil {
 			fmt.Printf("Ошибка при блокировке пользователя %d: %v\n", targetUserID, err)
@@ -300,6 +388,7 @@ func handleUserManagement(c telebot.Context, queries *db.Queries, bot *telebot.B
 			})
 		}
 		resultMessage = "✅ Пользователь заблокирован"
+		targetUser.Banned = pgtype.Bool{Bool: true, Valid: true}
 
 		// Отправляем уведомление пользователю о блокировке
 		blockMessage := "🚫 *Ваш аккаунт был заблокирован администратором*\n\n" +
@@ -322,6 +411,7 @@ func handleUserManagement(c telebot.Context, queries *db.Queries, bot *telebot.B
 			})
 		}
 		resultMessage = "✅ Пользователь разблокирован"
+		targetUser.Banned = pgtype.Bool{Bool: false, Valid: true}
 
 		// Отправляем уведомление пользователю о разблокировке
 		unblockMessage := "✅ *Ваш аккаунт был разблокирован*\n\n" +
@@ -341,49 +431,222 @@ func handleUserManagement(c telebot.Context, queries *db.Queries, bot *telebot.B
 		})
 	}
 
-	// Обновляем кнопку в сообщении
-	var newButtonText string
-	var newButtonData string
+	// Обновляем кнопки в сообщении
+	_, err = c.Bot().EditReplyMarkup(c.Message(), userManagementKeyboard(targetUser))
+	if err != nil {
+		fmt.Printf("Ошибка при обновлении кнопки: %v\n", err)
+	}
 
-	if action == "block_user" {
-		newButtonText = "🔓 Разблокировать"
-		newButtonData = fmt.Sprintf("unblock_user|%d", targetUserID)
-	} else {
-		newButtonText = "🚫 Заблокировать"
-		newButtonData = fmt.Sprintf("block_user|%d", targetUserID)
+	return c.Respond(&telebot.CallbackResponse{
+		Text: resultMessage,
+	})
+}
+
+var roleNames = map[string]string{
+	"supplier":  "Поставщик",
+	"organizer": "Организатор",
+	"admin":     "Администратор",
+}
+
+// roleMenu возвращает главное меню, соответствующее роли пользователя
+func roleMenu(user db.User) *telebot.ReplyMarkup {
+	switch user.Role {
+	case "organizer":
+		return menu.MenuOrganizer
+	case "admin":
+		return menu.MenuAdmin
 	}
+	if user.OrganizationName.Valid && user.OrganizationName.String != "" {
+		return menu.MenuSupplierRegistered
+	}
+	return menu.MenuSupplierUnregistered
+}
 
-	updatedButton := telebot.InlineButton{
+// isLastAdmin сообщает, что пользователь — единственный незаблокированный администратор.
+// Такого администратора нельзя понизить или заблокировать, иначе панель станет недоступна.
+func isLastAdmin(ctx context.Context, queries *db.Queries, user db.User) bool {
+	if user.Role != "admin" || user.Banned.Bool {
+		return false
+	}
+	admins, err := queries.CountAdmins(ctx)
+	if err != nil {
+		fmt.Printf("Ошибка подсчёта администраторов: %v\n", err)
+		return true
+	}
+	return admins <= 1
+}
+
+// userManagementKeyboard — кнопки блокировки и смены роли под карточкой пользователя
+func userManagementKeyboard(user db.User) *telebot.ReplyMarkup {
+	blockButton := telebot.InlineButton{
 		Unique: "user_management",
-		Text:   newButtonText,
-		Data:   newButtonData,
+		Text:   "🚫 Заблокировать",
+		Data:   fmt.Sprintf("block_user|%d", user.TelegramID),
+	}
+	if user.Banned.Bool {
+		blockButton.Text = "🔓 Разблокировать"
+		blockButton.Data = fmt.Sprintf("unblock_user|%d", user.TelegramID)
 	}
 
-	_, err = c.Bot().EditReplyMarkup(c.Message(), &telebot.ReplyMarkup{
+	roleButtons := []telebot.InlineButton{}
+	for _, role := range []string{"supplier", "organizer", "admin"} {
+		if role == user.Role {
+			continue
+		}
+		roleButtons = append(roleButtons, telebot.InlineButton{
+			Unique: "change_role",
+			Text:   "→ " + roleNames[role],
+			Data:   fmt.Sprintf("%s|%d", role, user.TelegramID),
+		})
+	}
+
+	return &telebot.ReplyMarkup{
 		InlineKeyboard: [][]telebot.InlineButton{
-			{updatedButton},
+			{blockButton},
+			roleButtons,
 		},
+	}
+}
+
+func handleChangeRole(c telebot.Context, queries *db.Queries, bot *telebot.Bot) error {
+	userID := c.Sender().ID
+	if !isAdmin(userID, queries) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ У вас нет прав для изменения ролей",
+			ShowAlert: true,
+		})
+	}
+
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный формат данных",
+			ShowAlert: true,
+		})
+	}
+
+	newRole := parts[0]
+	if _, ok := roleNames[newRole]; !ok {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Неизвестная роль",
+			ShowAlert: true,
+		})
+	}
+
+	targetUserID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID пользователя",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	targetUser, err := queries.GetUserByTelegramID(ctx, targetUserID)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Пользователь не найден",
+			ShowAlert: true,
+		})
+	}
+
+	if targetUser.Role == newRole {
+		return c.Respond(&telebot.CallbackResponse{
+			Text: "Эта роль уже назначена",
+		})
+	}
+
+	if isLastAdmin(ctx, queries, targetUser) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Нельзя снять роль с последнего администратора",
+			ShowAlert: true,
+		})
+	}
+
+	err = queries.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		TelegramID: targetUserID,
+		Role:       newRole,
 	})
+	if err != nil {
+		fmt.Printf("Ошибка при смене роли пользователя %d: %v\n", targetUserID, err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось изменить роль",
+			ShowAlert: true,
+		})
+	}
+	targetUser.Role = newRole
+
+	// Незавершённые диалоги относятся к прежней роли
+	for _, flow := range []state.Flow{state.FlowOrganizer, state.FlowSupplier, state.FlowBid} {
+		clearConversation(targetUserID, flow)
+	}
+
+	// Уведомляем пользователя и сразу показываем меню новой роли
+	msg, err := bot.Send(&telebot.User{ID: targetUserID},
+		fmt.Sprintf("🔄 *Ваша роль изменена:* %s", roleNames[newRole]),
+		&telebot.SendOptions{
+			ParseMode:   telebot.ModeMarkdown,
+			ReplyMarkup: roleMenu(targetUser),
+		})
+	if err != nil {
+		fmt.Printf("Ошибка уведомления пользователя %d о смене роли: %v\n", targetUserID, err)
+	} else {
+		MessageManagerOperator.AddMessage(targetUserID, msg.ID)
+	}
+
+	_, err = c.Bot().EditReplyMarkup(c.Message(), userManagementKeyboard(targetUser))
 	if err != nil {
 		fmt.Printf("Ошибка при обновлении кнопки: %v\n", err)
 	}
 
 	return c.Respond(&telebot.CallbackResponse{
-		Text: resultMessage,
+		Text: "✅ Роль изменена: " + roleNames[newRole],
 	})
 }
 
 func HandleAdminText(c telebot.Context, queries *db.Queries, text string, userID int64) error {
 	// Админ обычно работает через inline кнопки
 	if text == "Пользователи" {
+		clearConversation(userID, state.FlowAdmin)
 		return sendListOfUsers(c, queries)
 	}
 	if text == "История" {
+		clearConversation(userID, state.FlowAdmin)
 		return sendAdminHistory(c, queries)
 	}
 	if text == "Заявки на регистрацию" {
+		clearConversation(userID, state.FlowAdmin)
 		return sendPendingRegistrations(c, queries)
 	}
+	if text == "Классификации" {
+		clearConversation(userID, state.FlowAdmin)
+		return sendClassificationsAdmin(c, queries, false)
+	}
+	if text == "Отмена" {
+		clearConversation(userID, state.FlowAdmin)
+		return c.Send("Действие отменено.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuAdmin,
+		})
+	}
+
+	conv, _ := loadConversation(userID, state.FlowAdmin)
+	switch AdminState(conv.Step) {
+	case AdminStateRejectReason:
+		if err := tender.ValidateRejectReason(text); err != nil {
+			return c.Send(err.Error()+". Введите причину отклонения:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuAdminCancel,
+			})
+		}
+		tenderID, _ := strconv.ParseInt(conv.Get("reject_tender_id"), 10, 32)
+		clearConversation(userID, state.FlowAdmin)
+		return rejectTender(c, queries, int32(tenderID), text)
+	case AdminStateClassificationName:
+		return saveClassificationName(c, queries, "", conv.Get("classification_parent"), text)
+	case AdminStateClassificationRename:
+		return saveClassificationName(c, queries, conv.Get("classification_code"), "", text)
+	}
 
 	return nil
 
@@ -417,30 +680,26 @@ func sendPendingRegistrations(c telebot.Context, queries *db.Queries) error {
 	// Отправляем каждую заявку отдельным сообщением
 	for i, pendingUser := range pendingUsers {
 		// Форматируем классификации
-		classifications := strings.Split(pendingUser.Classification.String, ",")
-		var classificationNamesList []string
-		for _, code := range classifications {
-			if name, exists := classificationNames[code]; exists {
-				classificationNamesList = append(classificationNamesList, name)
-			}
-		}
 
 		userInfo := fmt.Sprintf(
-			"🆕 *Заявка #%d*\n\n"+
+			"🆕 *Заявка #%d* — %s\n\n"+
 				"👤 *ID пользователя:* %d\n"+
 				"🏢 *Организация:* %s\n"+
 				"🆔 *ИНН:* %s\n"+
+				"🧾 *ОГРН:* %s\n"+
 				"📞 *Телефон:* %s\n"+
 				"👨‍💼 *ФИО:* %s\n"+
 				"🗂️ *Классификации:* %s\n"+
 				"⏰ *Подана:* %s",
 			i+1,
+			pendingKindNames[pendingUser.Kind],
 			pendingUser.TelegramID,
 			pendingUser.OrganizationName.String,
 			pendingUser.Inn.String,
+			pendingOGRN(pendingUser),
 			pendingUser.PhoneNumber.String,
 			pendingUser.Name.String,
-			strings.Join(classificationNamesList, ", "),
+			classificationList(pendingUser.Classification.String),
 			pendingUser.CreatedAt.Time.Format("02.01.2006 15:04"),
 		)
 
@@ -469,6 +728,7 @@ func sendPendingRegistrations(c telebot.Context, queries *db.Queries) error {
 			fmt.Printf("Ошибка при отправке информации о заявке: %v\n", err)
 			continue
 		}
+		sendSupplierDocuments(c.Bot(), c.Recipient(), pendingUserDocuments(queries, pendingUser.ID))
 
 		time.Sleep(300 * time.Millisecond)
 	}
@@ -512,15 +772,9 @@ func sendListOfUsers(c telebot.Context, queries *db.Queries) error {
 			status = "❌ Заблокирован"
 		}
 
-		classifications := strings.Split(user.Classification.String, ",")
-		var classification1, classification2 string
-		if len(classifications) > 0 {
-			classification1 = classificationNames[classifications[0]]
-		}
-		if len(classifications) > 1 {
-			classification2 = classificationNames[classifications[1]]
-		} else {
-			classification2 = "не указана" // или какое-то значение по умолчанию
+		codes, err := userClassificationCodes(ctx, queries, user.TelegramID)
+		if err != nil {
+			fmt.Printf("Ошибка получения классификаций пользователя %d: %v\n", user.TelegramID, err)
 		}
 
 		// Формируем информацию о пользователе
@@ -530,46 +784,23 @@ func sendListOfUsers(c telebot.Context, queries *db.Queries) error {
 				"📞 *Телефон:* %s\n"+
 				"🆔 *ИНН:* %s\n"+
 				"👨‍💼 *ФИО:* %s\n"+
-				"🗂️ *Классификации:* %s, %s\n"+
+				"🗂️ *Классификации:* %s\n"+
+				"🎭 *Роль:* %s\n"+
 				"🔒 *Статус:* %s",
 			i+1,
 			user.OrganizationName.String,
 			user.PhoneNumber.String,
 			user.Inn.String,
 			user.Name.String,
-			classification1,
-			classification2,
+			classificationList(codes),
+			roleNames[user.Role],
 			status,
 		)
 
-		// Создаем кнопку в зависимости от статуса
-		var buttonText string
-		var buttonData string
-
-		if user.Banned.Bool {
-			buttonText = "🔓 Разблокировать"
-			buttonData = fmt.Sprintf("unblock_user|%d", user.TelegramID)
-		} else {
-			buttonText = "🚫 Заблокировать"
-			buttonData = fmt.Sprintf("block_user|%d", user.TelegramID)
-		}
-
-		inlineKeyboard := [][]telebot.InlineButton{
-			{
-				{
-					Unique: "user_management",
-					Text:   buttonText,
-					Data:   buttonData,
-				},
-			},
-		}
-
-		// Отправляем сообщение с пользователем и кнопкой
+		// Отправляем сообщение с пользователем и кнопками
 		if err := c.Send(userInfo, &telebot.SendOptions{
-			ParseMode: telebot.ModeMarkdown,
-			ReplyMarkup: &telebot.ReplyMarkup{
-				InlineKeyboard: inlineKeyboard,
-			},
+			ParseMode:   telebot.ModeMarkdown,
+			ReplyMarkup: userManagementKeyboard(user),
 		}); err != nil {
 			fmt.Printf("Ошибка при отправке информации о пользователе %d: %v\n", user.TelegramID, err)
 			continue
@@ -586,15 +817,7 @@ func sendListOfUsers(c telebot.Context, queries *db.Queries) error {
 
 func handleApproveTender(c telebot.Context, queries *db.Queries, bot *telebot.Bot) error {
 	userID := c.Sender().ID
-	isAdmin := false
-	for _, adminID := range config.AdminIDs {
-		if adminID == userID {
-			isAdmin = true
-			break
-		}
-	}
-
-	if !isAdmin {
+	if !isAdmin(userID, queries) {
 		return c.Respond(&telebot.CallbackResponse{
 			Text:      "❌ У вас нет прав для одобрения тендеров",
 			ShowAlert: true,
@@ -611,7 +834,6 @@ func handleApproveTender(c telebot.Context, queries *db.Queries, bot *telebot.Bo
 	}
 
 	tenderIDStr := parts[0]
-	tenderTitle := parts[1]
 	tenderID, err := strconv.ParseInt(tenderIDStr, 10, 32)
 	if err != nil {
 		return c.Respond(&telebot.CallbackResponse{
@@ -623,7 +845,13 @@ func handleApproveTender(c telebot.Context, queries *db.Queries, bot *telebot.Bo
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = queries.ApproveTender(ctx, int32(tenderID))
+	_, err = tender.Review(ctx, queries, int32(tenderID), tender.ReviewApproved, userID, "одобрен администратором")
+	if errors.Is(err, tender.ErrStatusChanged) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "Тендер уже одобрен или не ожидает одобрения",
+			ShowAlert: true,
+		})
+	}
 	if err != nil {
 		fmt.Printf("Ошибка при одобрении тендера: %v\n", err)
 		return c.Respond(&telebot.CallbackResponse{
@@ -647,24 +875,140 @@ func handleApproveTender(c telebot.Context, queries *db.Queries, bot *telebot.Bo
 		fmt.Printf("Ошибка при обновлении кнопки: %v\n", err)
 	}
 
-    for _, organizer := range config.OrganizerIDs {
-        _, err = c.Bot().Send(&telebot.User{ID: organizer},
-            fmt.Sprintf("✅ Тендер \"%s\" успешно одобрен!", tenderTitle))
-        if err != nil {
-            fmt.Printf("Ошибка отправки уведомления организатору: %v", err)
-        }
-    }
+	notifyTenderApproved(bot, queries, int32(tenderID))
+
+	return c.Respond(&telebot.CallbackResponse{
+		Text: "✅ Тендер успешно одобрен!",
+	})
+}
+
+// handleRejectTender начинает отклонение тендера: спрашивает у администратора причину,
+// которую получит организатор
+func handleRejectTender(c telebot.Context, queries *db.Queries) error {
+	userID := c.Sender().ID
+	if !isAdmin(userID, queries) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ У вас нет прав для отклонения тендеров",
+			ShowAlert: true,
+		})
+	}
+
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pending, err := queries.GetTender(ctx, int32(tenderID))
+	if err != nil || pending.Status != tender.StatusPendingApproval {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "Тендер уже рассмотрен или не ожидает одобрения",
+			ShowAlert: true,
+		})
+	}
 
-	
+	conv := state.Conversation{Step: int(AdminStateRejectReason)}
+	conv.Put("reject_tender_id", strconv.Itoa(int(pending.ID)))
+	saveConversation(userID, state.FlowAdmin, conv)
 
-	tender, err := queries.GetTenderById(ctx, int32(tenderID))
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(fmt.Sprintf("🚫 *Отклонение тендера «%s»*\n\nНапишите причину отклонения — её получит организатор:", pending.Title), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuAdminCancel,
+	})
+}
+
+// rejectTender отклоняет тендер с причиной, сохраняет раунд модерации и сообщает
+// организатору, что тендер можно исправить и отправить повторно
+func rejectTender(c telebot.Context, queries *db.Queries, tenderID int32, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userID := c.Sender().ID
+	review, err := tender.Review(ctx, queries, tenderID, tender.ReviewRejected, userID, reason)
+	if errors.Is(err, tender.ErrStatusChanged) {
+		return c.Send("Тендер уже рассмотрен или не ожидает одобрения", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuAdmin,
+		})
+	}
 	if err != nil {
-		fmt.Printf("Ошибка получения нового тендера")
+		fmt.Printf("Ошибка при отклонении тендера %d: %v\n", tenderID, err)
+		return c.Send("❌ Не удалось отклонить тендер", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuAdmin,
+		})
 	}
-	userIds, err := queries.GetUsersByClassification(ctx, tender.Classification)
+
+	rejected, err := queries.GetTender(ctx, tenderID)
 	if err != nil {
-		fmt.Printf("Ошибка получения userIds")
+		fmt.Printf("Ошибка получения тендера %d: %v\n", tenderID, err)
+	} else {
+		message := fmt.Sprintf(
+			"🚫 *Тендер отклонён администратором*\n\n"+
+				"📋 *Название:* %s\n"+
+				"❗ *Причина:* %s\n\n"+
+				"✏️ Исправьте тендер и отправьте его на модерацию повторно.",
+			rejected.Title,
+			escapeMarkdown(reason),
+		)
+		for _, organizer := range tenderOrganizerIDs(queries, rejected.OrganizerID) {
+			_, err := c.Bot().Send(&telebot.User{ID: organizer}, message, &telebot.SendOptions{
+				ParseMode: telebot.ModeMarkdown,
+				ReplyMarkup: &telebot.ReplyMarkup{
+					InlineKeyboard: [][]telebot.InlineButton{
+						{
+							{Unique: "resubmit_tender", Text: "✏️ Исправить и отправить повторно", Data: strconv.Itoa(int(tenderID))},
+						},
+					},
+				},
+			})
+			if err != nil {
+				fmt.Printf("Ошибка отправки уведомления организатору %d: %v\n", organizer, err)
+			}
+		}
+	}
+
+	return c.Send(fmt.Sprintf("🚫 Тендер отклонён (раунд модерации №%d). Организатор получил причину.", review.Round), &telebot.SendOptions{
+		ReplyMarkup: menu.MenuAdmin,
+	})
+}
+
+// NotifyTenderApproved рассылает уведомления об одобренном тендере его организатору
+// и поставщикам подходящей классификации. Используется REST API после одобрения.
+func NotifyTenderApproved(bot *telebot.Bot, pool *pgxpool.Pool, tenderID int32) {
+	notifyTenderApproved(bot, db.New(pool), tenderID)
+}
+
+func notifyTenderApproved(bot *telebot.Bot, queries *db.Queries, tenderID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tender, err := queries.GetTenderById(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения нового тендера: %v\n", err)
+		return
+	}
+
+	for _, organizer := range tenderOrganizerIDs(queries, tender.OrganizerID) {
+		_, err = bot.Send(&telebot.User{ID: organizer},
+			fmt.Sprintf("✅ Тендер \"%s\" успешно одобрен!", tender.Title))
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления организатору: %v\n", err)
+		}
+	}
+
+	// Тендер получают поставщики, чья классификация совпадает хотя бы с одним лотом
+	userIds, err := queries.GetUsersByTenderClassifications(ctx, tender.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения userIds: %v\n", err)
 	}
+	lots := tenderLots(queries, tender.ID)
 
 	formattedPrice := formatPriceFloat(tender.StartPrice)
 	var formattedDate string
@@ -678,14 +1022,16 @@ func handleApproveTender(c telebot.Context, queries *db.Queries, bot *telebot.Bo
 		"📋 *Доступен новый тендер:* %s\n\n"+
 			"📝 *Описание:* %s\n"+
 			"💰 *Стартовая цена:* %s руб.\n"+
+			"%s"+
 			"📅 *Дата начала:* %s\n"+
 			"🗂️ *Классификация:* %s\n",
 
 		tender.Title,
 		tender.Description.String,
 		formattedPrice,
+		formatTenderTerms(tender, lots),
 		formattedDate,
-		classificationNames[tender.Classification.String],
+		tenderClassificationNames(queries, tender, lots),
 	)
 
 	successCount := 0
@@ -765,10 +1111,6 @@ func handleApproveTender(c telebot.Context, queries *db.Queries, bot *telebot.Bo
 		// Небольшая задержка между отправками
 		time.Sleep(100 * time.Millisecond)
 	}
-
-	return c.Respond(&telebot.CallbackResponse{
-		Text: "✅ Тендер успешно одобрен!",
-	})
 }
 
 func sendAdminHistory(c telebot.Context, queries *db.Queries) error {
@@ -789,9 +1131,9 @@ func sendAdminHistory(c telebot.Context, queries *db.Queries) error {
 		})
 	}
 	for _, tender := range tenders {
-		bidsHistory, err := queries.GetBidsHistoryByTenderID(ctx, tender.TenderID)
+		bidsHistory, err := queries.GetBidsHistoryByLotID(ctx, tender.LotID)
 		if err != nil {
-			fmt.Printf("Ошибка получения истории ставок для тендера %d: %v\n", tender.TenderID, err)
+			fmt.Printf("Ошибка получения истории ставок для лота %d: %v\n", tender.LotID, err)
 		}
 
 		var bidsHistoryText string
@@ -813,31 +1155,8 @@ func sendAdminHistory(c telebot.Context, queries *db.Queries) error {
 			bidsHistoryText = "\n\n📊 *История ставок:*\nСтавки отсутствуют"
 		}
 
-		// Форматируем цену в финансовом формате
-		formattedPrice := formatPriceFloat(tender.StartPrice)
-
-		formattedBidPrice := formatPriceFloat(tender.Bid)
-
 		// Создаем сообщение с информацией о тендере
-		tenderInfo := fmt.Sprintf(
-			"📋 *Тендер*: %s\n\n"+
-				"💰 *Стартовая цена:* %s руб.\n"+
-				"💰 *Выигрышная ставка:* %s руб.\n"+
-				"👑 Победитель: %s\n"+
-				"📞 Контакты победителя:\n"+
-				"   • Телефон: %s\n"+
-				"   • ИНН: %s\n"+
-				"   • ФИО: %s\n"+
-				"%s",
-			tender.Title,
-			formattedPrice,
-			formattedBidPrice,
-			tender.Winner.String,
-			tender.PhoneNumber.String,
-			tender.Inn.String,
-			tender.Fio.String,
-			bidsHistoryText,
-		)
+		tenderInfo := formatHistoryEntry(tender, bidsHistoryText)
 
 		// Отправляем информацию о тендере
 		if err := c.Send(tenderInfo, &telebot.SendOptions{
@@ -858,7 +1177,7 @@ func sendAdminHistory(c telebot.Context, queries *db.Queries) error {
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	return c.Send(fmt.Sprintf("✅ Всего тендеров: %d", len(tenders)), &telebot.SendOptions{
+	return c.Send(fmt.Sprintf("✅ Всего тендеров: %d", historyTenderCount(tenders)), &telebot.SendOptions{
 		ReplyMarkup: menu.MenuAdmin,
 	})
 }
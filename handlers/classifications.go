@@ -0,0 +1,723 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"tender_bot_go/db"
+	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+)
+
+// maxClassificationNameLength — наибольшая длина названия классификации (classifications.name)
+const maxClassificationNameLength = 100
+
+// Копия названий классификаций и их групп в памяти для подписей в сообщениях.
+// Клавиатуры строятся по свежему списку из БД, и каждая загрузка списка обновляет копию.
+var classificationCatalog = struct {
+	sync.RWMutex
+	names   map[string]string
+	parents map[string]string
+}{
+	names:   make(map[string]string),
+	parents: make(map[string]string),
+}
+
+// loadClassifications читает справочник классификаций, включая архивные, и обновляет
+// названия в памяти. Список упорядочен деревом: группа, затем её подкатегории
+func loadClassifications(queries *db.Queries) []db.Classification {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	classifications, err := queries.ListClassifications(ctx)
+	if err != nil {
+		fmt.Printf("Ошибка загрузки классификаций: %v\n", err)
+		return nil
+	}
+
+	names := make(map[string]string, len(classifications))
+	parents := make(map[string]string)
+	for _, classification := range classifications {
+		names[classification.Code] = classification.Name
+		if classification.ParentCode.Valid {
+			parents[classification.Code] = classification.ParentCode.String
+		}
+	}
+	classificationCatalog.Lock()
+	classificationCatalog.names = names
+	classificationCatalog.parents = parents
+	classificationCatalog.Unlock()
+
+	return sortClassificationTree(classifications)
+}
+
+// sortClassificationTree располагает классификации деревом: за каждой классификацией
+// верхнего уровня идут её подкатегории. Внутри уровня сохраняется порядок справочника
+func sortClassificationTree(classifications []db.Classification) []db.Classification {
+	known := make(map[string]bool, len(classifications))
+	for _, classification := range classifications {
+		known[classification.Code] = true
+	}
+
+	var roots []db.Classification
+	children := make(map[string][]db.Classification)
+	for _, classification := range classifications {
+		parent := classification.ParentCode.String
+		if classification.ParentCode.Valid && known[parent] {
+			children[parent] = append(children[parent], classification)
+		} else {
+			roots = append(roots, classification)
+		}
+	}
+
+	sorted := make([]db.Classification, 0, len(classifications))
+	for _, root := range roots {
+		sorted = append(sorted, root)
+		sorted = append(sorted, children[root.Code]...)
+	}
+	return sorted
+}
+
+// activeClassifications возвращает классификации, которые можно выбрать: без архивных
+// и без подкатегорий архивных групп
+func activeClassifications(queries *db.Queries) []db.Classification {
+	classifications := loadClassifications(queries)
+	archived := make(map[string]bool)
+	for _, classification := range classifications {
+		if classification.Archived {
+			archived[classification.Code] = true
+		}
+	}
+
+	var active []db.Classification
+	for _, classification := range classifications {
+		if !classification.Archived && !archived[classification.ParentCode.String] {
+			active = append(active, classification)
+		}
+	}
+	return active
+}
+
+// childCounts возвращает число подкатегорий каждой группы
+func childCounts(classifications []db.Classification) map[string]int {
+	counts := make(map[string]int)
+	for _, classification := range classifications {
+		if classification.ParentCode.Valid {
+			counts[classification.ParentCode.String]++
+		}
+	}
+	return counts
+}
+
+// lookupClassification возвращает название классификации по коду
+func lookupClassification(code string) (string, bool) {
+	classificationCatalog.RLock()
+	defer classificationCatalog.RUnlock()
+	name, ok := classificationCatalog.names[code]
+	return name, ok
+}
+
+// classificationName возвращает название классификации по коду или пустую строку
+func classificationName(code string) string {
+	name, _ := lookupClassification(code)
+	return name
+}
+
+// classificationTitle возвращает название классификации вместе с группой:
+// «Инженерные системы → Отопление»
+func classificationTitle(code string) string {
+	classificationCatalog.RLock()
+	defer classificationCatalog.RUnlock()
+	name := classificationCatalog.names[code]
+	if parent, ok := classificationCatalog.parents[code]; ok {
+		if parentName, ok := classificationCatalog.names[parent]; ok {
+			return parentName + " → " + name
+		}
+	}
+	return name
+}
+
+// tenderClassificationCodes возвращает коды классификаций тендера; при ошибке — пустой список
+func tenderClassificationCodes(queries *db.Queries, tenderID int32) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	classifications, err := queries.GetTenderClassifications(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения классификаций тендера %d: %v\n", tenderID, err)
+		return nil
+	}
+	codes := make([]string, 0, len(classifications))
+	for _, classification := range classifications {
+		codes = append(codes, classification.Code)
+	}
+	return codes
+}
+
+// toggleClassification добавляет код в выбор через запятую или убирает его оттуда и
+// возвращает выбор в порядке дерева. Выбранная группа охватывает свои подкатегории,
+// поэтому они из выбора убираются, а отдельно их выбрать нельзя
+func toggleClassification(classifications []db.Classification, selected string, code string) (string, error) {
+	selectedSet := make(map[string]bool)
+	for _, selectedCode := range splitCodes(selected) {
+		selectedSet[selectedCode] = true
+	}
+
+	var target *db.Classification
+	for i := range classifications {
+		if classifications[i].Code == code {
+			target = &classifications[i]
+		}
+	}
+	if target == nil {
+		return selected, errors.New("Классификация недоступна, откройте список заново")
+	}
+
+	if selectedSet[code] {
+		delete(selectedSet, code)
+	} else {
+		if target.ParentCode.Valid && selectedSet[target.ParentCode.String] {
+			return selected, fmt.Errorf("«%s» уже входит в выбранную группу «%s»", target.Name, classificationName(target.ParentCode.String))
+		}
+		selectedSet[code] = true
+		for _, classification := range classifications {
+			if classification.ParentCode.String == code {
+				delete(selectedSet, classification.Code)
+			}
+		}
+	}
+
+	// Архивные классификации в список не входят и из выбора выпадают
+	var codes []string
+	for _, classification := range classifications {
+		if selectedSet[classification.Code] {
+			codes = append(codes, classification.Code)
+		}
+	}
+	return strings.Join(codes, ","), nil
+}
+
+// classificationButtons строит кнопки выбора классификаций деревом. Подкатегории
+// выбранной группы отмечаются вместе с ней
+func classificationButtons(classifications []db.Classification, selected string, unique string) [][]telebot.InlineButton {
+	selectedSet := make(map[string]bool)
+	for _, code := range splitCodes(selected) {
+		selectedSet[code] = true
+	}
+	counts := childCounts(classifications)
+
+	var rows [][]telebot.InlineButton
+	for _, classification := range classifications {
+		text := classification.Name
+		if counts[classification.Code] > 0 {
+			text = "📁 " + text
+		}
+		if classification.ParentCode.Valid {
+			text = "↳ " + text
+		}
+		switch {
+		case selectedSet[classification.Code]:
+			text = "✅ " + text
+		case selectedSet[classification.ParentCode.String]:
+			text = "☑️ " + text
+		}
+		rows = append(rows, []telebot.InlineButton{
+			{Unique: unique, Text: text, Data: classification.Code},
+		})
+	}
+	return rows
+}
+
+// userClassificationCodes возвращает коды классификаций пользователя через запятую,
+// в том виде, в котором их хранит диалог выбора
+func userClassificationCodes(ctx context.Context, queries *db.Queries, userID int64) (string, error) {
+	classifications, err := queries.GetUserClassifications(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	codes := make([]string, 0, len(classifications))
+	for _, classification := range classifications {
+		codes = append(codes, classification.Code)
+	}
+	return strings.Join(codes, ","), nil
+}
+
+// splitCodes разбирает коды классификаций через запятую, пропуская пустые
+func splitCodes(codes string) []string {
+	var result []string
+	for _, code := range strings.Split(codes, ",") {
+		if code != "" {
+			result = append(result, code)
+		}
+	}
+	return result
+}
+
+// sendClassificationsAdmin показывает админу справочник классификаций деревом: каждая
+// открывается кнопкой с действиями, последняя кнопка добавляет новую
+func sendClassificationsAdmin(c telebot.Context, queries *db.Queries, edit bool) error {
+	classifications := loadClassifications(queries)
+	counts := childCounts(classifications)
+
+	var rows [][]telebot.InlineButton
+	for _, classification := range classifications {
+		text := classification.Name
+		if counts[classification.Code] > 0 {
+			text = "📁 " + text
+		}
+		if classification.Archived {
+			text = "📦 " + text
+		}
+		if classification.ParentCode.Valid {
+			text = "↳ " + text
+		}
+		rows = append(rows, []telebot.InlineButton{
+			{Unique: "class_admin", Text: text, Data: classification.Code},
+		})
+	}
+	rows = append(rows, []telebot.InlineButton{
+		{Unique: "class_admin_add", Text: "➕ Добавить классификацию"},
+	})
+
+	message := "🗂️ *Классификации*\n\nПорядок списка — порядок кнопок у организаторов и поставщиков. Группы (📁) объединяют подкатегории (↳): поставщик, выбравший группу, видит тендеры всех её подкатегорий. Архивные (📦) нельзя выбрать для новых тендеров и регистраций, но они остаются у существующих."
+	options := &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: rows},
+	}
+	if edit {
+		return c.Edit(message, options)
+	}
+	return c.Send(message, options)
+}
+
+// sendClassificationActions показывает действия с одной классификацией
+func sendClassificationActions(c telebot.Context, queries *db.Queries, classification db.Classification) error {
+	children := childCounts(loadClassifications(queries))[classification.Code]
+
+	status := "✅ Доступна для выбора"
+	archiveButton := telebot.InlineButton{Unique: "class_admin_archive", Text: "📦 В архив", Data: classification.Code}
+	if classification.Archived {
+		status = "📦 В архиве"
+		archiveButton = telebot.InlineButton{Unique: "class_admin_archive", Text: "♻️ Вернуть из архива", Data: classification.Code}
+	}
+
+	rows := [][]telebot.InlineButton{
+		{
+			{Unique: "class_admin_up", Text: "⬆️ Выше", Data: classification.Code},
+			{Unique: "class_admin_down", Text: "⬇️ Ниже", Data: classification.Code},
+		},
+		{
+			{Unique: "class_admin_rename", Text: "✏️ Переименовать", Data: classification.Code},
+			archiveButton,
+		},
+	}
+
+	// Дерево двухуровневое: подкатегории добавляются только к классификациям верхнего
+	// уровня, а группу с подкатегориями нельзя вложить в другую
+	level := "Верхний уровень"
+	var groupRow []telebot.InlineButton
+	if classification.ParentCode.Valid {
+		level = "Группа: " + classificationName(classification.ParentCode.String)
+	} else {
+		groupRow = append(groupRow, telebot.InlineButton{Unique: "class_admin_add", Text: "➕ Подкатегория", Data: classification.Code})
+	}
+	if children > 0 {
+		level += fmt.Sprintf("\nПодкатегорий: %d", children)
+	} else {
+		groupRow = append(groupRow, telebot.InlineButton{Unique: "class_admin_group", Text: "📁 Сменить группу", Data: classification.Code})
+	}
+	rows = append(rows, groupRow, []telebot.InlineButton{
+		{Unique: "class_admin_list", Text: "« К списку"},
+	})
+
+	return c.Edit(fmt.Sprintf("🗂️ *%s*\n\nКод: %s\n%s\n%s", classification.Name, classification.Code, level, status), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: rows},
+	})
+}
+
+// adminClassification проверяет права админа и загружает классификацию из данных кнопки
+func adminClassification(c telebot.Context, queries *db.Queries) (db.Classification, bool) {
+	if !isAdmin(c.Sender().ID, queries) {
+		c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Справочник классификаций доступен только администратору",
+			ShowAlert: true,
+		})
+		return db.Classification{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	classification, err := queries.GetClassification(ctx, c.Data())
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Printf("Ошибка получения классификации %s: %v\n", c.Data(), err)
+		}
+		c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Классификация не найдена",
+			ShowAlert: true,
+		})
+		return db.Classification{}, false
+	}
+	return classification, true
+}
+
+func handleClassificationAdmin(c telebot.Context, queries *db.Queries) error {
+	classification, ok := adminClassification(c, queries)
+	if !ok {
+		return nil
+	}
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return sendClassificationActions(c, queries, classification)
+}
+
+func handleClassificationAdminList(c telebot.Context, queries *db.Queries) error {
+	if !isAdmin(c.Sender().ID, queries) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Справочник классификаций доступен только администратору",
+			ShowAlert: true,
+		})
+	}
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return sendClassificationsAdmin(c, queries, true)
+}
+
+// handleClassificationMove меняет классификацию местами с соседней выше (step = -1)
+// или ниже (step = 1) в пределах её уровня дерева
+func handleClassificationMove(c telebot.Context, queries *db.Queries, step int) error {
+	classification, ok := adminClassification(c, queries)
+	if !ok {
+		return nil
+	}
+
+	var classifications []db.Classification
+	for _, item := range loadClassifications(queries) {
+		if item.ParentCode == classification.ParentCode {
+			classifications = append(classifications, item)
+		}
+	}
+	index := -1
+	for i, item := range classifications {
+		if item.Code == classification.Code {
+			index = i
+		}
+	}
+	neighbour := index + step
+	if index < 0 || neighbour < 0 || neighbour >= len(classifications) {
+		return c.Respond(&telebot.CallbackResponse{Text: "Классификация уже на краю списка"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := queries.SwapClassificationPositions(ctx, db.SwapClassificationPositionsParams{
+		Code:   classification.Code,
+		Code_2: classifications[neighbour].Code,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка изменения порядка классификаций: %v\n", err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось изменить порядок",
+			ShowAlert: true,
+		})
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{
+		Text: fmt.Sprintf("«%s» теперь %d-я в списке", classification.Name, neighbour+1),
+	}); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return sendClassificationsAdmin(c, queries, true)
+}
+
+func handleClassificationArchive(c telebot.Context, queries *db.Queries) error {
+	classification, ok := adminClassification(c, queries)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	updated, err := queries.SetClassificationArchived(ctx, db.SetClassificationArchivedParams{
+		Code:     classification.Code,
+		Archived: !classification.Archived,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка архивации классификации %s: %v\n", classification.Code, err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось изменить классификацию",
+			ShowAlert: true,
+		})
+	}
+	loadClassifications(queries)
+
+	response := "📦 Классификация перенесена в архив"
+	if !updated.Archived {
+		response = "♻️ Классификация снова доступна для выбора"
+	}
+	if err := c.Respond(&telebot.CallbackResponse{Text: response}); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return sendClassificationActions(c, queries, updated)
+}
+
+// handleClassificationAdd спрашивает у админа название новой классификации верхнего
+// уровня или, если в кнопке передан код группы, новой подкатегории
+func handleClassificationAdd(c telebot.Context, queries *db.Queries) error {
+	userID := c.Sender().ID
+	prompt := "Введите название новой классификации:"
+	conv := state.Conversation{Step: int(AdminStateClassificationName)}
+
+	if c.Data() == "" {
+		if !isAdmin(userID, queries) {
+			return c.Respond(&telebot.CallbackResponse{
+				Text:      "❌ Справочник классификаций доступен только администратору",
+				ShowAlert: true,
+			})
+		}
+	} else {
+		parent, ok := adminClassification(c, queries)
+		if !ok {
+			return nil
+		}
+		if parent.ParentCode.Valid {
+			return c.Respond(&telebot.CallbackResponse{
+				Text:      "❌ Подкатегорию можно добавить только к классификации верхнего уровня",
+				ShowAlert: true,
+			})
+		}
+		conv.Put("classification_parent", parent.Code)
+		prompt = fmt.Sprintf("Введите название новой подкатегории группы «%s»:", parent.Name)
+	}
+
+	saveConversation(userID, state.FlowAdmin, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(prompt, &telebot.SendOptions{
+		ReplyMarkup: menu.MenuAdminCancel,
+	})
+}
+
+// handleClassificationGroup предлагает админу группу для классификации без подкатегорий:
+// любую действующую классификацию верхнего уровня или сам верхний уровень
+func handleClassificationGroup(c telebot.Context, queries *db.Queries) error {
+	classification, ok := adminClassification(c, queries)
+	if !ok {
+		return nil
+	}
+
+	classifications := loadClassifications(queries)
+	if childCounts(classifications)[classification.Code] > 0 {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ У классификации есть подкатегории, её нельзя вложить в другую группу",
+			ShowAlert: true,
+		})
+	}
+
+	var rows [][]telebot.InlineButton
+	if classification.ParentCode.Valid {
+		rows = append(rows, []telebot.InlineButton{
+			{Unique: "class_admin_set_group", Text: "⬆️ На верхний уровень", Data: classification.Code + "|"},
+		})
+	}
+	for _, group := range classifications {
+		if group.ParentCode.Valid || group.Archived || group.Code == classification.Code || group.Code == classification.ParentCode.String {
+			continue
+		}
+		rows = append(rows, []telebot.InlineButton{
+			{Unique: "class_admin_set_group", Text: group.Name, Data: classification.Code + "|" + group.Code},
+		})
+	}
+	rows = append(rows, []telebot.InlineButton{
+		{Unique: "class_admin", Text: "« Назад", Data: classification.Code},
+	})
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Edit(fmt.Sprintf("📁 Выберите группу для «%s»:", classification.Name), &telebot.SendOptions{
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: rows},
+	})
+}
+
+// handleClassificationSetGroup переносит классификацию в выбранную группу или на верхний уровень
+func handleClassificationSetGroup(c telebot.Context, queries *db.Queries) error {
+	if !isAdmin(c.Sender().ID, queries) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Справочник классификаций доступен только администратору",
+			ShowAlert: true,
+		})
+	}
+	code, parentCode, found := strings.Cut(c.Data(), "|")
+	if !found {
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Ошибка: неверный формат данных"})
+	}
+
+	// Проверяем дерево по свежему справочнику: за время выбора могли появиться подкатегории
+	classifications := loadClassifications(queries)
+	counts := childCounts(classifications)
+	var classification, parent *db.Classification
+	for i := range classifications {
+		switch classifications[i].Code {
+		case code:
+			classification = &classifications[i]
+		case parentCode:
+			parent = &classifications[i]
+		}
+	}
+	switch {
+	case classification == nil || (parentCode != "" && parent == nil):
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Классификация не найдена", ShowAlert: true})
+	case parentCode != "" && counts[code] > 0:
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ У классификации есть подкатегории, её нельзя вложить в другую группу",
+			ShowAlert: true,
+		})
+	case parent != nil && parent.ParentCode.Valid:
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Группой может быть только классификация верхнего уровня",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	updated, err := queries.SetClassificationParent(ctx, db.SetClassificationParentParams{
+		Code: code,
+		ParentCode: pgtype.Text{
+			String: parentCode,
+			Valid:  parentCode != "",
+		},
+	})
+	if err != nil {
+		fmt.Printf("Ошибка переноса классификации %s: %v\n", code, err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось перенести классификацию",
+			ShowAlert: true,
+		})
+	}
+
+	response := "⬆️ Классификация перенесена на верхний уровень"
+	if parent != nil {
+		response = fmt.Sprintf("📁 Классификация перенесена в группу «%s»", parent.Name)
+	}
+	if err := c.Respond(&telebot.CallbackResponse{Text: response}); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return sendClassificationActions(c, queries, updated)
+}
+
+// handleClassificationRename спрашивает у админа новое название классификации
+func handleClassificationRename(c telebot.Context, queries *db.Queries) error {
+	classification, ok := adminClassification(c, queries)
+	if !ok {
+		return nil
+	}
+
+	conv := state.Conversation{Step: int(AdminStateClassificationRename)}
+	conv.Put("classification_code", classification.Code)
+	saveConversation(c.Sender().ID, state.FlowAdmin, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(fmt.Sprintf("Введите новое название для «%s»:", classification.Name), &telebot.SendOptions{
+		ReplyMarkup: menu.MenuAdminCancel,
+	})
+}
+
+// validateClassificationName проверяет название классификации, введённое админом
+func validateClassificationName(name string) error {
+	if name == "" {
+		return errors.New("Название не может быть пустым")
+	}
+	if len([]rune(name)) > maxClassificationNameLength {
+		return fmt.Errorf("Название не должно быть длиннее %d символов", maxClassificationNameLength)
+	}
+	return nil
+}
+
+// saveClassificationName добавляет классификацию (code пустой) в группу parentCode или на
+// верхний уровень либо переименовывает существующую
+func saveClassificationName(c telebot.Context, queries *db.Queries, code, parentCode, text string) error {
+	name := strings.TrimSpace(text)
+	if err := validateClassificationName(name); err != nil {
+		return c.Send(err.Error()+". Введите название ещё раз:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuAdminCancel,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Пока админ вводил название, группу могли вложить в другую — третьего уровня в дереве нет
+	if parentCode != "" {
+		parent, err := queries.GetClassification(ctx, parentCode)
+		if err != nil || parent.ParentCode.Valid {
+			clearConversation(c.Sender().ID, state.FlowAdmin)
+			return c.Send("❌ Группа больше не может содержать подкатегории, откройте справочник заново", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuAdmin,
+			})
+		}
+	}
+
+	var saved db.Classification
+	var err error
+	if code == "" {
+		saved, err = queries.CreateClassification(ctx, db.CreateClassificationParams{
+			Name: name,
+			ParentCode: pgtype.Text{
+				String: parentCode,
+				Valid:  parentCode != "",
+			},
+		})
+	} else {
+		saved, err = queries.RenameClassification(ctx, db.RenameClassificationParams{
+			Code: code,
+			Name: name,
+		})
+	}
+	if isUniqueViolation(err) {
+		return c.Send(fmt.Sprintf("❌ Классификация «%s» уже есть. Введите другое название:", name), &telebot.SendOptions{
+			ReplyMarkup: menu.MenuAdminCancel,
+		})
+	}
+	if err != nil {
+		fmt.Printf("Ошибка сохранения классификации: %v\n", err)
+		clearConversation(c.Sender().ID, state.FlowAdmin)
+		return c.Send("❌ Не удалось сохранить классификацию", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuAdmin,
+		})
+	}
+	clearConversation(c.Sender().ID, state.FlowAdmin)
+
+	message := fmt.Sprintf("✅ Классификация «%s» добавлена в конец списка", saved.Name)
+	if parentCode != "" {
+		message = fmt.Sprintf("✅ Подкатегория «%s» добавлена в группу «%s»", saved.Name, classificationName(parentCode))
+	}
+	if code != "" {
+		message = fmt.Sprintf("✅ Классификация переименована в «%s»", saved.Name)
+	}
+	if err := c.Send(message, &telebot.SendOptions{
+		ReplyMarkup: menu.MenuAdmin,
+	}); err != nil {
+		return err
+	}
+	return sendClassificationsAdmin(c, queries, false)
+}
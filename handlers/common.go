@@ -2,43 +2,20 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"tender_bot_go/db"
 	"tender_bot_go/settings"
+	"tender_bot_go/state"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 var config = settings.LoadSettings()
 
-var classificationNames = map[string]string{
-	"1":  "Сантехника",
-	"2":  "Вентиляция и кондиционирование",
-	"3":  "Отопление",
-	"4":  "Освещение",
-	"5":  "Розетки/выключатели",
-	"6":  "Камень натуральный",
-	"7":  "Керамогранит",
-	"8":  "Краска",
-	"9":  "Декоративная штукатурка",
-	"10": "Стеклянные перегородки и зеркала",
-	"11": "Двери",
-	"12": "Мебель индивидуального изготовления",
-	"13": "Мебель",
-	"14": "Портьеры",
-	"15": "Постельное белье",
-	"16": "Декор",
-	"17": "Обои",
-	"18": "Камины",
-	"19": "Посуда",
-	"20": "Озеленение",
-	"21": "Ковры",
-}
-
-var allCodes = []string{
-	"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20", "21",
-}
-
 func getUserRole(userID int64, queries *db.Queries) string {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -50,23 +27,226 @@ func getUserRole(userID int64, queries *db.Queries) string {
 	return user.Role
 }
 
+// isAdmin проверяет права администратора по роли, сохранённой в БД
+func isAdmin(userID int64, queries *db.Queries) bool {
+	return getUserRole(userID, queries) == "admin"
+}
+
+// usersWithRole возвращает Telegram ID незаблокированных пользователей с указанной ролью
+func usersWithRole(queries *db.Queries, role string) []int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	userIDs, err := queries.GetUserIDsByRole(ctx, role)
+	if err != nil {
+		fmt.Printf("Ошибка получения пользователей с ролью %s: %v\n", role, err)
+		return nil
+	}
+	return userIDs
+}
+
+// markdownEscaper экранирует управляющие символы Markdown-разметки Telegram
+var markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// escapeMarkdown подготавливает произвольный текст пользователя (причины отклонения,
+// отмены) для сообщений с ModeMarkdown, чтобы он не ломал разметку
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
 func getStatusWithEmoji(status string) (string, string) {
 	switch status {
 	case "active":
 		return "🟢", "Активный"
 	case "completed":
 		return "🔴", "Завершен"
+	case "failed":
+		return "⚫", "Не состоялся"
 	case "active_pending":
 		return "🟡", "Ожидает начала"
 	case "cancelled":
 		return "❌", "Отменен"
 	case "pending_approval":
 		return "🟠", "Ожидает подтверждения"
+	case "rejected":
+		return "🚫", "Отклонен"
 	default:
 		return "❓", "Неизвестный"
 	}
 }
 
+// Функция для описания шага понижения ставки (сумма или процент от текущей цены)
+func formatBidStep(stepType string, value float64) string {
+	if stepType == db.BidStepPercent {
+		return strconv.FormatFloat(value, 'f', -1, 64) + "% от текущей цены"
+	}
+	return formatPriceFloat(value) + " руб."
+}
+
+// formatTenderTerms описывает правила торгов: шаг понижения открытого аукциона
+// или срок приёма предложений закрытого тендера, а если лотов несколько — список
+// лотов с текущими ценами
+func formatTenderTerms(tender db.Tender, lots []db.TenderLot) string {
+	var terms string
+	if tender.Type == db.TenderTypeSealed {
+		terms = fmt.Sprintf("🔒 *Тип:* закрытый конверт\n⏳ *Приём предложений до:* %s\n",
+			tender.EndAt.Time.Format("02.01.2006 15:04"))
+	} else if tender.EndAt.Valid {
+		terms = fmt.Sprintf("⏳ *Окончание торгов:* %s\n", tender.EndAt.Time.Format("02.01.2006 15:04"))
+		if tender.ExtensionMinutes > 0 {
+			terms += fmt.Sprintf("⏱ *Продление:* ставка в последние %d мин продлевает торги на %d мин\n",
+				tender.ExtensionMinutes, tender.ExtensionMinutes)
+		}
+	}
+
+	if len(lots) <= 1 {
+		if tender.Type == db.TenderTypeSealed {
+			return terms
+		}
+		stepType, stepValue := tender.MinBidStepType, tender.MinBidDecrease
+		if len(lots) == 1 {
+			stepType, stepValue = lots[0].MinBidStepType, lots[0].MinBidDecrease
+		}
+		return terms + "📊 *Шаг понижения:* " + formatBidStep(stepType, stepValue) + "\n"
+	}
+
+	terms += "📦 *Лоты:*\n"
+	for _, lot := range lots {
+		terms += fmt.Sprintf("   %d. %s (%s) — %s руб.", lot.Number, lot.Title,
+			classificationName(lot.Classification.String), formatPriceFloat(lot.CurrentPrice))
+		if tender.Type == db.TenderTypeOpen {
+			terms += ", шаг " + formatBidStep(lot.MinBidStepType, lot.MinBidDecrease)
+		}
+		switch lot.Status {
+		case db.LotStatusConfirming:
+			terms += " — ожидается подтверждение победителя"
+		case db.LotStatusCompleted:
+			terms += " — торги завершены"
+		case db.LotStatusFailed:
+			terms += " — торги не состоялись"
+		}
+		terms += "\n"
+	}
+	return terms
+}
+
+// tenderLots возвращает лоты тендера по порядку; при ошибке — пустой список
+func tenderLots(queries *db.Queries, tenderID int32) []db.TenderLot {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	lots, err := queries.GetTenderLots(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения лотов тендера %d: %v\n", tenderID, err)
+		return nil
+	}
+	return lots
+}
+
+// tenderClassificationNames перечисляет классификации тендера, а если их не удалось
+// получить — классификации его лотов
+func tenderClassificationNames(queries *db.Queries, tender db.Tender, lots []db.TenderLot) string {
+	codes := tenderClassificationCodes(queries, tender.ID)
+	if len(codes) == 0 {
+		seen := make(map[string]bool)
+		for _, lot := range lots {
+			code := lot.Classification.String
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	if len(codes) == 0 {
+		codes = append(codes, tender.Classification.String)
+	}
+
+	names := make([]string, 0, len(codes))
+	for _, code := range codes {
+		names = append(names, classificationTitle(code))
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatHistoryEntry форматирует запись архива: победителя торгов по лоту или
+// отметку о том, что торги не состоялись
+func formatHistoryEntry(entry db.History, bidsHistoryText string) string {
+	formattedPrice := formatPriceFloat(entry.StartPrice)
+
+	if entry.Outcome == db.LotStatusFailed {
+		return fmt.Sprintf(
+			"📋 *Тендер*: %s\n\n"+
+				"💰 *Стартовая цена:* %s руб.\n"+
+				"⚫ *Торги не состоялись:* победитель не определён"+
+				"%s",
+			entry.Title,
+			formattedPrice,
+			bidsHistoryText,
+		)
+	}
+
+	return fmt.Sprintf(
+		"📋 *Тендер*: %s\n\n"+
+			"💰 *Стартовая цена:* %s руб.\n"+
+			"💰 *Выигрышная ставка:* %s руб.\n"+
+			"👑 Победитель: %s\n"+
+			"📞 Контакты победителя:\n"+
+			"   • Телефон: %s\n"+
+			"   • ИНН: %s\n"+
+			"   • ФИО: %s\n"+
+			"%s",
+		entry.Title,
+		formattedPrice,
+		formatPriceFloat(entry.Bid.Float64),
+		entry.Winner.String,
+		entry.PhoneNumber.String,
+		entry.Inn.String,
+		entry.Fio.String,
+		bidsHistoryText,
+	)
+}
+
+// lotLabel — обозначение лота в сообщениях, например «Лот №2: Двери»
+func lotLabel(lot db.TenderLot) string {
+	return fmt.Sprintf("Лот №%d: %s", lot.Number, lot.Title)
+}
+
+// tenderDeadline возвращает ближайший срок завершения торгов: для закрытого тендера —
+// срок приёма предложений, для открытого — ближайший срок среди открытых лотов.
+// Пока по лотам открытого тендера нет ставок, срока нет.
+func tenderDeadline(tender db.Tender, lots []db.TenderLot) (time.Time, bool) {
+	if tender.Type == db.TenderTypeSealed {
+		return tender.EndAt.Time, tender.EndAt.Valid
+	}
+
+	var deadline time.Time
+	for _, lot := range lots {
+		if lot.Status != db.LotStatusOpen || !lot.ClosesAt.Valid {
+			continue
+		}
+		if deadline.IsZero() || lot.ClosesAt.Time.Before(deadline) {
+			deadline = lot.ClosesAt.Time
+		}
+	}
+	return deadline, !deadline.IsZero()
+}
+
+// formatTimeLeft форматирует оставшееся время, например «1 ч 05 мин»
+func formatTimeLeft(d time.Duration) string {
+	if d < time.Minute {
+		return "меньше минуты"
+	}
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%d мин", minutes)
+	}
+	if hours < 24 {
+		return fmt.Sprintf("%d ч %02d мин", hours, minutes)
+	}
+	return fmt.Sprintf("%d дн %d ч", hours/24, hours%24)
+}
+
 // Функция для форматирования цены в финансовый формат (из строки)
 func formatPrice(priceStr string) string {
 	// Пытаемся преобразовать строку в число
@@ -119,4 +299,92 @@ func formatInteger(n int64) string {
 	}
 
 	return result
-}
\ No newline at end of file
+}
+// organizerOwnerID возвращает значение tenders.organizer_id для организатора
+func organizerOwnerID(userID int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: userID, Valid: true}
+}
+
+// tenderOrganizerIDs возвращает получателей уведомлений организатора по тендеру
+// (см. db.Queries.TenderOrganizerIDs)
+func tenderOrganizerIDs(queries *db.Queries, organizerID pgtype.Int8) []int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	organizers, err := queries.TenderOrganizerIDs(ctx, organizerID)
+	if err != nil {
+		fmt.Printf("Ошибка получения организаторов тендера: %v\n", err)
+		return nil
+	}
+	return organizers
+}
+
+// conversations хранит незавершённые диалоги (мастера организатора, регистрации
+// поставщика и ввода ставки). Инициализируется в RegisterHandlers.
+var conversations state.Store
+
+func newConversationStore(queries db.Querier) state.Store {
+	if config.StateStore == "memory" {
+		return state.NewMemoryStore(config.StateTTL)
+	}
+	return state.NewPostgresStore(queries, config.StateTTL)
+}
+
+// loadConversation возвращает диалог пользователя в указанном сценарии.
+// Второй результат false, если диалог не начат или его срок хранения истёк.
+func loadConversation(userID int64, flow state.Flow) (state.Conversation, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conv, ok, err := conversations.Get(ctx, userID, flow)
+	if err != nil {
+		fmt.Printf("Ошибка при чтении состояния диалога %s пользователя %d: %v\n", flow, userID, err)
+	}
+	if !ok || err != nil {
+		return state.Conversation{Data: map[string]string{}}, false
+	}
+	if conv.Data == nil {
+		conv.Data = map[string]string{}
+	}
+	return conv, true
+}
+
+func saveConversation(userID int64, flow state.Flow, conv state.Conversation) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := conversations.Set(ctx, userID, flow, conv); err != nil {
+		fmt.Printf("Ошибка при сохранении состояния диалога %s пользователя %d: %v\n", flow, userID, err)
+	}
+}
+
+func clearConversation(userID int64, flow state.Flow) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := conversations.Delete(ctx, userID, flow); err != nil {
+		fmt.Printf("Ошибка при удалении состояния диалога %s пользователя %d: %v\n", flow, userID, err)
+	}
+}
+
+// isUniqueViolation сообщает, что запрос нарушил уникальный индекс
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation сообщает, что запись ссылается на уже удалённую строку
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// historyTenderCount считает тендеры в архиве: у многолотового тендера в нём
+// несколько записей — по одной на лот
+func historyTenderCount(entries []db.History) int {
+	tenderIDs := make(map[int32]bool, len(entries))
+	for _, entry := range entries {
+		tenderIDs[entry.TenderID] = true
+	}
+	return len(tenderIDs)
+}
@@ -1,11 +1,19 @@
 package handlers
 
 import (
+	"tender_bot_go/db"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gopkg.in/telebot.v3"
 )
 
 func RegisterHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
+	// Хранилище незавершённых диалогов
+	conversations = newConversationStore(db.New(pool))
+
+	// Названия классификаций для сообщений; клавиатуры перечитывают справочник сами
+	loadClassifications(db.New(pool))
+
 	// Регистрируем единый текстовый обработчик
 	registerTextHandler(bot, pool)
 	
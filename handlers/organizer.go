@@ -2,17 +2,21 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"tender_bot_go/db"
 	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"tender_bot_go/tender"
 	"time"
 
 	"github.com/gofiber/fiber/v2/log"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gopkg.in/telebot.v3"
 )
@@ -27,22 +31,42 @@ const (
 	StateStartDate
 	StateClassification
 	StateConditions
+	// Шаги добавлены в конец, чтобы не сдвинуть номера шагов в сохранённых диалогах
+	StateMinBidStep
+	StateTenderType
+	StateEndDate
+	StateLotTitle
+	StateMoreLots
+	StateExtension
+	StateRelaunchStartDate
+	StateRelaunchPriceIncrease
+	StateEditValue
+	StateCancelReason
+	StateTemplateName
+	StateCloneStartDate
+	StateClonePrice
+	StateImportFile
 )
 
-var organizerStates = make(map[int64]OrganizerState)
-var organizerData = make(map[int64]map[string]string)
-var deleteTenderData = make(map[int64][]db.Tender)
+// Кнопки выбора типа тендера в мастере
+const (
+	tenderTypeOpenButton   = "Открытый аукцион"
+	tenderTypeSealedButton = "Закрытый конверт"
+)
+
+// Кнопки после добавления лота
+const (
+	addLotButton     = "Добавить лот"
+	finishLotsButton = "Продолжить"
+)
 
 func RegisterOrganizerHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 	queries := db.New(pool)
 
 	// Обработчики inline кнопок для организатора
-	for code := range classificationNames {
-		classCode := code
-		bot.Handle(&telebot.InlineButton{Unique: "org_class_" + classCode}, func(c telebot.Context) error {
-			return handleOrgClassification(c, queries, classCode)
-		})
-	}
+	bot.Handle(&telebot.InlineButton{Unique: "org_class"}, func(c telebot.Context) error {
+		return handleOrgClassification(c, queries, c.Data())
+	})
 
 	bot.Handle(&telebot.InlineButton{Unique: "org_class_done"}, func(c telebot.Context) error {
 		return handleOrgClassificationDone(c, queries)
@@ -52,26 +76,59 @@ func RegisterOrganizerHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 		return handleDeleteTender(c, queries)
 	})
 
-	// Обработчик документов для организатора
+	bot.Handle(&telebot.InlineButton{Unique: "relaunch_tender"}, func(c telebot.Context) error {
+		return handleRelaunchTender(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "resubmit_tender"}, func(c telebot.Context) error {
+		return handleResubmitTender(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "cancel_tender"}, func(c telebot.Context) error {
+		return handleCancelTender(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "save_template"}, func(c telebot.Context) error {
+		return handleSaveTemplate(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "use_template"}, func(c telebot.Context) error {
+		return handleUseTemplate(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "delete_template"}, func(c telebot.Context) error {
+		return handleDeleteTemplate(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "clone_tender"}, func(c telebot.Context) error {
+		return handleCloneTender(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "edit_tender"}, func(c telebot.Context) error {
+		return handleEditTender(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "edit_tender_field"}, func(c telebot.Context) error {
+		return handleEditTenderField(c, queries)
+	})
+
+	// Обработчик документов: условия и импорт у организатора, документы регистрации у поставщика
 	bot.Handle(telebot.OnDocument, func(c telebot.Context) error {
 		userID := c.Sender().ID
 		role := getUserRole(userID, queries)
 		if role == "organizer" {
 			return HandleOrganizerDocument(c, queries, userID)
 		}
+		if role == "supplier" {
+			return HandleSupplierDocument(c, queries, userID)
+		}
 		return nil
 	})
 }
 
 func HandleOrganizerText(c telebot.Context, queries *db.Queries, text string, userID int64) error {
-	// Инициализируем данные пользователя, если их нет
-	if _, exists := organizerData[userID]; !exists {
-		organizerData[userID] = make(map[string]string)
-	}
-
 	if text == "Создать тендер" {
-		organizerStates[userID] = StateTitle
-		organizerData[userID] = make(map[string]string)
+		saveConversation(userID, state.FlowOrganizer, state.Conversation{Step: int(StateTitle)})
 		return c.Send("Введите название тендера:", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizerCancel,
 		})
@@ -82,78 +139,347 @@ func HandleOrganizerText(c telebot.Context, queries *db.Queries, text string, us
 	if text == "История" {
 		return sendOrganizerHistory(c, queries)
 	}
-	if text == "Удалить тендер" {
-		return sendTendersForDeletion(c, queries)
+	if text == "Шаблоны" {
+		return sendOrganizerTemplates(c, queries)
+	}
+	if text == "Импорт из файла" {
+		return startTenderImport(c, userID)
+	}
+	// «Удалить тендер» остаётся для клавиатур, отправленных до появления отмены
+	if text == "Отменить тендер" || text == "Удалить тендер" {
+		return sendTendersForCancellation(c, queries)
 	}
 	if text == "Отмена" {
-		delete(organizerStates, userID)
-		delete(organizerData, userID)
+		clearConversation(userID, state.FlowOrganizer)
 		return c.Send("Создание тендера отменено.", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizer,
 		})
 	}
 
-	state := organizerStates[userID]
-	switch state {
+	conv, _ := loadConversation(userID, state.FlowOrganizer)
+	switch OrganizerState(conv.Step) {
 	case StateTitle:
-		organizerData[userID]["title"] = text
-		organizerStates[userID] = StateDescription
+		if err := tender.ValidateTitle(text); err != nil {
+			return c.Send(err.Error()+". Введите название тендера:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		conv.Put("title", text)
+		conv.Step = int(StateDescription)
+		saveConversation(userID, state.FlowOrganizer, conv)
 		return c.Send("Введите описание тендера:", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizerCancel,
 		})
 	case StateDescription:
-		organizerData[userID]["description"] = text
-		organizerStates[userID] = StateStartPrice
-		return c.Send("Введите стартовую цену в рублях:", &telebot.SendOptions{
+		conv.Put("description", text)
+		conv.Step = int(StateTenderType)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send("Выберите тип тендера:\n\n"+
+			"• *Открытый аукцион* — участники видят ставки друг друга и понижают цену\n"+
+			"• *Закрытый конверт* — каждый участник подаёт одно скрытое предложение до срока, побеждает наименьшее", &telebot.SendOptions{
+			ParseMode:   telebot.ModeMarkdown,
+			ReplyMarkup: menu.MenuOrganizerTenderType,
+		})
+	case StateTenderType:
+		switch text {
+		case tenderTypeOpenButton:
+			conv.Put("type", db.TenderTypeOpen)
+		case tenderTypeSealedButton:
+			conv.Put("type", db.TenderTypeSealed)
+		default:
+			return c.Send("Выберите тип тендера кнопкой ниже:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerTenderType,
+			})
+		}
+		conv.Step = int(StateLotTitle)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send("Тендер может состоять из нескольких лотов, по каждому идут отдельные торги.\n\n"+
+			"Введите название лота №1 (например, «Натуральный камень»):", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	case StateLotTitle:
+		if err := tender.ValidateLotTitle(text); err != nil {
+			return c.Send(err.Error()+". Введите название лота:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		conv.Put("lot_title", text)
+		conv.Step = int(StateStartPrice)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send("Введите стартовую цену лота в рублях:", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizerCancel,
 		})
 	case StateStartPrice:
-		organizerData[userID]["start_price"] = text
-		organizerStates[userID] = StateStartDate
-		return c.Send("Введите дату и время начала тендера в формате ДД.ММ.ГГГГ ЧЧ:ММ:", &telebot.SendOptions{
+		startPrice, err := strconv.ParseFloat(text, 64)
+		if err != nil || tender.ValidateStartPrice(startPrice) != nil {
+			return c.Send("Введите корректную числовую стартовую цену!", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		conv.Put("start_price", text)
+		// В закрытом тендере шага понижения нет
+		if conv.Get("type") == db.TenderTypeSealed {
+			conv.Step = int(StateClassification)
+			saveConversation(userID, state.FlowOrganizer, conv)
+			return c.Send(lotClassificationPrompt, &telebot.SendOptions{
+				ReplyMarkup: showOrganizerClassificationKeyboard(queries, ""),
+			})
+		}
+		conv.Step = int(StateMinBidStep)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send("Введите минимальный шаг понижения ставки: сумму в рублях (например, 5000) или процент от текущей цены (например, 1%):", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizerCancel,
 		})
+	case StateMinBidStep:
+		startPrice, _ := strconv.ParseFloat(conv.Get("start_price"), 64)
+		stepType, stepValue, err := tender.ParseBidStep(text)
+		if err == nil {
+			err = tender.ValidateBidStep(stepType, stepValue, startPrice)
+		}
+		if err != nil {
+			return c.Send(err.Error()+". Введите сумму в рублях или процент, например: 5000 или 1%", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		conv.Put("min_bid_step_type", stepType)
+		conv.Put("min_bid_decrease", strconv.FormatFloat(stepValue, 'f', -1, 64))
+		conv.Step = int(StateClassification)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send(lotClassificationPrompt, &telebot.SendOptions{
+			ReplyMarkup: showOrganizerClassificationKeyboard(queries, ""),
+		})
+	case StateMoreLots:
+		switch text {
+		case addLotButton:
+			conv.Step = int(StateLotTitle)
+			saveConversation(userID, state.FlowOrganizer, conv)
+			return c.Send(fmt.Sprintf("Введите название лота №%d:", len(conversationLots(conv))+1), &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		case finishLotsButton:
+			conv.Step = int(StateStartDate)
+			saveConversation(userID, state.FlowOrganizer, conv)
+			return c.Send(startDatePrompt(conv), &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		default:
+			return c.Send("Добавьте ещё лот или нажмите «Продолжить»:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerLots,
+			})
+		}
 	case StateStartDate:
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		db_location, err := queries.TimeZone(ctx)
-		log.Info(db_location)
+		startDateTime, err := time.ParseInLocation("02.01.2006 15:04", text, dbLocation(queries))
+		if err != nil {
+			return c.Send("Введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ, например: 25.12.2024 14:30", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		if err := tender.ValidateStartAt(startDateTime, time.Now()); err != nil {
+			return c.Send(err.Error()+"!", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		conv.Put("start_date", text)
+		conv.Put("start_date_parsed", startDateTime.Format(time.RFC3339))
+		conv.Step = int(StateEndDate)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		if conv.Get("type") == db.TenderTypeSealed {
+			return c.Send("Введите срок окончания приёма предложений в формате ДД.ММ.ГГГГ ЧЧ:ММ:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		return c.Send("Введите срок окончания торгов в формате ДД.ММ.ГГГГ ЧЧ:ММ или отправьте 'нет', чтобы торги завершались только через 5 минут без новых ставок:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	case StateEndDate:
+		// Срок окончания открытого тендера необязателен
+		if conv.Get("type") != db.TenderTypeSealed && (text == "нет" || text == "Нет") {
+			conv.Step = int(StateConditions)
+			saveConversation(userID, state.FlowOrganizer, conv)
+			return c.Send("Прикрепите файл с условиями или отправьте 'нет'", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		endDateTime, err := time.ParseInLocation("02.01.2006 15:04", text, dbLocation(queries))
+		if err != nil {
+			return c.Send("Введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ, например: 25.12.2024 18:00", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		startDateTime, _ := time.Parse(time.RFC3339, conv.Get("start_date_parsed"))
+		if err := tender.ValidateEndAt(conv.Get("type"), startDateTime, endDateTime); err != nil {
+			return c.Send(err.Error()+"!", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		conv.Put("end_date_parsed", endDateTime.Format(time.RFC3339))
+		if conv.Get("type") == db.TenderTypeSealed {
+			conv.Step = int(StateConditions)
+			saveConversation(userID, state.FlowOrganizer, conv)
+			return c.Send("Прикрепите файл с условиями или отправьте 'нет'", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		conv.Step = int(StateExtension)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send(fmt.Sprintf("На сколько минут продлевать торги, если ставка сделана в последние минуты перед окончанием? Введите число от 0 до %d (0 — без продления):", tender.MaxExtensionMinutes), &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	case StateExtension:
+		minutes, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return c.Send("Введите количество минут целым числом, например: 5", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		endDateTime, _ := time.Parse(time.RFC3339, conv.Get("end_date_parsed"))
+		if err := tender.ValidateExtension(conv.Get("type"), endDateTime, int32(minutes)); err != nil {
+			return c.Send(err.Error()+"!", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		conv.Put("extension_minutes", strconv.FormatInt(minutes, 10))
+		conv.Step = int(StateConditions)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send("Прикрепите файл с условиями или отправьте 'нет'", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	case StateRelaunchStartDate:
+		startDateTime, err := time.ParseInLocation("02.01.2006 15:04", text, dbLocation(queries))
+		if err != nil {
+			return c.Send("Введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ, например: 25.12.2024 14:30", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		if err := tender.ValidateStartAt(startDateTime, time.Now()); err != nil {
+			return c.Send(err.Error()+"!", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		conv.Put("start_date_parsed", startDateTime.Format(time.RFC3339))
+		conv.Step = int(StateRelaunchPriceIncrease)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send("На сколько процентов поднять стартовую цену? Введите число (0 — оставить прежней):", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	case StateRelaunchPriceIncrease:
+		percent, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
 		if err != nil {
-			log.Info("Failed to get db location")
+			return c.Send("Введите число процентов, например: 10", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
 		}
-		location, err := time.LoadLocation(db_location)
+		if err := tender.ValidatePriceIncrease(percent); err != nil {
+			return c.Send(err.Error()+"!", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+
+		startDateTime, _ := time.Parse(time.RFC3339, conv.Get("start_date_parsed"))
+		tenderID, _ := strconv.ParseInt(conv.Get("relaunch_tender_id"), 10, 32)
+		successMessage, err := relaunchTender(c, queries, int32(tenderID), startDateTime, percent)
 		if err != nil {
-			location = time.UTC
+			return err
+		}
+		clearConversation(userID, state.FlowOrganizer)
+		return c.Send(successMessage, &telebot.SendOptions{
+			ParseMode:   telebot.ModeMarkdown,
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	case StateEditValue:
+		return applyTenderEdit(c, queries, conv, text)
+	case StateTemplateName:
+		if err := tender.ValidateTemplateName(text); err != nil {
+			return c.Send(err.Error()+". Введите название шаблона:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
 		}
-		startDateTime, err := time.ParseInLocation("02.01.2006 15:04", text, location)
+		tenderID, _ := strconv.ParseInt(conv.Get("template_tender_id"), 10, 32)
+		return saveTenderTemplate(c, queries, int32(tenderID), text)
+	case StateCloneStartDate:
+		startDateTime, err := time.ParseInLocation("02.01.2006 15:04", text, dbLocation(queries))
 		if err != nil {
 			return c.Send("Введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ, например: 25.12.2024 14:30", &telebot.SendOptions{
 				ReplyMarkup: menu.MenuOrganizerCancel,
 			})
 		}
 
-		if startDateTime.Before(time.Now()) {
-			return c.Send("Дата начала тендера должна быть в будущем!", &telebot.SendOptions{
+		if err := tender.ValidateStartAt(startDateTime, time.Now()); err != nil {
+			return c.Send(err.Error()+"!", &telebot.SendOptions{
 				ReplyMarkup: menu.MenuOrganizerCancel,
 			})
 		}
 
-		organizerData[userID]["start_date"] = text
-		organizerData[userID]["start_date_parsed"] = startDateTime.Format(time.RFC3339)
-		organizerStates[userID] = StateClassification
-		markup := showOrganizerClassificationKeyboard(userID)
-		return c.Send("Выберите одну классификацию для тендера:", &telebot.SendOptions{
-			ReplyMarkup: markup,
+		conv.Put("start_date_parsed", startDateTime.Format(time.RFC3339))
+		draft, err := cloneDraft(queries, conv, userID)
+		if err != nil || len(draft.Lots) == 0 {
+			fmt.Printf("Ошибка получения образца тендера: %v\n", err)
+			clearConversation(userID, state.FlowOrganizer)
+			return c.Send("❌ Шаблон или тендер-образец не найден.", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizer,
+			})
+		}
+		conv.Put("clone_lot", "0")
+		conv.Step = int(StateClonePrice)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send(clonePricePrompt(draft, 0), &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
 		})
+	case StateClonePrice:
+		if text != keepPriceAnswer {
+			startPrice, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+			if err != nil || tender.ValidateStartPrice(startPrice) != nil {
+				return c.Send(fmt.Sprintf("Введите корректную числовую стартовую цену или «%s»!", keepPriceAnswer), &telebot.SendOptions{
+					ReplyMarkup: menu.MenuOrganizerCancel,
+				})
+			}
+			text = strconv.FormatFloat(startPrice, 'f', -1, 64)
+		}
+		index, _ := strconv.Atoi(conv.Get("clone_lot"))
+		conv.Put(fmt.Sprintf("clone_price_%d", index), text)
+
+		draft, err := cloneDraft(queries, conv, userID)
+		if err != nil {
+			fmt.Printf("Ошибка получения образца тендера: %v\n", err)
+			clearConversation(userID, state.FlowOrganizer)
+			return c.Send("❌ Шаблон или тендер-образец не найден.", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizer,
+			})
+		}
+		if index+1 < len(draft.Lots) {
+			conv.Put("clone_lot", strconv.Itoa(index+1))
+			saveConversation(userID, state.FlowOrganizer, conv)
+			return c.Send(clonePricePrompt(draft, index+1), &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		return createClonedTender(c, queries, draft)
+	case StateCancelReason:
+		if err := tender.ValidateCancelReason(text); err != nil {
+			return c.Send(err.Error()+". Введите причину отмены:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+		tenderID, _ := strconv.ParseInt(conv.Get("cancel_tender_id"), 10, 32)
+		clearConversation(userID, state.FlowOrganizer)
+		return confirmTenderCancel(c, queries, int32(tenderID), text)
 	case StateConditions:
 		if text == "нет" || text == "Нет" {
-			organizerData[userID]["conditions_path"] = ""
-			successMessage, _, err := saveTenderToDB(userID, queries, c)
+			conv.Put("conditions_path", "")
+			successMessage, _, err := saveTenderToDB(conv.Data, queries, c)
 			if err != nil {
 				return err
 			}
-			delete(organizerStates, userID)
-			delete(organizerData, userID)
+			clearConversation(userID, state.FlowOrganizer)
 			return c.Send(successMessage, &telebot.SendOptions{
 				ParseMode:   telebot.ModeMarkdown,
 				ReplyMarkup: menu.MenuOrganizer,
@@ -168,74 +494,119 @@ func HandleOrganizerText(c telebot.Context, queries *db.Queries, text string, us
 	}
 }
 
-func HandleOrganizerDocument(c telebot.Context, queries *db.Queries, userID int64) error {
-	state := organizerStates[userID]
-	if state != StateConditions {
-		return nil
+// startDatePrompt — приглашение ввести дату начала тендера выбранного типа
+func startDatePrompt(conv state.Conversation) string {
+	if conv.Get("type") == db.TenderTypeSealed {
+		return "Введите дату и время начала приёма предложений в формате ДД.ММ.ГГГГ ЧЧ:ММ:"
 	}
+	return "Введите дату и время начала тендера в формате ДД.ММ.ГГГГ ЧЧ:ММ:"
+}
 
-	if _, exists := organizerData[userID]; !exists {
-		organizerData[userID] = make(map[string]string)
+// conversationLots возвращает лоты, уже добавленные в мастере создания тендера
+func conversationLots(conv state.Conversation) []tender.LotDraft {
+	var lots []tender.LotDraft
+	if raw := conv.Get("lots"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &lots); err != nil {
+			fmt.Printf("Ошибка чтения лотов из диалога: %v\n", err)
+		}
 	}
+	return lots
+}
 
-	doc := c.Message().Document
-	if doc == nil {
-		return c.Send("Файл не найден. Попробуйте еще раз.", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+// addConversationLot переносит заполненный лот в список лотов диалога
+// и очищает поля для ввода следующего. Первая по справочнику выбранная классификация
+// становится классификацией лота, остальные добавляются к классификациям тендера
+func addConversationLot(conv *state.Conversation) []tender.LotDraft {
+	startPrice, _ := strconv.ParseFloat(conv.Get("start_price"), 64)
+	minBidDecrease, _ := strconv.ParseFloat(conv.Get("min_bid_decrease"), 64)
+
+	// Диалоги, начатые до появления лотов, создают один лот с названием тендера
+	title := conv.Get("lot_title")
+	if title == "" {
+		title = conv.Get("title")
 	}
 
-	timestamp := time.Now().UnixNano()
-	filename := fmt.Sprintf("%d_%s", timestamp, doc.FileName)
-	filePath := filepath.Join("files", filename)
-
-	if err := os.MkdirAll("files", 0755); err != nil {
-		fmt.Printf("Ошибка создания директории: %v\n", err)
-		return c.Send("Не удалось создать директорию для файлов.", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+	codes := splitCodes(conv.Get("classification"))
+	var classification string
+	if len(codes) > 0 {
+		classification = codes[0]
+		extra := append(splitCodes(conv.Get("extra_classifications")), codes[1:]...)
+		conv.Put("extra_classifications", strings.Join(extra, ","))
 	}
 
-	f, err := os.Create(filePath)
+	lots := append(conversationLots(*conv), tender.LotDraft{
+		Title:          title,
+		StartPrice:     startPrice,
+		MinBidDecrease: minBidDecrease,
+		MinBidStepType: conv.Get("min_bid_step_type"),
+		Classification: classification,
+	})
+	raw, err := json.Marshal(lots)
 	if err != nil {
-		fmt.Printf("Ошибка создания файла: %v\n", err)
-		return c.Send("Не удалось сохранить файл.", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+		fmt.Printf("Ошибка сохранения лотов в диалог: %v\n", err)
 	}
-	defer f.Close()
+	conv.Put("lots", string(raw))
 
-	reader, err := c.Bot().File(&doc.File)
-	if err != nil {
-		fmt.Printf("Ошибка получения файла от Telegram: %v\n", err)
-		return c.Send("Не удалось прочитать файл.", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+	for _, key := range []string{"lot_title", "start_price", "min_bid_decrease", "min_bid_step_type", "classification"} {
+		conv.Put(key, "")
 	}
+	return lots
+}
 
-	_, err = io.Copy(f, reader)
+// dbLocation возвращает часовой пояс БД, в котором организатор вводит даты
+func dbLocation(queries *db.Queries) *time.Location {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db_location, err := queries.TimeZone(ctx)
+	log.Info(db_location)
 	if err != nil {
-		fmt.Printf("Ошибка копирования файла: %v\n", err)
-		return c.Send("Ошибка при сохранении файла.", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+		log.Info("Failed to get db location")
+	}
+	location, err := time.LoadLocation(db_location)
+	if err != nil {
+		location = time.UTC
 	}
+	return location
+}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		fmt.Printf("Файл не создан: %s\n", filePath)
-		return c.Send("Файл не был сохранен на сервере.", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+func HandleOrganizerDocument(c telebot.Context, queries *db.Queries, userID int64) error {
+	conv, ok := loadConversation(userID, state.FlowOrganizer)
+	if !ok {
+		return nil
+	}
+	// При редактировании тендера файл заменяет условия уже сохранённого тендера
+	if OrganizerState(conv.Step) == StateEditValue && conv.Get("edit_field") == editFieldConditions {
+		filePath, err := saveConditionsFile(c)
+		if filePath == "" {
+			return err
+		}
+		return applyTenderEdit(c, queries, conv, filePath)
+	}
+	if OrganizerState(conv.Step) == StateImportFile {
+		return handleTenderImport(c, queries, userID)
+	}
+	if OrganizerState(conv.Step) != StateConditions {
+		return nil
 	}
 
-	organizerData[userID]["conditions_path"] = filePath
+	filePath, err := saveConditionsFile(c)
+	if filePath == "" {
+		return err
+	}
+	doc := c.Message().Document
+
+	conv.Put("conditions_path", filePath)
 	fmt.Printf("Файл сохранен: %s\n", filePath)
 
-	successMessage, _, err := saveTenderToDB(userID, queries, c)
+	successMessage, _, err := saveTenderToDB(conv.Data, queries, c)
 	if err != nil {
 		return err
 	}
 
+	// Тендер уже сохранён, поэтому диалог завершаем сразу, даже если
+	// отправка файла ниже не удастся
+	clearConversation(userID, state.FlowOrganizer)
+
 	if err := c.Send(successMessage, &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
 	}); err != nil {
@@ -261,49 +632,129 @@ func HandleOrganizerDocument(c telebot.Context, queries *db.Queries, userID int6
 		return c.Send("❌ Не удалось отправить файл. Попробуйте еще раз.")
 	}
 
-	delete(organizerStates, userID)
-	delete(organizerData, userID)
-
 	return c.Send("Тендер успешно создан! Что хотите сделать дальше?", &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
 		ReplyMarkup: menu.MenuOrganizer,
 	})
 }
 
+// saveConditionsFile сохраняет присланный файл условий в каталог files и возвращает
+// путь к нему. Пустой путь означает, что сохранить не удалось и пользователь уже получил ответ.
+func saveConditionsFile(c telebot.Context) (string, error) {
+	doc := c.Message().Document
+	if doc == nil {
+		return "", c.Send("Файл не найден. Попробуйте еще раз.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	timestamp := time.Now().UnixNano()
+	filename := fmt.Sprintf("%d_%s", timestamp, doc.FileName)
+	filePath := filepath.Join(config.FilesDir, filename)
+
+	if err := os.MkdirAll(config.FilesDir, 0755); err != nil {
+		fmt.Printf("Ошибка создания директории: %v\n", err)
+		return "", c.Send("Не удалось создать директорию для файлов.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		fmt.Printf("Ошибка создания файла: %v\n", err)
+		return "", c.Send("Не удалось сохранить файл.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	defer f.Close()
+
+	reader, err := c.Bot().File(&doc.File)
+	if err != nil {
+		fmt.Printf("Ошибка получения файла от Telegram: %v\n", err)
+		return "", c.Send("Не удалось прочитать файл.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	_, err = io.Copy(f, reader)
+	if err != nil {
+		fmt.Printf("Ошибка копирования файла: %v\n", err)
+		return "", c.Send("Ошибка при сохранении файла.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		fmt.Printf("Файл не создан: %s\n", filePath)
+		return "", c.Send("Файл не был сохранен на сервере.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	return filePath, nil
+}
+
 func handleOrgClassification(c telebot.Context, queries *db.Queries, classCode string) error {
 	userID := c.Sender().ID
-	organizerData[userID]["classification"] = classCode
-	markup := showOrganizerClassificationKeyboard(userID)
-	return c.Edit("Выберите одну классификацию для тендера:", &telebot.SendOptions{
-		ReplyMarkup: markup,
+	conv, ok := loadConversation(userID, state.FlowOrganizer)
+	if !ok || OrganizerState(conv.Step) != StateClassification {
+		return c.Respond(&telebot.CallbackResponse{Text: "Создание тендера не начато или устарело"})
+	}
+	classifications := activeClassifications(queries)
+	selected, err := toggleClassification(classifications, conv.Get("classification"), classCode)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      err.Error(),
+			ShowAlert: true,
+		})
+	}
+	conv.Put("classification", selected)
+	saveConversation(userID, state.FlowOrganizer, conv)
+	return c.Edit(lotClassificationPrompt, &telebot.SendOptions{
+		ReplyMarkup: organizerClassificationKeyboard(classifications, selected),
 	})
 }
 
 func handleOrgClassificationDone(c telebot.Context, queries *db.Queries) error {
 	userID := c.Sender().ID
-	selectedCode := organizerData[userID]["classification"]
+	conv, ok := loadConversation(userID, state.FlowOrganizer)
+	if !ok || OrganizerState(conv.Step) != StateClassification {
+		return c.Respond(&telebot.CallbackResponse{Text: "Создание тендера не начато или устарело"})
+	}
+	selectedCodes := conv.Get("classification")
 
-	if selectedCode == "" {
+	if selectedCodes == "" {
 		return c.Respond(&telebot.CallbackResponse{
 			Text:      "Выберите классификацию!",
 			ShowAlert: true,
 		})
 	}
 
-	selectedName := classificationNames[selectedCode]
-	organizerStates[userID] = StateConditions
+	lots := addConversationLot(&conv)
 
 	err := c.Respond()
 	if err != nil {
 		fmt.Printf("Ошибка при ответе на callback: %v\n", err)
 	}
 
-	return c.Send(
-		fmt.Sprintf("Выбранная классификация: %s\n\nПрикрепите файл с условиями или отправьте 'нет'", selectedName),
-		&telebot.SendOptions{
+	lot := lots[len(lots)-1]
+	message := fmt.Sprintf("Лот №%d «%s» добавлен: %s руб., классификации: %s",
+		len(lots), lot.Title, formatPriceFloat(lot.StartPrice), classificationList(selectedCodes))
+
+	// Больше лотов добавить нельзя — сразу переходим к дате начала
+	if len(lots) >= tender.MaxLots {
+		conv.Step = int(StateStartDate)
+		saveConversation(userID, state.FlowOrganizer, conv)
+		return c.Send(message+"\n\n"+startDatePrompt(conv), &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizerCancel,
-		},
-	)
+		})
+	}
+
+	conv.Step = int(StateMoreLots)
+	saveConversation(userID, state.FlowOrganizer, conv)
+	return c.Send(message+"\n\nДобавить ещё лот?", &telebot.SendOptions{
+		ReplyMarkup: menu.MenuOrganizerLots,
+	})
 }
 
 func handleDeleteTender(c telebot.Context, queries *db.Queries) error {
@@ -316,37 +767,232 @@ func handleDeleteTender(c telebot.Context, queries *db.Queries) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = queries.DeleteTender(ctx, int32(tenderID))
+	// Админ может удалить любой тендер, организатор — только свой. Удаляются только
+	// тендеры, которые ни разу не покидали модерацию, остальные отменяются
+	userID := c.Sender().ID
+	role := getUserRole(userID, queries)
+	var deleted int64
+	switch role {
+	case "admin":
+		deleted, err = queries.DeleteTender(ctx, int32(tenderID))
+	case "organizer":
+		deleted, err = queries.DeleteOrganizerTender(ctx, db.DeleteOrganizerTenderParams{
+			ID:          int32(tenderID),
+			OrganizerID: organizerOwnerID(userID),
+		})
+	default:
+		return c.Send("❌ У вас нет прав для удаления тендеров")
+	}
 	if err != nil {
 		fmt.Printf("Ошибка при удалении тендера: %v\n", err)
 		return c.Send("❌ Не удалось удалить тендер", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizer,
 		})
 	}
-
-	userID := c.Sender().ID
-	delete(deleteTenderData, userID)
+	if deleted == 0 {
+		return c.Send("❌ Удалить можно только свой тендер, который ещё не прошёл модерацию. Остальные тендеры можно отменить.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
 
 	return c.Send("✅ Тендер успешно удален", &telebot.SendOptions{
 		ReplyMarkup: menu.MenuOrganizer,
 	})
 }
 
-func showOrganizerClassificationKeyboard(userID int64) *telebot.ReplyMarkup {
-	selectedCode := organizerData[userID]["classification"]
+// handleRelaunchTender начинает перезапуск несостоявшегося тендера: спрашивает новую
+// дату начала и повышение стартовой цены
+func handleRelaunchTender(c telebot.Context, queries *db.Queries) error {
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Перезапускать тендеры может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	original, err := queries.GetTender(ctx, int32(tenderID))
+	if err != nil || (original.OrganizerID.Valid && original.OrganizerID != organizerOwnerID(userID)) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не найден среди ваших тендеров",
+			ShowAlert: true,
+		})
+	}
+	if original.Status != "failed" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Перезапустить можно только несостоявшийся тендер",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateRelaunchStartDate)}
+	conv.Put("relaunch_tender_id", strconv.Itoa(int(original.ID)))
+	saveConversation(userID, state.FlowOrganizer, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(fmt.Sprintf("🔁 *Перезапуск тендера «%s»*\n\nВведите новую дату и время начала в формате ДД.ММ.ГГГГ ЧЧ:ММ:", original.Title), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// relaunchTender создаёт копию несостоявшегося тендера с новой датой начала и
+// отправляет её на модерацию, как новый тендер
+func relaunchTender(c telebot.Context, queries *db.Queries, tenderID int32, startAt time.Time, priceIncrease float64) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	original, err := queries.GetTender(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения тендера %d для перезапуска: %v\n", tenderID, err)
+		return "", c.Send("❌ Тендер для перезапуска не найден.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	draft := tender.RelaunchDraft(original, tenderLots(queries, original.ID), tenderClassificationCodes(queries, original.ID), startAt, priceIncrease)
+	draft.OrganizerID = c.Sender().ID
+	if err := draft.Validate(time.Now()); err != nil {
+		return "", c.Send("❌ "+err.Error()+". Перезапуск отменён.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
 
-	var rows [][]telebot.InlineButton
-	for _, code := range allCodes {
-		name := classificationNames[code]
-		text := name
-		if code == selectedCode {
-			text = "✅ " + name
+	warning := copyDraftConditions(&draft)
+	created, createdLots, err := queries.CreateTenderWithLots(ctx, draft.CreateParams(), draft.LotParams(), draft.ClassificationCodes())
+	if err != nil {
+		if draft.ConditionsPath != "" {
+			os.Remove(draft.ConditionsPath)
 		}
-		btn := telebot.InlineButton{Unique: "org_class_" + code, Text: text}
-		rows = append(rows, []telebot.InlineButton{btn})
+		fmt.Printf("Ошибка при перезапуске тендера %d: %v\n", tenderID, err)
+		return "", c.Send("Ошибка при сохранении данных в БД. Попробуйте снова.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	go sendTenderApprovalNotification(c.Bot(), queries, created, createdLots)
+
+	return fmt.Sprintf(
+		"✅ *Тендер перезапущен и отправлен на модерацию!*\n\n"+
+			"📋 *Название:* %s\n"+
+			"💰 *Стартовая цена:* %s руб. (было %s руб.)\n"+
+			"%s"+
+			"📅 *Дата начала:* %s\n\n"+
+			"⏳ *Ожидайте одобрения администратора*"+
+			"%s",
+		created.Title,
+		formatPriceFloat(created.StartPrice),
+		formatPriceFloat(original.StartPrice),
+		formatTenderTerms(created, createdLots),
+		created.StartAt.Time.In(startAt.Location()).Format("02.01.2006 15:04"),
+		warning,
+	), nil
+}
+
+// handleResubmitTender запускает мастер заново для отклонённого тендера: после
+// заполнения тендер обновляется и снова уходит на модерацию
+func handleResubmitTender(c telebot.Context, queries *db.Queries) error {
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
 	}
 
-	if selectedCode != "" {
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Отправлять тендеры на модерацию может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rejected, err := queries.GetTender(ctx, int32(tenderID))
+	if err != nil || (rejected.OrganizerID.Valid && rejected.OrganizerID != organizerOwnerID(userID)) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не найден среди ваших тендеров",
+			ShowAlert: true,
+		})
+	}
+	if rejected.Status != tender.StatusRejected {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Повторно отправить можно только отклонённый тендер",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateTitle)}
+	conv.Put("resubmit_tender_id", strconv.Itoa(int(rejected.ID)))
+	saveConversation(userID, state.FlowOrganizer, conv)
+
+	message := fmt.Sprintf("✏️ *Исправление тендера «%s»*\n\n", rejected.Title)
+	if review, err := queries.GetLastTenderReview(ctx, rejected.ID); err == nil && review.Reason != "" {
+		message += fmt.Sprintf("❗ *Причина отклонения:* %s\n\n", escapeMarkdown(review.Reason))
+	}
+	message += "Заполните тендер заново — после сохранения он снова уйдёт на модерацию.\n\nВведите название тендера:"
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(message, &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// resubmitTender заменяет данные отклонённого тендера исправленным черновиком и
+// возвращает его на модерацию
+func resubmitTender(ctx context.Context, queries *db.Queries, tenderID int32, draft tender.Draft, actorID int64) (db.Tender, []db.TenderLot, error) {
+	current, err := queries.GetTender(ctx, tenderID)
+	if err != nil {
+		return db.Tender{}, nil, err
+	}
+	if current.Status != tender.StatusRejected || (current.OrganizerID.Valid && current.OrganizerID.Int64 != draft.OrganizerID) {
+		return db.Tender{}, nil, tender.ErrStatusChanged
+	}
+
+	// Новые данные и возврат на модерацию сохраняются в одной транзакции
+	var updated db.Tender
+	var updatedLots []db.TenderLot
+	err = tender.TransitionWith(ctx, queries, tenderID, tender.StatusRejected, tender.StatusPendingApproval, actorID, "исправлен и отправлен повторно", func(q *db.Queries) error {
+		var err error
+		updated, updatedLots, err = q.UpdateTenderWithLots(ctx, tenderID, draft.CreateParams(), draft.LotParams(), draft.ClassificationCodes())
+		return err
+	})
+	if err != nil {
+		return db.Tender{}, nil, err
+	}
+	return updated, updatedLots, nil
+}
+
+// lotClassificationPrompt — подсказка к выбору классификаций лота
+const lotClassificationPrompt = "Выберите классификации лота — одну или несколько. Первая по списку станет классификацией лота, остальные добавятся к классификациям тендера:"
+
+func showOrganizerClassificationKeyboard(queries *db.Queries, selected string) *telebot.ReplyMarkup {
+	return organizerClassificationKeyboard(activeClassifications(queries), selected)
+}
+
+func organizerClassificationKeyboard(classifications []db.Classification, selected string) *telebot.ReplyMarkup {
+	rows := classificationButtons(classifications, selected, "org_class")
+
+	if selected != "" {
 		rows = append(rows, []telebot.InlineButton{
 			{Unique: "org_class_done", Text: "✅ Завершить выбор"},
 		})
@@ -357,32 +1003,29 @@ func showOrganizerClassificationKeyboard(userID int64) *telebot.ReplyMarkup {
 }
 
 // Остальные функции организатора (sendOrganizerTendersList, sendOrganizerHistory, sendTendersForDeletion, saveTenderToDB и т.д.)
-// нужно скопировать из вашего кода и заменить userData на organizerData
 
-func sendTendersForDeletion(c telebot.Context, queries *db.Queries) error {
+// sendTendersForCancellation показывает незавершённые тендеры организатора с кнопкой
+// отмены; тендеры, которые ещё не покидали модерацию, можно и удалить
+func sendTendersForCancellation(c telebot.Context, queries *db.Queries) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Получаем тендеры, которые можно удалить (статус не "completed")
-	tenders, err := queries.GetTendersForDeletion(ctx)
+	// Получаем тендеры, которые можно отменить (торги по ним ещё не завершены)
+	tenders, err := queries.GetOrganizerTenders(ctx, organizerOwnerID(c.Sender().ID))
 	if err != nil {
-		fmt.Printf("Ошибка при получении тендеров для удаления: %v\n", err)
-		return c.Send("❌ Не удалось загрузить список тендеров для удаления", &telebot.SendOptions{
+		fmt.Printf("Ошибка при получении тендеров для отмены: %v\n", err)
+		return c.Send("❌ Не удалось загрузить список тендеров для отмены", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizer,
 		})
 	}
 
 	if len(tenders) == 0 {
-		return c.Send("📭 Нет тендеров для удаления (все тендеры завершены)", &telebot.SendOptions{
+		return c.Send("📭 Нет тендеров для отмены (все тендеры завершены)", &telebot.SendOptions{
 			ReplyMarkup: menu.MenuOrganizer,
 		})
 	}
 
-	// Сохраняем тендеры для данного пользователя (для обработки кнопок)
-	userID := c.Sender().ID
-	deleteTenderData[userID] = tenders
-
-	// Отправляем информацию о каждом тендере с кнопкой удаления
+	// Отправляем информацию о каждом тендере с кнопками отмены и удаления
 	for _, tender := range tenders {
 		// Форматируем дату для красивого вывода
 		var formattedDate string
@@ -400,12 +1043,15 @@ func sendTendersForDeletion(c telebot.Context, queries *db.Queries) error {
 		// Форматируем статус с эмодзи
 		statusEmoji, statusText := getStatusWithEmoji(tender.Status)
 
+		lots := tenderLots(queries, tender.ID)
+
 		// Создаем сообщение с информацией о тендере
 		tenderInfo := fmt.Sprintf(
 			"📋 *Тендер*: %s\n\n"+
 				"📝 *Описание:* %s\n"+
 				"💰 *Стартовая цена:* %s руб.\n"+
 				"📈 *Текущая цена:* %s руб.\n"+
+				"%s"+
 				"📅 *Дата начала:* %s\n"+
 				"🗂️ *Классификация:* %s\n"+
 				"👥 *Участников:* %d\n"+
@@ -415,32 +1061,43 @@ func sendTendersForDeletion(c telebot.Context, queries *db.Queries) error {
 			tender.Description.String,
 			formattedPrice,
 			formattedCurrentPrice,
+			formatTenderTerms(tender, lots),
 			formattedDate,
-			classificationNames[tender.Classification.String],
+			tenderClassificationNames(queries, tender, lots),
 			tender.ParticipantsCount,
 			statusEmoji,
 			statusText,
 			tender.ID,
 		)
 
-		// Создаем кнопку удаления для этого тендера
-		deleteBtn := telebot.InlineButton{
-			Unique: "delete_tender", // общий уникальный идентификатор
-			Text:   "🗑️ Удалить тендер",
-			Data:   fmt.Sprintf("%d", tender.ID), // ID тендера храним в Data
+		// Создаем кнопку отмены для этого тендера
+		buttons := []telebot.InlineButton{
+			{
+				Unique: "cancel_tender",
+				Text:   "🚫 Отменить тендер",
+				Data:   fmt.Sprintf("%d", tender.ID), // ID тендера храним в Data
+			},
+		}
+		// Тендер, который ещё не покидал модерацию, можно удалить без следа
+		if isTenderDeletable(ctx, queries, tender) {
+			buttons = append(buttons, telebot.InlineButton{
+				Unique: "delete_tender", // общий уникальный идентификатор
+				Text:   "🗑️ Удалить тендер",
+				Data:   fmt.Sprintf("%d", tender.ID),
+			})
 		}
 
-		// Отправляем информацию о тендере с кнопкой удаления
+		// Отправляем информацию о тендере с кнопками
 		_, err := c.Bot().Send(c.Sender(), tenderInfo, &telebot.SendOptions{
 			ParseMode: telebot.ModeMarkdown,
 			ReplyMarkup: &telebot.ReplyMarkup{
 				InlineKeyboard: [][]telebot.InlineButton{
-					{deleteBtn},
+					buttons,
 				},
 			},
 		})
 		if err != nil {
-			fmt.Printf("Ошибка при отправке информации о тендере для удаления: %v\n", err)
+			fmt.Printf("Ошибка при отправке информации о тендере для отмены: %v\n", err)
 			continue
 		}
 
@@ -448,23 +1105,21 @@ func sendTendersForDeletion(c telebot.Context, queries *db.Queries) error {
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	return c.Send(fmt.Sprintf("✅ Выберите тендер для удаления (всего доступно: %d)", len(tenders)), &telebot.SendOptions{
+	return c.Send(fmt.Sprintf("✅ Выберите тендер для отмены (всего доступно: %d)", len(tenders)), &telebot.SendOptions{
 		ReplyMarkup: menu.MenuOrganizer,
 	})
 }
 
-func saveTenderToDB(userID int64, queries *db.Queries, c telebot.Context) (string, int32, error) {
-	data := organizerData[userID]
-
+func saveTenderToDB(data map[string]string, queries *db.Queries, c telebot.Context) (string, int32, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	startPrice, err := strconv.ParseFloat(data["start_price"], 64)
-	if err != nil {
-		return "", 0, c.Send("Введите корректную числовую стартовую цену!", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuOrganizerCancel,
-		})
+	// Диалоги, начатые до появления лотов, хранят единственный лот в полях верхнего уровня
+	conv := state.Conversation{Data: data}
+	if conv.Get("lots") == "" && conv.Get("start_price") != "" {
+		addConversationLot(&conv)
 	}
+	lots := conversationLots(conv)
 
 	startDateTime, err := time.Parse(time.RFC3339, data["start_date_parsed"])
 	if err != nil {
@@ -473,29 +1128,53 @@ func saveTenderToDB(userID int64, queries *db.Queries, c telebot.Context) (strin
 		})
 	}
 
-	fmt.Println("Создаём тендер:", data)
-	tender, err := queries.CreateTender(ctx, db.CreateTenderParams{
-		Title: data["title"],
-		Description: pgtype.Text{
-			String: data["description"],
-			Valid:  true,
-		},
-		StartPrice: startPrice,
-		StartAt: pgtype.Timestamptz{
-			Time:  startDateTime,
-			Valid: true,
-		},
-		ConditionsPath: pgtype.Text{
-			String: data["conditions_path"],
-			Valid:  data["conditions_path"] != "",
-		},
-		CurrentPrice: startPrice,
-		Classification: pgtype.Text{
-			String: data["classification"],
-			Valid:  data["classification"] != "",
-		},
-	})
+	// Диалоги, начатые до появления закрытых тендеров, создают открытый аукцион
+	tenderType := data["type"]
+	if tenderType == "" {
+		tenderType = db.TenderTypeOpen
+	}
+	var endDateTime time.Time
+	if data["end_date_parsed"] != "" {
+		endDateTime, _ = time.Parse(time.RFC3339, data["end_date_parsed"])
+	}
+	extensionMinutes, _ := strconv.ParseInt(data["extension_minutes"], 10, 32)
+
+	draft := tender.Draft{
+		Title:            data["title"],
+		Description:      data["description"],
+		StartAt:          startDateTime,
+		ConditionsPath:   data["conditions_path"],
+		Type:             tenderType,
+		EndAt:            endDateTime,
+		ExtensionMinutes: int32(extensionMinutes),
+		OrganizerID:      c.Sender().ID,
+		Lots:             lots,
+		Classifications:  splitCodes(data["extra_classifications"]),
+	}
+	// Те же проверки выполняет POST /tenders в REST API
+	if err := draft.Validate(time.Now()); err != nil {
+		return "", 0, c.Send("❌ "+err.Error()+". Начните создание тендера заново.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
 
+	var created db.Tender
+	var createdLots []db.TenderLot
+	headline := "✅ *Тендер успешно создан и отправлен на модерацию!*"
+	if data["resubmit_tender_id"] != "" {
+		// Исправленный после отклонения тендер обновляется на месте, чтобы сохранить историю раундов
+		tenderID, _ := strconv.ParseInt(data["resubmit_tender_id"], 10, 32)
+		created, createdLots, err = resubmitTender(ctx, queries, int32(tenderID), draft, organizerOwnerID(c.Sender().ID).Int64)
+		if errors.Is(err, tender.ErrStatusChanged) {
+			return "", 0, c.Send("❌ Тендер уже не ожидает исправлений.", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizer,
+			})
+		}
+		headline = "✅ *Тендер исправлен и повторно отправлен на модерацию!*"
+	} else {
+		fmt.Println("Создаём тендер:", data)
+		created, createdLots, err = queries.CreateTenderWithLots(ctx, draft.CreateParams(), draft.LotParams(), draft.ClassificationCodes())
+	}
 	if err != nil {
 		fmt.Printf("Ошибка при создании тендера: %v\n", err)
 		return "", 0, c.Send("Ошибка при сохранении данных в БД. Попробуйте снова.", &telebot.SendOptions{
@@ -504,71 +1183,103 @@ func saveTenderToDB(userID int64, queries *db.Queries, c telebot.Context) (strin
 	}
 
 	// Отправляем уведомление админам о новом тендере
-	go sendTenderApprovalNotification(c.Bot(), config.AdminIDs, data, tender.ID, tender.Title)
+	go sendTenderApprovalNotification(c.Bot(), queries, created, createdLots)
 
 	// Форматируем дату для красивого вывода
 	parsedTime, _ := time.Parse(time.RFC3339, data["start_date_parsed"])
 	formattedDate := parsedTime.Format("02.01.2006 15:04")
 
 	// Форматируем цену в финансовом формате
-	formattedPrice := formatPrice(data["start_price"])
+	formattedPrice := formatPriceFloat(created.StartPrice)
 
 	// Создаем сообщение об успехе ПЕРЕД тем как очистить данные
 	successMessage := fmt.Sprintf(
-		"✅ *Тендер успешно создан и отправлен на модерацию!*\n\n"+
+		"%s\n\n"+
 			"📋 *Название:* %s\n"+
 			"📝 *Описание:* %s\n"+
 			"💰 *Стартовая цена:* %s руб.\n"+
+			"%s"+
 			"📅 *Дата начала:* %s\n"+
 			"🗂️ *Классификация:* %s\n\n"+
 			"⏳ *Ожидайте одобрения администратора*",
+		headline,
 		data["title"],
 		data["description"],
 		formattedPrice,
+		formatTenderTerms(created, createdLots),
 		formattedDate,
-		classificationNames[data["classification"]],
+		tenderClassificationNames(queries, created, createdLots),
 	)
 
-	return successMessage, tender.ID, nil
+	return successMessage, created.ID, nil
+}
+
+// NotifyTenderCreated отправляет админам запрос на одобрение тендера,
+// созданного не через бота (например, через REST API)
+func NotifyTenderCreated(bot *telebot.Bot, pool *pgxpool.Pool, newTender db.Tender) {
+	queries := db.New(pool)
+	sendTenderApprovalNotification(bot, queries, newTender, tenderLots(queries, newTender.ID))
 }
 
-func sendTenderApprovalNotification(bot *telebot.Bot, adminIDs []int64, tenderData map[string]string, tenderID int32, tenderTitle string) {
+func sendTenderApprovalNotification(bot *telebot.Bot, queries *db.Queries, newTender db.Tender, lots []db.TenderLot) {
 	// Форматируем дату для красивого вывода
-	parsedTime, _ := time.Parse(time.RFC3339, tenderData["start_date_parsed"])
-	formattedDate := parsedTime.Format("02.01.2006 15:04")
+	formattedDate := newTender.StartAt.Time.Format("02.01.2006 15:04")
 
 	// Форматируем цену в финансовом формате
-	formattedPrice := formatPrice(tenderData["start_price"])
+	formattedPrice := formatPriceFloat(newTender.StartPrice)
+
+	// Повторно отправленный тендер показываем с номером раунда и прошлой причиной отклонения
+	headline := "🆕 *Новый тендер требует одобрения*"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if reviews, err := queries.GetTenderReviews(ctx, newTender.ID); err == nil && len(reviews) > 0 {
+		last := reviews[len(reviews)-1]
+		headline = fmt.Sprintf("🔁 *Тендер отправлен на модерацию повторно (раунд %d)*", len(reviews)+1)
+		if last.Decision == tender.ReviewRejected {
+			headline += fmt.Sprintf("\n\n❗ *Прошлая причина отклонения:* %s", last.Reason)
+		} else {
+			headline += "\n\n✏️ Организатор изменил уже одобренный тендер"
+		}
+	}
 
 	message := fmt.Sprintf(
-		"🆕 *Новый тендер требует одобрения*\n\n"+
+		"%s\n\n"+
 			"📋 *Название:* %s\n"+
 			"📝 *Описание:* %s\n"+
 			"💰 *Стартовая цена:* %s руб.\n"+
+			"%s"+
 			"📅 *Дата начала:* %s\n"+
 			"🗂️ *Классификация:* %s\n\n"+
-			"✅ Для одобрения нажмите кнопку ниже",
-		tenderData["title"],
-		tenderData["description"],
+			"✅ Одобрите тендер или отклоните его с указанием причины",
+		headline,
+		newTender.Title,
+		newTender.Description.String,
 		formattedPrice,
+		formatTenderTerms(newTender, lots),
 		formattedDate,
-		classificationNames[tenderData["classification"]],
+		tenderClassificationNames(queries, newTender, lots),
 	)
 
 	// Создаем кнопку для одобрения
 	approveBtn := telebot.InlineButton{
 		Unique: "approve_tender",
 		Text:   "⏳ Одобрить тендер",
-		Data:   fmt.Sprintf("%d|%s", tenderID, tenderTitle),
+		Data:   fmt.Sprintf("%d|%s", newTender.ID, newTender.Title),
+	}
+
+	rejectBtn := telebot.InlineButton{
+		Unique: "reject_tender",
+		Text:   "🚫 Отклонить",
+		Data:   strconv.Itoa(int(newTender.ID)),
 	}
 
 	// Отправляем сообщение всем админам
-	for _, adminID := range adminIDs {
+	for _, adminID := range usersWithRole(queries, "admin") {
 		_, err := bot.Send(&telebot.User{ID: adminID}, message, &telebot.SendOptions{
 			ParseMode: telebot.ModeMarkdown,
 			ReplyMarkup: &telebot.ReplyMarkup{
 				InlineKeyboard: [][]telebot.InlineButton{
-					{approveBtn},
+					{approveBtn, rejectBtn},
 				},
 			},
 		})
@@ -583,7 +1294,7 @@ func sendOrganizerTendersList(c telebot.Context, queries *db.Queries) error {
 	defer cancel()
 
 	// Получаем все тендеры из БД
-	tenders, err := queries.GetTenders(ctx)
+	tenders, err := queries.GetOrganizerTenders(ctx, organizerOwnerID(c.Sender().ID))
 	if err != nil {
 		fmt.Printf("Ошибка при получении тендеров: %v\n", err)
 		return c.Send("❌ Не удалось загрузить список тендеров", &telebot.SendOptions{
@@ -615,12 +1326,15 @@ func sendOrganizerTendersList(c telebot.Context, queries *db.Queries) error {
 
 		formattedCurrentPrice := formatPriceFloat(tender.CurrentPrice)
 
+		lots := tenderLots(queries, tender.ID)
+
 		// Создаем сообщение с информацией о тендере
 		tenderInfo := fmt.Sprintf(
 			"📋 *Тендер:* %s\n\n"+
 				"📝 *Описание:* %s\n"+
 				"💰 *Стартовая цена:* %s руб.\n"+
 				"📈 *Текущая цена:* %s руб.\n"+
+				"%s"+
 				"📅 *Дата начала:* %s\n"+
 				"🗂️ *Классификация:* %s\n"+
 				"👥 *Участников:* %d\n"+
@@ -630,16 +1344,38 @@ func sendOrganizerTendersList(c telebot.Context, queries *db.Queries) error {
 			tender.Description.String,
 			formattedPrice,
 			formattedCurrentPrice,
+			formatTenderTerms(tender, lots),
 			formattedDate,
-			classificationNames[tender.Classification.String],
+			tenderClassificationNames(queries, tender, lots),
 			tender.ParticipantsCount,
 			statusEmoji,
 			statusText,
 		)
 
+		var keyboard [][]telebot.InlineButton
+		// Отклонённый тендер показываем с причиной и кнопкой повторной отправки
+		if tender.Status == "rejected" {
+			if review, err := queries.GetLastTenderReview(ctx, tender.ID); err == nil {
+				tenderInfo += fmt.Sprintf("\n❗ *Причина отклонения:* %s\n🔁 *Раундов модерации:* %d", escapeMarkdown(review.Reason), review.Round)
+			}
+			keyboard = append(keyboard, []telebot.InlineButton{
+				{Unique: "resubmit_tender", Text: "✏️ Исправить и отправить повторно", Data: strconv.Itoa(int(tender.ID))},
+			})
+		}
+		// До начала торгов тендер можно отредактировать
+		if tender.Status == "pending_approval" || tender.Status == "active_pending" {
+			keyboard = append(keyboard, []telebot.InlineButton{
+				{Unique: "edit_tender", Text: "✏️ Редактировать", Data: strconv.Itoa(int(tender.ID))},
+			})
+		}
+		keyboard = append(keyboard, []telebot.InlineButton{
+			{Unique: "save_template", Text: "💾 Сохранить как шаблон", Data: strconv.Itoa(int(tender.ID))},
+		})
+
 		// Отправляем информацию о тендере
 		if err := c.Send(tenderInfo, &telebot.SendOptions{
-			ParseMode: telebot.ModeMarkdown,
+			ParseMode:   telebot.ModeMarkdown,
+			ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: keyboard},
 		}); err != nil {
 			fmt.Printf("Ошибка при отправке информации о тендере: %v\n", err)
 			continue
@@ -699,7 +1435,7 @@ func sendOrganizerHistory(c telebot.Context, queries *db.Queries) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	tenders, err := queries.GetTendersHistory(ctx)
+	tenders, err := queries.GetOrganizerTendersHistory(ctx, organizerOwnerID(c.Sender().ID))
 	if err != nil {
 		fmt.Printf("Ошибка при получении тендеров: %v\n", err)
 		return c.Send("❌ Не удалось загрузить историю", &telebot.SendOptions{
@@ -712,10 +1448,16 @@ func sendOrganizerHistory(c telebot.Context, queries *db.Queries) error {
 			ReplyMarkup: menu.MenuOrganizer,
 		})
 	}
+	// Кнопки «по образцу» показываем один раз на тендер — под последней записью о нём
+	lastEntry := make(map[int32]int32)
+	for _, entry := range tenders {
+		lastEntry[entry.TenderID] = entry.ID
+	}
+
 	for _, tender := range tenders {
-		bidsHistory, err := queries.GetBidsHistoryByTenderID(ctx, tender.TenderID)
+		bidsHistory, err := queries.GetBidsHistoryByLotID(ctx, tender.LotID)
 		if err != nil {
-			fmt.Printf("Ошибка получения истории ставок для тендера %d: %v\n", tender.TenderID, err)
+			fmt.Printf("Ошибка получения истории ставок для лота %d: %v\n", tender.LotID, err)
 		}
 
 		var bidsHistoryText string
@@ -737,36 +1479,25 @@ func sendOrganizerHistory(c telebot.Context, queries *db.Queries) error {
 			bidsHistoryText = "\n\n📊 *История ставок:*\nСтавки отсутствуют"
 		}
 
-		// Форматируем цену в финансовом формате
-		formattedPrice := formatPriceFloat(tender.StartPrice)
-
-		formattedBidPrice := formatPriceFloat(tender.Bid)
-
 		// Создаем сообщение с информацией о тендере
-		tenderInfo := fmt.Sprintf(
-			"📋 *Тендер*: %s\n\n"+
-				"💰 *Стартовая цена:* %s руб.\n"+
-				"💰 *Выигрышная ставка:* %s руб.\n"+
-				"👑 Победитель: %s\n"+
-				"📞 Контакты победителя:\n"+
-				"   • Телефон: %s\n"+
-				"   • ИНН: %s\n"+
-				"   • ФИО: %s\n"+
-				"%s",
-			tender.Title,
-			formattedPrice,
-			formattedBidPrice,
-			tender.Winner.String,
-			tender.PhoneNumber.String,
-			tender.Inn.String,
-			tender.Fio.String,
-			bidsHistoryText,
-		)
+		tenderInfo := formatHistoryEntry(tender, bidsHistoryText)
 
-		// Отправляем информацию о тендере
-		if err := c.Send(tenderInfo, &telebot.SendOptions{
+		sendOptions := &telebot.SendOptions{
 			ParseMode: telebot.ModeMarkdown,
-		}); err != nil {
+		}
+		if lastEntry[tender.TenderID] == tender.ID {
+			sendOptions.ReplyMarkup = &telebot.ReplyMarkup{
+				InlineKeyboard: [][]telebot.InlineButton{
+					{
+						{Unique: "clone_tender", Text: "🔁 Создать по образцу", Data: strconv.Itoa(int(tender.TenderID))},
+						{Unique: "save_template", Text: "💾 В шаблоны", Data: strconv.Itoa(int(tender.TenderID))},
+					},
+				},
+			}
+		}
+
+		// Отправляем информацию о тендере
+		if err := c.Send(tenderInfo, sendOptions); err != nil {
 			fmt.Printf("Ошибка при отправке информации о тендере: %v\n", err)
 			continue
 		}
@@ -782,7 +1513,7 @@ func sendOrganizerHistory(c telebot.Context, queries *db.Queries) error {
 		time.Sleep(500 * time.Millisecond)
 	}
 
-	return c.Send(fmt.Sprintf("✅ Всего тендеров: %d", len(tenders)), &telebot.SendOptions{
+	return c.Send(fmt.Sprintf("✅ Всего тендеров: %d", historyTenderCount(tenders)), &telebot.SendOptions{
 		ReplyMarkup: menu.MenuOrganizer,
 	})
 }
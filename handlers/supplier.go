@@ -2,16 +2,22 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"tender_bot_go/db"
 	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"tender_bot_go/tender"
+	"tender_bot_go/validation"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gopkg.in/telebot.v3"
@@ -26,8 +32,11 @@ var MessageManagerOperator = &MessageManager{
 	userMessages: make(map[int64][]int),
 }
 
-// Глобальная мапа для хранения таймеров
-var tenderTimers = struct {
+// Время без новых ставок, после которого завершаются торги по лоту
+const tenderInactivityTimeout = 5 * time.Minute
+
+// Глобальная мапа для хранения таймеров лотов
+var lotTimers = struct {
 	sync.RWMutex
 	timers map[int32]*time.Timer
 }{
@@ -108,6 +117,14 @@ const (
 	StatePhone
 	StateSelectClassification
 	StateFIO
+	// Шаги добавлены в конец, чтобы не сдвинуть номера шагов в сохранённых диалогах
+	StateOGRN
+	StateProfilePhone
+	StateProfileName
+	StateProfileClassification
+	StateDocuments
+	StateDocumentKind
+	StateDocumentExpiry
 )
 
 type BidState int
@@ -117,25 +134,32 @@ const (
 	BidStateConfirm
 )
 
-var supplierStates = make(map[int64]SupplierState)
-var supplierData = make(map[int64]map[string]string)
-
-var bidData = make(map[int64]map[string]interface{})
-var bidStates = make(map[int64]BidState)
-
 func RegisterSupplierHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 	queries := db.New(pool)
 
 	// Обработчики inline кнопок для поставщика
-	for code := range classificationNames {
-		classCode := code
-		bot.Handle(&telebot.InlineButton{Unique: "supplier_class_" + classCode}, func(c telebot.Context) error {
-			return handleSupplierClassification(c, classCode)
-		})
-	}
+	bot.Handle(&telebot.InlineButton{Unique: "supplier_class"}, func(c telebot.Context) error {
+		return handleSupplierClassification(c, queries, c.Data())
+	})
 
 	bot.Handle(&telebot.InlineButton{Unique: "supplier_class_done"}, func(c telebot.Context) error {
-		return handleSupplierClassificationDone(c)
+		return handleSupplierClassificationDone(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "document_kind"}, func(c telebot.Context) error {
+		return handleDocumentKind(c)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "documents_done"}, func(c telebot.Context) error {
+		return handleDocumentsDone(c)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "reupload_document"}, func(c telebot.Context) error {
+		return handleReuploadDocument(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "edit_profile"}, func(c telebot.Context) error {
+		return handleEditProfile(c, queries)
 	})
 
 	bot.Handle(&menu.BtnJoinTender, func(c telebot.Context) error {
@@ -154,6 +178,10 @@ func RegisterSupplierHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 		return handleMakeBid(c, queries)
 	})
 
+	bot.Handle(&telebot.InlineButton{Unique: "select_lot"}, func(c telebot.Context) error {
+		return handleSelectLot(c, queries)
+	})
+
 	bot.Handle(&telebot.InlineButton{Unique: "cancel_bid"}, func(c telebot.Context) error {
 		return handleCancelBid(c)
 	})
@@ -161,60 +189,101 @@ func RegisterSupplierHandlers(bot *telebot.Bot, pool *pgxpool.Pool) {
 	bot.Handle(&telebot.InlineButton{Unique: "confirm_bid"}, func(c telebot.Context) error {
 		return handleConfirmBid(c, queries)
 	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "winner_accept"}, func(c telebot.Context) error {
+		return handleWinnerAccept(c, queries)
+	})
+
+	bot.Handle(&telebot.InlineButton{Unique: "winner_decline"}, func(c telebot.Context) error {
+		return handleWinnerDecline(c, queries)
+	})
 }
 
 func HandleSupplierText(c telebot.Context, queries *db.Queries, text string, userID int64) error {
-	if state, exists := bidStates[userID]; exists {
-		return handleBidText(c, queries, text, userID, state)
-	}
-	if _, exists := supplierData[userID]; !exists {
-		supplierData[userID] = make(map[string]string)
-	}
-
 	if text == "Регистрация" {
-		supplierStates[userID] = StateOrgName
-		supplierData[userID] = make(map[string]string)
+		if hasPendingRegistration(queries, userID) {
+			return c.Send("⏳ Ваша заявка на регистрацию уже на рассмотрении. Дождитесь решения администратора.")
+		}
+		saveConversation(userID, state.FlowSupplier, state.Conversation{Step: int(StateOrgName)})
 		return c.Send("Введите наименование вашей организации:")
 	}
 
+	// Кнопки меню прерывают незавершённый ввод ставки
 	if text == "Тендеры" {
+		clearConversation(userID, state.FlowBid)
 		return sendSupplierTendersList(c, queries, userID)
 	}
 
 	if text == "Подать заявку" {
+		clearConversation(userID, state.FlowBid)
 		return bidTender(c, queries)
 	}
 
-	state := supplierStates[userID]
-	switch state {
+	if text == "Мои участия" {
+		clearConversation(userID, state.FlowBid)
+		return sendSupplierParticipations(c, queries, userID)
+	}
+
+	if text == "Профиль" {
+		clearConversation(userID, state.FlowBid)
+		clearConversation(userID, state.FlowSupplier)
+		return sendSupplierProfile(c, queries, userID)
+	}
+
+	if bidConv, exists := loadConversation(userID, state.FlowBid); exists {
+		return handleBidText(c, queries, text, userID, bidConv)
+	}
+
+	conv, _ := loadConversation(userID, state.FlowSupplier)
+	switch SupplierState(conv.Step) {
 	case StateOrgName:
-		supplierData[userID]["org_name"] = text
-		supplierStates[userID] = StateINN
+		conv.Put("org_name", text)
+		conv.Step = int(StateINN)
+		saveConversation(userID, state.FlowSupplier, conv)
 		return c.Send("Введите ИНН организации:")
 	case StateINN:
-		if len(text) != 10 && len(text) != 12 {
-			return c.Send("ИНН должен содержать 10 или 12 цифр. Попробуйте снова:")
+		inn := strings.TrimSpace(text)
+		if err := validation.ValidateINN(inn); err != nil {
+			return c.Send("❌ " + err.Error() + ". Введите ИНН ещё раз:")
+		}
+		if err := checkRegistrationDetails(queries, userID, validation.CheckINNAvailable, inn); err != nil {
+			return c.Send("❌ " + err.Error())
+		}
+		conv.Put("inn", inn)
+		conv.Step = int(StateOGRN)
+		saveConversation(userID, state.FlowSupplier, conv)
+		if len(inn) == validation.PersonINNLength {
+			return c.Send("Введите ОГРНИП (15 цифр):")
 		}
-		supplierData[userID]["inn"] = text
-		supplierStates[userID] = StatePhone
+		return c.Send("Введите ОГРН организации (13 цифр):")
+	case StateOGRN:
+		ogrn := strings.TrimSpace(text)
+		if err := validation.ValidateOGRN(ogrn, conv.Get("inn")); err != nil {
+			return c.Send("❌ " + err.Error() + ". Введите номер ещё раз:")
+		}
+		if err := checkRegistrationDetails(queries, userID, validation.CheckOGRNAvailable, ogrn); err != nil {
+			return c.Send("❌ " + err.Error())
+		}
+		conv.Put("ogrn", ogrn)
+		if conv.Get("kind") == db.PendingKindRequisites {
+			return submitRequisitesChange(c, queries, userID, conv)
+		}
+		conv.Step = int(StatePhone)
+		saveConversation(userID, state.FlowSupplier, conv)
 		return c.Send("Введите контактный телефон:")
 	case StatePhone:
-		phone := ""
-		for _, r := range text {
-			if r >= '0' && r <= '9' {
-				phone += string(r)
-			}
-		}
-		if len(phone) < 10 {
+		phone, ok := parsePhone(text)
+		if !ok {
 			return c.Send("Введите корректный номер телефона:")
 		}
-		supplierData[userID]["phone"] = phone
-		supplierData[userID]["classifications"] = ""
-		supplierStates[userID] = StateSelectClassification
-		markup := showSupplierClassificationKeyboard(userID)
-		return c.Send("Выберите до двух классификаций вашей организации:", markup)
+		conv.Put("phone", phone)
+		conv.Put("classifications", "")
+		conv.Step = int(StateSelectClassification)
+		saveConversation(userID, state.FlowSupplier, conv)
+		markup := showSupplierClassificationKeyboard(activeClassifications(queries), "")
+		return c.Send(supplierClassificationPrompt, markup)
 	case StateFIO:
-		supplierData[userID]["fio"] = text
+		conv.Put("fio", text)
 
 		// Сохраняем данные в pending_users вместо непосредственной регистрации
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -223,54 +292,106 @@ func HandleSupplierText(c telebot.Context, queries *db.Queries, text string, use
 		err := queries.CreatePendingUser(ctx, db.CreatePendingUserParams{
 			TelegramID: userID,
 			OrganizationName: pgtype.Text{
-				String: supplierData[userID]["org_name"],
+				String: conv.Get("org_name"),
 				Valid:  true,
 			},
 			Inn: pgtype.Text{
-				String: supplierData[userID]["inn"],
+				String: conv.Get("inn"),
 				Valid:  true,
 			},
+			Ogrn: pgtype.Text{
+				String: conv.Get("ogrn"),
+				Valid:  conv.Get("ogrn") != "",
+			},
 			PhoneNumber: pgtype.Text{
-				String: supplierData[userID]["phone"],
+				String: conv.Get("phone"),
 				Valid:  true,
 			},
 			Name: pgtype.Text{
-				String: supplierData[userID]["fio"],
+				String: conv.Get("fio"),
 				Valid:  true,
 			},
 			Classification: pgtype.Text{
-				String: supplierData[userID]["classifications"],
+				String: conv.Get("classifications"),
 				Valid:  true,
 			},
+			Kind: db.PendingKindRegistration,
 		})
 
+		if isUniqueViolation(err) {
+			clearConversation(userID, state.FlowSupplier)
+			return c.Send("⏳ Ваша заявка на регистрацию уже на рассмотрении. Дождитесь решения администратора.")
+		}
 		if err != nil {
 			fmt.Printf("Ошибка при сохранении данных ожидания: %v\n", err)
 			return c.Send("❌ Ошибка при сохранении данных. Попробуйте снова.")
 		}
 
-		// Отправляем уведомление администраторам
-		sendRegistrationRequestToAdmins(c, queries, userID)
+		// Отправляем заявку администраторам и переходим к документам
+		return startSupplierDocuments(c, queries, userID, conv)
+	case StateDocuments:
+		return c.Send("Пришлите файл документа или нажмите «Готово».")
+	case StateDocumentKind:
+		return c.Send("Выберите вид документа кнопкой выше.")
+	case StateDocumentExpiry:
+		return handleDocumentExpiryText(c, queries, text, userID, conv)
+	case StateProfilePhone:
+		phone, ok := parsePhone(text)
+		if !ok {
+			return c.Send("Введите корректный номер телефона:")
+		}
+		return updateSupplierProfile(c, queries, userID, func(params *db.UpdateUserProfileParams) {
+			params.PhoneNumber = pgtype.Text{String: phone, Valid: true}
+		})
+	case StateProfileName:
+		name := strings.TrimSpace(text)
+		if name == "" || len([]rune(name)) > maxContactNameLength {
+			return c.Send("ФИО должно быть непустым и не длиннее 255 символов. Попробуйте снова:")
+		}
+		return updateSupplierProfile(c, queries, userID, func(params *db.UpdateUserProfileParams) {
+			params.Name = pgtype.Text{String: name, Valid: true}
+		})
+	default:
+		return nil
+	}
+}
 
-		delete(supplierStates, userID)
-		delete(supplierData, userID)
+// hasPendingRegistration сообщает, что у пользователя уже есть заявка на регистрацию
+func hasPendingRegistration(queries *db.Queries, userID int64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-		msg, err := c.Bot().Send(c.Sender(), "✅ Заявка на регистрацию отправлена на модерацию!\n\nОжидайте подтверждения администратора.", &telebot.SendOptions{
-			ReplyMarkup: &telebot.ReplyMarkup{
-				RemoveKeyboard: true,
-			},
-		})
+	_, err := queries.GetPendingUser(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		fmt.Printf("Ошибка проверки заявки пользователя %d: %v\n", userID, err)
+	}
+	return err == nil
+}
 
-		if err != nil {
-			return err
-		}
+// checkRegistrationDetails проверяет, что реквизит не занят другим пользователем или
+// чужой заявкой. Ошибки базы не мешают регистрации: повторную проверку выполнит
+// уникальный индекс при одобрении заявки.
+func checkRegistrationDetails(queries *db.Queries, userID int64, check func(context.Context, validation.RegistryStore, string, int64) error, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-		MessageManagerOperator.AddMessage(userID, msg.ID)
+	err := check(ctx, queries, value, userID)
+	switch {
+	case errors.Is(err, validation.ErrINNRegistered), errors.Is(err, validation.ErrINNPending),
+		errors.Is(err, validation.ErrOGRNRegistered), errors.Is(err, validation.ErrOGRNPending):
+		return err
+	case err != nil:
+		fmt.Printf("Ошибка проверки реквизитов пользователя %d: %v\n", userID, err)
+	}
+	return nil
+}
 
-		return nil
-	default:
-		return nil
+// pendingOGRN возвращает ОГРН из заявки; у заявок, поданных до его проверки, его нет
+func pendingOGRN(pendingUser db.PendingUser) string {
+	if !pendingUser.Ogrn.Valid {
+		return "не указан"
 	}
+	return pendingUser.Ogrn.String
 }
 
 func sendRegistrationRequestToAdmins(c telebot.Context, queries *db.Queries, userID int64) {
@@ -284,32 +405,26 @@ func sendRegistrationRequestToAdmins(c telebot.Context, queries *db.Queries, use
 		return
 	}
 
-	// Форматируем классификации
-	classifications := strings.Split(pendingUser.Classification.String, ",")
-	var classificationNamesList []string
-	for _, code := range classifications {
-		if name, exists := classificationNames[code]; exists {
-			classificationNamesList = append(classificationNamesList, name)
-		}
-	}
-
 	// Формируем сообщение для администраторов
 	message := fmt.Sprintf(
-		"🆕 *НОВАЯ ЗАЯВКА НА РЕГИСТРАЦИЮ*\n\n"+
+		"%s\n\n"+
 			"👤 *Пользователь:* @%s (ID: %d)\n"+
 			"🏢 *Организация:* %s\n"+
 			"🆔 *ИНН:* %s\n"+
+			"🧾 *ОГРН:* %s\n"+
 			"📞 *Телефон:* %s\n"+
 			"👨‍💼 *ФИО:* %s\n"+
 			"🗂️ *Классификации:* %s\n\n"+
 			"⏰ *Время подачи:* %s",
+		pendingUserHeadline(queries, pendingUser),
 		c.Sender().Username,
 		userID,
 		pendingUser.OrganizationName.String,
 		pendingUser.Inn.String,
+		pendingOGRN(pendingUser),
 		pendingUser.PhoneNumber.String,
 		pendingUser.Name.String,
-		strings.Join(classificationNamesList, ", "),
+		classificationList(pendingUser.Classification.String),
 		pendingUser.CreatedAt.Time.Format("02.01.2006 15:04"),
 	)
 
@@ -329,8 +444,10 @@ func sendRegistrationRequestToAdmins(c telebot.Context, queries *db.Queries, use
 		},
 	}
 
+	documents := pendingUserDocuments(queries, pendingUser.ID)
+
 	// Отправляем всем администраторам
-	for _, adminID := range config.AdminIDs {
+	for _, adminID := range usersWithRole(queries, "admin") {
 		_, err := c.Bot().Send(&telebot.User{ID: adminID}, message, &telebot.SendOptions{
 			ParseMode: telebot.ModeMarkdown,
 			ReplyMarkup: &telebot.ReplyMarkup{
@@ -339,7 +456,9 @@ func sendRegistrationRequestToAdmins(c telebot.Context, queries *db.Queries, use
 		})
 		if err != nil {
 			fmt.Printf("Ошибка отправки уведомления администратору %d: %v\n", adminID, err)
+			continue
 		}
+		sendSupplierDocuments(c.Bot(), &telebot.User{ID: adminID}, documents)
 	}
 }
 
@@ -351,8 +470,7 @@ func handleCancelBid(c telebot.Context) error {
 	MessageManagerOperator.CleanupSessionMessages(c.Bot(), userID, oldMessages)
 
 	// Очищаем состояние
-	delete(bidStates, userID)
-	delete(bidData, userID)
+	clearConversation(userID, state.FlowBid)
 
 	// Ждем немного чтобы удаление завершилось
 	time.Sleep(300 * time.Millisecond)
@@ -377,20 +495,10 @@ func handleCancelBid(c telebot.Context) error {
 func bidTender(c telebot.Context, queries *db.Queries) error {
 	userId := c.Sender().ID
 
-	// Получаем тендер, в котором участвует пользователь
-	tenderId, err := queries.GetTenderFromParticipants(context.Background(), userId)
-	if err != nil {
-		errorMsg := "❌ Вы не участвуете ни в одном тендере или произошла ошибка."
-		msg, err := c.Bot().Send(c.Sender(), errorMsg)
-		if err == nil {
-			MessageManagerOperator.AddMessage(userId, msg.ID)
-		}
-		return err
-	}
-
-	tender, err := queries.GetTenderById(context.Background(), tenderId)
+	// Получаем все активные тендеры, в которых участвует пользователь
+	tenders, err := queries.GetActiveTendersForParticipant(context.Background(), userId)
 	if err != nil {
-		errorMsg := "❌ Произошла ошибка при получении информации о тендере. Попробуйте снова."
+		errorMsg := "❌ Произошла ошибка при получении информации о тендерах. Попробуйте снова."
 		msg, err := c.Bot().Send(c.Sender(), errorMsg)
 		if err == nil {
 			MessageManagerOperator.AddMessage(userId, msg.ID)
@@ -398,76 +506,15 @@ func bidTender(c telebot.Context, queries *db.Queries) error {
 		return err
 	}
 
-	// Проверяем статус тендера
-	if tender.Status != "active" {
-		errorMsg := "❌ Тендер не активен. Подача ставок невозможна."
+	if len(tenders) == 0 {
+		errorMsg := "❌ Вы не участвуете ни в одном активном тендере. Подача ставок невозможна."
 		msg, err := c.Bot().Send(c.Sender(), errorMsg)
 		if err == nil {
 			MessageManagerOperator.AddMessage(userId, msg.ID)
 		}
-		return fmt.Errorf(errorMsg)
-	}
-
-	// Получаем предыдущие ставки пользователя в этом тендере
-	previousBids, err := queries.GetUserBidsForTender(context.Background(), db.GetUserBidsForTenderParams{
-		TenderID: tender.ID,
-		UserID:   userId,
-	})
-	if err != nil {
-		fmt.Printf("Ошибка получения предыдущих ставок: %v\n", err)
-	}
-
-	// Инициализируем данные для ставки
-	if _, exists := bidData[userId]; !exists {
-		bidData[userId] = make(map[string]interface{})
-	}
-
-	bidData[userId]["tender_id"] = tender.ID
-	bidData[userId]["tender_title"] = tender.Title
-	bidData[userId]["start_price"] = tender.StartPrice
-	bidData[userId]["previous_bids"] = previousBids
-	bidData[userId]["current_price"] = tender.CurrentPrice
-
-	bidStates[userId] = BidStateEnterPrice
-
-	// Получаем минимально возможную ставку
-	var minBid float64
-	if tender.CurrentPrice-tender.CurrentPrice*0.01 >= 0 {
-		minBid = tender.CurrentPrice*0.01
-	} else {
-		minBid = 0
-	}
-
-	formattedMinBid := formatPriceFloat(minBid)
-	formattedCurrentPrice := formatPriceFloat(tender.CurrentPrice)
-	formattedStartPrice := formatPriceFloat(tender.StartPrice)
-
-	// Формируем сообщение с предыдущими ставками
-	message := fmt.Sprintf(
-		"📋 *Тендер:* %s\n"+
-			"💰 *Стартовая цена:* %s руб.\n"+
-			"💰 *Текущая цена:* %s руб.\n"+
-			"📊 *Минимальное понижение ставки на 1%% от текущей или* %s руб.",
-		tender.Title,
-		formattedStartPrice,
-		formattedCurrentPrice,
-		formattedMinBid,
-	)
-
-	// Добавляем информацию о предыдущих ставках
-	if len(previousBids) > 0 {
-		message += "\n📊 *Ваши предыдущие ставки:*\n"
-		for i, bid := range previousBids {
-			formattedBidAmount := formatPriceFloat(bid.Amount)
-			message += fmt.Sprintf("%d. %s руб. (%s)\n",
-				i+1,
-				formattedBidAmount,
-				bid.BidTime.Time.Format("02.01.2006 15:04"))
-		}
+		return errors.New(errorMsg)
 	}
 
-	message += "\nВведите вашу новую ставку в рублях:"
-
 	// УДАЛЯЕМ СТАРЫЕ СООБЩЕНИЯ СИНХРОННО
 	oldMessages := MessageManagerOperator.StartNewSession(userId)
 	MessageManagerOperator.CleanupSessionMessages(c.Bot(), userId, oldMessages)
@@ -475,10 +522,12 @@ func bidTender(c telebot.Context, queries *db.Queries) error {
 	// Ждем немного чтобы удаление завершилось
 	time.Sleep(300 * time.Millisecond)
 
-	// Отправляем новое сообщение и сохраняем его ID
-	msg, err := c.Bot().Send(c.Sender(), message, &telebot.SendOptions{
-		ParseMode: telebot.ModeMarkdown,
-	})
+	// В единственном тендере сразу переходим к ставке
+	if len(tenders) == 1 {
+		err = startLotBid(c, queries, tenders[0])
+	} else {
+		err = sendTenderPicker(c, queries, tenders)
+	}
 
 	if err != nil {
 		// Сохраняем ID сообщения об ошибке, если отправка не удалась
@@ -490,9 +539,48 @@ func bidTender(c telebot.Context, queries *db.Queries) error {
 		return err
 	}
 
-	// СОХРАНЯЕМ ID НОВОГО СООБЩЕНИЯ
-	MessageManagerOperator.AddMessage(userId, msg.ID)
+	return nil
+}
+
+// sendTenderPicker показывает активные тендеры поставщика с текущей ценой и временем
+// до завершения торгов. Выбранный тендер передаётся в make_bid.
+func sendTenderPicker(c telebot.Context, queries *db.Queries, tenders []db.Tender) error {
+	userID := c.Sender().ID
+
+	message := "📋 *Выберите тендер для подачи ставки:*\n"
+	var rows [][]telebot.InlineButton
+	for i, tender := range tenders {
+		lots := tenderLots(queries, tender.ID)
+
+		priceLine := fmt.Sprintf("💰 Текущая цена: %s руб.", formatPriceFloat(tender.CurrentPrice))
+		if tender.Type == db.TenderTypeSealed {
+			priceLine = fmt.Sprintf("🔒 Стартовая цена: %s руб.", formatPriceFloat(tender.StartPrice))
+		}
+
+		timeLine := "⏳ Ставок ещё нет — отсчёт начнётся после первой ставки"
+		if deadline, ok := tenderDeadline(tender, lots); ok {
+			timeLine = fmt.Sprintf("⏳ До завершения: %s", formatTimeLeft(time.Until(deadline)))
+		}
+
+		message += fmt.Sprintf("\n%d. *%s*\n   %s\n   %s\n", i+1, tender.Title, priceLine, timeLine)
+
+		rows = append(rows, []telebot.InlineButton{{
+			Unique: "make_bid",
+			Text:   fmt.Sprintf("%d. %s", i+1, tender.Title),
+			Data:   fmt.Sprintf("%d|%d", tender.ID, userID),
+		}})
+	}
 
+	msg, err := c.Bot().Send(c.Sender(), message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{
+			InlineKeyboard: rows,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	MessageManagerOperator.AddMessage(userID, msg.ID)
 	return nil
 }
 func handleMakeBid(c telebot.Context, queries *db.Queries) error {
@@ -505,7 +593,14 @@ func handleMakeBid(c telebot.Context, queries *db.Queries) error {
 		})
 	}
 
-	tenderID, _ := strconv.ParseInt(parts[0], 10, 32)
+	// Тендер определяется только данными кнопки: поставщик может участвовать в нескольких тендерах
+	tenderID, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка формата данных",
+			ShowAlert: true,
+		})
+	}
 	userID := c.Sender().ID
 
 	// Получаем информацию о тендере
@@ -537,137 +632,312 @@ func handleMakeBid(c telebot.Context, queries *db.Queries) error {
 		})
 	}
 
-	// Получаем предыдущие ставки
-	previousBids, err := queries.GetUserBidsForTender(context.Background(), db.GetUserBidsForTenderParams{
-		TenderID: int32(tenderID),
-		UserID:   userID,
-	})
-	if err != nil {
-		fmt.Printf("Ошибка получения предыдущих ставок: %v\n", err)
+	if err := startLotBid(c, queries, tender); err != nil {
+		fmt.Printf("Ошибка при отправке сообщения: %v\n", err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка при обновлении сообщения",
+			ShowAlert: true,
+		})
 	}
 
-	// Инициализируем данные ставки
-	if _, exists := bidData[userID]; !exists {
-		bidData[userID] = make(map[string]interface{})
-	}
+	// СОХРАНЯЕМ ID СООБЩЕНИЯ ДЛЯ ПОСЛЕДУЮЩЕГО УДАЛЕНИЯ
+	MessageManagerOperator.AddMessage(userID, c.Message().ID)
 
-	bidData[userID]["tender_id"] = tender.ID
-	bidData[userID]["tender_title"] = tender.Title
-	bidData[userID]["start_price"] = tender.StartPrice
-	bidData[userID]["previous_bids"] = previousBids
-	bidData[userID]["current_price"] = tender.CurrentPrice
-	bidData[userID]["participants_count"] = tender.ParticipantsCount
+	return c.Respond()
+}
 
-	bidStates[userID] = BidStateEnterPrice
+// startLotBid начинает подачу ставки в тендере: если торги идут по одному лоту,
+// сразу просит ввести ставку, иначе предлагает выбрать лот
+func startLotBid(c telebot.Context, queries *db.Queries, tender db.Tender) error {
+	userID := c.Sender().ID
 
-	var minBid float64
-	if tender.CurrentPrice-tender.CurrentPrice*0.01 >= 0 {
-		minBid = tender.CurrentPrice*0.01
-	} else {
-		minBid = 0
+	lots, err := queries.GetOpenTenderLots(context.Background(), tender.ID)
+	if err != nil {
+		return err
 	}
 
-	formattedMinBid := formatPriceFloat(minBid)
-	formattedCurrentPrice := formatPriceFloat(tender.CurrentPrice)
-	formattedStartPrice := formatPriceFloat(tender.StartPrice)
+	if len(lots) == 0 {
+		msg, err := c.Bot().Send(c.Sender(), "❌ Торги по всем лотам тендера уже завершены.")
+		if err == nil {
+			MessageManagerOperator.AddMessage(userID, msg.ID)
+		}
+		return err
+	}
 
-	// Формируем сообщение
-	message := fmt.Sprintf(
-		"📋 *Тендер:* %s\n"+
-			"💰 *Стартовая цена:* %s руб.\n"+
-			"💰 *Текущая цена:* %s руб.\n"+
-			"📊 *Минимальное понижение ставки на 1%% от текущей:* %s руб.",
-		tender.Title,
-		formattedStartPrice,
-		formattedCurrentPrice,
-		formattedMinBid,
-	)
+	if len(lots) == 1 {
+		return sendLotBidPrompt(c, queries, tender, lots[0])
+	}
 
-	if len(previousBids) > 0 {
-		message += "\n📊 *Ваши предыдущие ставки:*\n"
-		for i, bid := range previousBids {
-			formattedBidAmount := formatPriceFloat(bid.Amount)
-			message += fmt.Sprintf("%d. %s руб. (%s)\n",
-				i+1,
-				formattedBidAmount,
-				bid.BidTime.Time.Format("02.01.2006 15:04"))
+	var rows [][]telebot.InlineButton
+	for _, lot := range lots {
+		price := lot.CurrentPrice
+		if tender.Type == db.TenderTypeSealed {
+			price = lot.StartPrice
 		}
+		rows = append(rows, []telebot.InlineButton{{
+			Unique: "select_lot",
+			Text:   fmt.Sprintf("%s — %s руб.", lotLabel(lot), formatPriceFloat(price)),
+			Data:   strconv.Itoa(int(lot.ID)),
+		}})
 	}
 
-	message += "\nВведите вашу новую ставку в рублях:"
-
-	msg, err := c.Bot().Send(c.Sender(), message, &telebot.SendOptions{
+	msg, err := c.Bot().Send(c.Sender(), fmt.Sprintf("📋 *Тендер:* %s\n\nВыберите лот, по которому хотите подать ставку:", tender.Title), &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{
+			InlineKeyboard: rows,
+		},
 	})
-
 	if err != nil {
-		fmt.Printf("Ошибка при редактировании сообщения: %v\n", err)
-		return c.Respond(&telebot.CallbackResponse{
-			Text:      "❌ Ошибка при обновлении сообщения",
-			ShowAlert: true,
-		})
+		return err
 	}
-
 	MessageManagerOperator.AddMessage(userID, msg.ID)
-
-	// СОХРАНЯЕМ ID СООБЩЕНИЯ ДЛЯ ПОСЛЕДУЮЩЕГО УДАЛЕНИЯ
-	// Получаем ID текущего сообщения (которое мы только что отредактировали)
-	messageID := c.Message().ID
-	MessageManagerOperator.AddMessage(userID, messageID)
-
 	return nil
 }
-func handleViewBids(c telebot.Context, queries *db.Queries) error {
-	data := c.Data()
-	parts := strings.Split(data, "|")
-	if len(parts) != 2 {
+
+func handleSelectLot(c telebot.Context, queries *db.Queries) error {
+	lotID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
 		return c.Respond(&telebot.CallbackResponse{
 			Text:      "❌ Ошибка формата данных",
 			ShowAlert: true,
 		})
 	}
-
-	tenderID, _ := strconv.ParseInt(parts[0], 10, 32)
 	userID := c.Sender().ID
 
-	// Получаем все ставки пользователя
-	bids, err := queries.GetUserBidsForTender(context.Background(), db.GetUserBidsForTenderParams{
-		TenderID: int32(tenderID),
-		UserID:   userID,
-	})
+	lot, err := queries.GetTenderLot(context.Background(), int32(lotID))
 	if err != nil {
 		return c.Respond(&telebot.CallbackResponse{
-			Text:      "❌ Ошибка получения ставок",
+			Text:      "❌ Лот не найден",
 			ShowAlert: true,
 		})
 	}
 
-	if len(bids) == 0 {
+	tender, err := queries.GetTender(context.Background(), lot.TenderID)
+	if err != nil {
 		return c.Respond(&telebot.CallbackResponse{
-			Text:      "📭 У вас нет ставок в этом тендере",
+			Text:      "❌ Ошибка получения данных тендера",
 			ShowAlert: true,
 		})
 	}
 
-	// Формируем сообщение с историей ставок
-	message := "📊 *История ваших ставок*\n\n"
-	for i, bid := range bids {
-		message += fmt.Sprintf("%d. *%.2f руб.* - %s\n",
-			i+1,
-			bid.Amount,
-			bid.BidTime.Time.Format("02.01.2006 15:04"))
+	isParticipating, err := queries.CheckTenderParticipation(context.Background(), db.CheckTenderParticipationParams{
+		TenderID: tender.ID,
+		UserID:   userID,
+	})
+	if err != nil || !isParticipating {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Вы не участвуете в этом тендере",
+			ShowAlert: true,
+		})
 	}
 
-	// Редактируем сообщение
-	err = c.Edit(message, &telebot.SendOptions{
-		ParseMode: telebot.ModeMarkdown,
-		ReplyMarkup: &telebot.ReplyMarkup{
-			InlineKeyboard: [][]telebot.InlineButton{
-				{
-					{Unique: "make_bid", Text: "💵 Сделать новую ставку", Data: fmt.Sprintf("%d|%d", tenderID, userID)},
-				},
+	if tender.Status != "active" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не активен",
+			ShowAlert: true,
+		})
+	}
+
+	if lot.Status != db.LotStatusOpen {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Торги по этому лоту уже завершены",
+			ShowAlert: true,
+		})
+	}
+
+	if err := sendLotBidPrompt(c, queries, tender, lot); err != nil {
+		fmt.Printf("Ошибка при отправке сообщения: %v\n", err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка при обновлении сообщения",
+			ShowAlert: true,
+		})
+	}
+
+	return c.Respond()
+}
+
+// sendLotBidPrompt показывает условия торгов по лоту и предыдущие ставки
+// пользователя и ждёт ввода новой ставки
+func sendLotBidPrompt(c telebot.Context, queries *db.Queries, tender db.Tender, lot db.TenderLot) error {
+	userID := c.Sender().ID
+
+	// Получаем предыдущие ставки пользователя по этому лоту
+	previousBids, err := queries.GetUserBidsForLot(context.Background(), db.GetUserBidsForLotParams{
+		LotID:  lot.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка получения предыдущих ставок: %v\n", err)
+	}
+
+	if tender.Type == db.TenderTypeSealed {
+		return sendSealedBidPrompt(c, tender, lot, previousBids)
+	}
+
+	// Инициализируем данные для ставки
+	saveConversation(userID, state.FlowBid, newBidConversation(tender, lot))
+
+	// Формируем сообщение с предыдущими ставками
+	message := fmt.Sprintf(
+		"📋 *Тендер:* %s\n"+
+			"📦 *%s*\n"+
+			"💰 *Стартовая цена:* %s руб.\n"+
+			"💰 *Текущая цена:* %s руб.\n"+
+			"📊 *Шаг понижения:* %s\n"+
+			"📉 *Ставка не выше:* %s руб.",
+		tender.Title,
+		lotLabel(lot),
+		formatPriceFloat(lot.StartPrice),
+		formatPriceFloat(lot.CurrentPrice),
+		formatBidStep(lot.MinBidStepType, lot.MinBidDecrease),
+		formatPriceFloat(db.NextAllowedBid(tender, lot)),
+	)
+
+	if tender.EndAt.Valid {
+		message += fmt.Sprintf("\n⏳ *Окончание торгов:* %s", tender.EndAt.Time.Format("02.01.2006 15:04"))
+	}
+
+	// Добавляем информацию о предыдущих ставках
+	if len(previousBids) > 0 {
+		message += "\n📊 *Ваши предыдущие ставки:*\n"
+		for i, bid := range previousBids {
+			formattedBidAmount := formatPriceFloat(bid.Amount)
+			message += fmt.Sprintf("%d. %s руб. (%s)\n",
+				i+1,
+				formattedBidAmount,
+				bid.BidTime.Time.Format("02.01.2006 15:04"))
+		}
+	}
+
+	message += "\nВведите вашу новую ставку в рублях:"
+
+	msg, err := c.Bot().Send(c.Sender(), message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+	})
+	if err != nil {
+		return err
+	}
+
+	// СОХРАНЯЕМ ID НОВОГО СООБЩЕНИЯ
+	MessageManagerOperator.AddMessage(userID, msg.ID)
+
+	return nil
+}
+
+// sendSealedBidPrompt приглашает подать предложение по лоту закрытого тендера. Текущая
+// цена и шаг не показываются, а повторное предложение не принимается.
+func sendSealedBidPrompt(c telebot.Context, tender db.Tender, lot db.TenderLot, previousBids []db.TenderBid) error {
+	userID := c.Sender().ID
+	deadline := tender.EndAt.Time.Format("02.01.2006 15:04")
+
+	var message string
+	if len(previousBids) > 0 {
+		message = fmt.Sprintf(
+			"🔒 *Закрытый тендер:* %s\n"+
+				"📦 *%s*\n\n"+
+				"✉️ Вы уже подали предложение: *%s руб.*\n"+
+				"В закрытом тендере предложение по лоту подаётся один раз. Итоги будут объявлены после %s.",
+			tender.Title,
+			lotLabel(lot),
+			formatPriceFloat(previousBids[0].Amount),
+			deadline,
+		)
+	} else {
+		saveConversation(userID, state.FlowBid, newBidConversation(tender, lot))
+		message = fmt.Sprintf(
+			"🔒 *Закрытый тендер:* %s\n"+
+				"📦 *%s*\n"+
+				"💰 *Стартовая цена:* %s руб.\n"+
+				"⏳ *Приём предложений до:* %s\n\n"+
+				"Предложение подаётся один раз, другие участники его не увидят.\n"+
+				"Введите ваше предложение в рублях (не выше стартовой цены):",
+			tender.Title,
+			lotLabel(lot),
+			formatPriceFloat(lot.StartPrice),
+			deadline,
+		)
+	}
+
+	msg, err := c.Bot().Send(c.Sender(), message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+	})
+	if err != nil {
+		return err
+	}
+	MessageManagerOperator.AddMessage(userID, msg.ID)
+	return nil
+}
+
+func handleViewBids(c telebot.Context, queries *db.Queries) error {
+	data := c.Data()
+	parts := strings.Split(data, "|")
+	if len(parts) != 2 {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка формата данных",
+			ShowAlert: true,
+		})
+	}
+
+	tenderID, _ := strconv.ParseInt(parts[0], 10, 32)
+	userID := c.Sender().ID
+
+	// Получаем все ставки пользователя
+	bids, err := queries.GetUserBidsForTender(context.Background(), db.GetUserBidsForTenderParams{
+		TenderID: int32(tenderID),
+		UserID:   userID,
+	})
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка получения ставок",
+			ShowAlert: true,
+		})
+	}
+
+	if len(bids) == 0 {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "📭 У вас нет ставок в этом тендере",
+			ShowAlert: true,
+		})
+	}
+
+	// Если лотов несколько, указываем, к какому относится ставка
+	lotNumbers := make(map[int32]int32)
+	if lots := tenderLots(queries, int32(tenderID)); len(lots) > 1 {
+		for _, lot := range lots {
+			lotNumbers[lot.ID] = lot.Number
+		}
+	}
+
+	// Формируем сообщение с историей ставок
+	message := "📊 *История ваших ставок*\n\n"
+	for i, bid := range bids {
+		lotPrefix := ""
+		if number, ok := lotNumbers[bid.LotID]; ok {
+			lotPrefix = fmt.Sprintf("Лот №%d: ", number)
+		}
+		message += fmt.Sprintf("%d. %s*%.2f руб.* - %s\n",
+			i+1,
+			lotPrefix,
+			bid.Amount,
+			bid.BidTime.Time.Format("02.01.2006 15:04"))
+	}
+
+	// В закрытом тендере предложение подаётся один раз
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{
+				{Unique: "make_bid", Text: "💵 Сделать новую ставку", Data: fmt.Sprintf("%d|%d", tenderID, userID)},
 			},
 		},
+	}
+	if tender, err := queries.GetTender(context.Background(), int32(tenderID)); err == nil && tender.Type == db.TenderTypeSealed {
+		markup = &telebot.ReplyMarkup{}
+	}
+
+	// Редактируем сообщение
+	err = c.Edit(message, &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: markup,
 	})
 
 	if err != nil {
@@ -691,11 +961,12 @@ func handleViewBids(c telebot.Context, queries *db.Queries) error {
 	return c.Respond()
 }
 
-func handleBidText(c telebot.Context, queries *db.Queries, text string, userID int64, state BidState) error {
-	switch state {
+func handleBidText(c telebot.Context, queries *db.Queries, text string, userID int64, conv state.Conversation) error {
+	switch BidState(conv.Step) {
 	case BidStateEnterPrice:
 		// Проверяем, что все необходимые данные существуют
-		if bidData[userID] == nil {
+		lotID, err := bidConversationLotID(conv)
+		if err != nil {
 			errorMsg := "❌ Ошибка данных. Начните процесс подачи ставки заново."
 			msg, err := c.Bot().Send(c.Sender(), errorMsg)
 			if err == nil {
@@ -715,9 +986,20 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 			return err
 		}
 
-		// Безопасно получаем данные тендера с проверкой типов
-		currentPrice, ok := bidData[userID]["current_price"].(float64)
-		if !ok {
+		// Предыдущие ставки берём из БД: диалог мог быть восстановлен после перезапуска
+		previousBids, err := queries.GetUserBidsForLot(context.Background(), db.GetUserBidsForLotParams{
+			LotID:  lotID,
+			UserID: userID,
+		})
+		if err != nil {
+			fmt.Printf("Ошибка получения предыдущих ставок: %v\n", err)
+			previousBids = []db.TenderBid{}
+		}
+
+		// Шаг считаем от актуальной цены лота: за время ввода её могли снизить другие участники
+		lot, err := queries.GetTenderLot(context.Background(), lotID)
+		if err != nil {
+			fmt.Printf("Ошибка получения лота %d: %v\n", lotID, err)
 			errorMsg := "❌ Ошибка данных. Начните процесс подачи ставки заново."
 			msg, err := c.Bot().Send(c.Sender(), errorMsg)
 			if err == nil {
@@ -726,14 +1008,9 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 			return err
 		}
 
-		previousBids, ok := bidData[userID]["previous_bids"].([]db.TenderBid)
-		if !ok {
-			// Если предыдущих ставок нет, создаем пустой слайс
-			previousBids = []db.TenderBid{}
-		}
-
-		tenderTitle, ok := bidData[userID]["tender_title"].(string)
-		if !ok {
+		tender, err := queries.GetTenderById(context.Background(), lot.TenderID)
+		if err != nil {
+			fmt.Printf("Ошибка получения тендера %d: %v\n", lot.TenderID, err)
 			errorMsg := "❌ Ошибка данных. Начните процесс подачи ставки заново."
 			msg, err := c.Bot().Send(c.Sender(), errorMsg)
 			if err == nil {
@@ -742,18 +1019,15 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 			return err
 		}
 
-		// Вычисляем минимальную и максимальную ставки по вашей формуле
-		var minBid float64
-		if currentPrice-currentPrice*0.01 >= 0 {
-			minBid = currentPrice*0.01
-		} else {
-			minBid = 0
-		}
+		tenderTitle := conv.Get("tender_title")
+
+		// Проверяем ставку по тому же правилу шага, что и при сохранении
+		nextBid := db.NextAllowedBid(tender, lot)
 
-		if bidAmount > minBid {
+		if bidAmount <= 0 || bidAmount > nextBid {
 			errorMsg := fmt.Sprintf(
-				"❌ Ставка не может превышать %.2f руб. Введите другую сумму:",
-				minBid,
+				"❌ Ставка должна быть больше нуля и не выше %s руб. Введите другую сумму:",
+				formatPriceFloat(nextBid),
 			)
 			msg, err := c.Bot().Send(c.Sender(), errorMsg)
 			if err == nil {
@@ -775,8 +1049,9 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 		}
 
 		// Сохраняем ставку
-		bidData[userID]["bid_amount"] = bidAmount
-		bidStates[userID] = BidStateConfirm
+		conv.Put("bid_amount", strconv.FormatFloat(bidAmount, 'f', -1, 64))
+		conv.Step = int(BidStateConfirm)
+		saveConversation(userID, state.FlowBid, conv)
 
 		// Создаем клавиатуру подтверждения
 		markup := &telebot.ReplyMarkup{
@@ -788,22 +1063,28 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 			},
 		}
 		formattedBidAmount := formatPriceFloat(bidAmount)
-		formattedMinBid := formatPriceFloat(minBid)
+		formattedNextBid := formatPriceFloat(nextBid)
 
 		// Формируем сообщение с информацией о всех ставках
 		message := fmt.Sprintf(
 			"📊 *Подтверждение ставки*\n\n"+
 				"📋 Тендер: %s\n"+
+				"📦 %s\n"+
 				"💰 Новая ставка: *%s руб.*\n"+
-				"📊 *Минимальное понижение ставки на 1%% от текущей:* %s руб.",
+				"💰 Текущая цена: %s руб.\n"+
+				"📊 *Шаг понижения:* %s\n"+
+				"📉 *Ставка не выше:* %s руб.",
 			tenderTitle,
+			lotLabel(lot),
 			formattedBidAmount,
-			formattedMinBid,
+			formatPriceFloat(lot.CurrentPrice),
+			formatBidStep(lot.MinBidStepType, lot.MinBidDecrease),
+			formattedNextBid,
 		)
 
 		// Добавляем информацию о предыдущих ставках
 		if len(previousBids) > 0 {
-			message += "\n📈 *Все ваши ставки в этом тендере:*\n"
+			message += "\n📈 *Все ваши ставки по этому лоту:*\n"
 			for i, bid := range previousBids {
 				formattedBidAmount := formatPriceFloat(bid.Amount)
 				message += fmt.Sprintf("%d. %s руб. (%s)\n",
@@ -816,6 +1097,20 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 
 		message += "\nПодтверждаете новую ставку?"
 
+		// Предложение закрытого тендера подаётся вслепую и один раз
+		if tender.Type == db.TenderTypeSealed {
+			message = fmt.Sprintf(
+				"📊 *Подтверждение предложения*\n\n"+
+					"📋 Тендер: %s\n"+
+					"📦 %s\n"+
+					"💰 Ваше предложение: *%s руб.*\n\n"+
+					"⚠️ Изменить предложение после подтверждения будет нельзя. Подтверждаете?",
+				tenderTitle,
+				lotLabel(lot),
+				formattedBidAmount,
+			)
+		}
+
 		msg, err := c.Bot().Send(c.Sender(), message, &telebot.SendOptions{
 			ParseMode:   telebot.ModeMarkdown,
 			ReplyMarkup: markup,
@@ -835,208 +1130,625 @@ func handleBidText(c telebot.Context, queries *db.Queries, text string, userID i
 		// СОХРАНЯЕМ ID СООБЩЕНИЯ
 		MessageManagerOperator.AddMessage(userID, msg.ID)
 
-		return nil
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+func handleConfirmBid(c telebot.Context, queries *db.Queries) error {
+	userID := c.Sender().ID
+
+	conv, exists := loadConversation(userID, state.FlowBid)
+	if !exists || BidState(conv.Step) != BidStateConfirm {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Данные ставки не найдены",
+			ShowAlert: true,
+		})
+	}
+
+	tenderID, err := bidConversationTenderID(conv)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Данные ставки не найдены",
+			ShowAlert: true,
+		})
+	}
+	lotID, err := bidConversationLotID(conv)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Данные ставки не найдены",
+			ShowAlert: true,
+		})
+	}
+	bidAmount, err := strconv.ParseFloat(conv.Get("bid_amount"), 64)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Данные ставки не найдены",
+			ShowAlert: true,
+		})
+	}
+	tenderTitle := conv.Get("tender_title")
+
+	ctx := context.Background()
+
+	// Сохраняем ставку атомарно: тендер и лот блокируются, цена и шаг проверяются в БД
+	bidTime := time.Now()
+	closesAt := bidTime.Add(tenderInactivityTimeout)
+	result, err := queries.PlaceBid(ctx, db.PlaceBidParams{
+		TenderID: tenderID,
+		LotID:    lotID,
+		UserID:   userID,
+		Amount:   bidAmount,
+		BidTime:  bidTime,
+		ClosesAt: closesAt,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка сохранения ставки: %v\n", err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка сохранения ставки",
+			ShowAlert: true,
+		})
+	}
+
+	if result.Rejection != db.BidAccepted {
+		// Цена могла измениться - даем пользователю ввести новую сумму
+		if result.Rejection == db.BidRejectedStepViolation {
+			conv.Step = int(BidStateEnterPrice)
+			saveConversation(userID, state.FlowBid, conv)
+		} else {
+			clearConversation(userID, state.FlowBid)
+		}
+
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      bidRejectionMessage(result),
+			ShowAlert: true,
+		})
+	}
+
+	fmt.Printf("✅ Ставка успешно сохранена в базу: тендер %d, лот %d, пользователь %d, сумма %.2f\n",
+		tenderID, lotID, userID, bidAmount)
+
+	// Закрытое предложение никому не показываем и срок лота не сдвигаем
+	if result.Tender.Type == db.TenderTypeSealed {
+		return confirmSealedBid(c, result.Tender, result.Lot, bidAmount)
+	}
+
+	// Получаем все ставки пользователя по этому лоту для отображения
+	allBids, err := queries.GetUserBidsForLot(ctx, db.GetUserBidsForLotParams{
+		LotID:  lotID,
+		UserID: userID,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка получения списка ставок: %v\n", err)
+	}
+
+	// Актуальная информация о лоте (с обновленной ценой)
+	updatedLot := result.Lot
+
+	formattedBidAmount := formatPriceFloat(bidAmount)
+	formattedCurrentPrice := formatPriceFloat(updatedLot.CurrentPrice)
+
+	// Формируем сообщение для пользователя, который сделал ставку
+	message := fmt.Sprintf(
+		"✅ *Новая ставка успешно подана!*\n\n"+
+			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
+			"💰 Новая ставка: *%s руб.*\n"+
+			"💰 *Новая текущая цена лота:* %s руб.\n",
+		tenderTitle,
+		lotLabel(updatedLot),
+		formattedBidAmount,
+		formattedCurrentPrice,
+	)
+
+	if result.EndAtExtended {
+		message += fmt.Sprintf("⏱ *Ставка продлила торги до:* %s\n", result.Tender.EndAt.Time.Format("02.01.2006 15:04"))
+	}
+
+	if len(allBids) > 0 {
+		message += "\n📊 *Все ваши ставки по этому лоту:*\n"
+		for i, bid := range allBids {
+			indicator := ""
+			if bid.Amount == bidAmount {
+				indicator = " 🆕"
+			}
+			formattedAmount := formatPriceFloat(bid.Amount)
+			message += fmt.Sprintf("%d. %s руб. (%s)%s\n",
+				i+1,
+				formattedAmount,
+				bid.BidTime.Time.Format("02.01.2006 15:04"),
+				indicator)
+		}
+	}
+
+	message += "\nВы можете сделать еще одну ставку, нажав кнопку ниже:"
+
+	// Создаем кнопку для новой ставки
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{
+				{Unique: "select_lot", Text: "💵 Сделать еще одну ставку", Data: strconv.Itoa(int(lotID))},
+			},
+		},
+	}
+
+	// Обновляем сообщение для пользователя, который сделал ставку
+	_, err = c.Bot().Edit(c.Message(), message, &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: markup,
+	})
+
+	if err != nil {
+		fmt.Printf("Ошибка обновления сообщения: %v\n", err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text: "✅ Ставка успешно подана!",
+		})
+	}
+
+	// ЗАПУСКАЕМ ТАЙМЕР ДО СРОКА ЗАВЕРШЕНИЯ ТОРГОВ ПО ЛОТУ
+	go startOrRestartTimer(c.Bot(), queries, lotID, closesAt)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+
+		// Отправляем сообщение с текстом и клавиатурой
+		keyboardMsg, err := c.Bot().Send(c.Sender(), "⌨️ Используйте меню ниже для дальнейших действий", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuSupplierRegistered,
+		})
+		if err == nil {
+			MessageManagerOperator.AddMessage(userID, keyboardMsg.ID)
+		}
+	}()
+
+	// РАССЫЛАЕМ УВЕДОМЛЕНИЯ ДРУГИМ УЧАСТНИКАМ ТЕНДЕРА
+	go sendBidNotificationToOtherParticipants(c.Bot(), queries, result.Tender, updatedLot, userID, bidAmount)
+
+	// Очищаем состояние
+	clearConversation(userID, state.FlowBid)
+
+	MessageManagerOperator.CleanupOldMessages(c.Bot(), userID, 2)
+
+	return c.Respond()
+}
+
+// confirmSealedBid сообщает участнику, что его предложение по лоту закрытого тендера принято
+func confirmSealedBid(c telebot.Context, tender db.Tender, lot db.TenderLot, bidAmount float64) error {
+	userID := c.Sender().ID
+	clearConversation(userID, state.FlowBid)
+
+	message := fmt.Sprintf(
+		"✅ *Предложение принято!*\n\n"+
+			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
+			"💰 Ваше предложение: *%s руб.*\n\n"+
+			"🔒 Предложения остальных участников скрыты. Итоги будут объявлены после %s.",
+		tender.Title,
+		lotLabel(lot),
+		formatPriceFloat(bidAmount),
+		tender.EndAt.Time.Format("02.01.2006 15:04"),
+	)
+
+	_, err := c.Bot().Edit(c.Message(), message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка обновления сообщения: %v\n", err)
+	}
+
+	keyboardMsg, err := c.Bot().Send(c.Sender(), "⌨️ Используйте меню ниже для дальнейших действий", &telebot.SendOptions{
+		ReplyMarkup: menu.MenuSupplierRegistered,
+	})
+	if err == nil {
+		MessageManagerOperator.AddMessage(userID, keyboardMsg.ID)
+	}
+
+	return c.Respond(&telebot.CallbackResponse{
+		Text: "✅ Предложение принято",
+	})
+}
+
+// newBidConversation возвращает начальное состояние диалога подачи ставки по лоту тендера
+func newBidConversation(tender db.Tender, lot db.TenderLot) state.Conversation {
+	conv := state.Conversation{Step: int(BidStateEnterPrice)}
+	conv.Put("tender_id", strconv.Itoa(int(tender.ID)))
+	conv.Put("tender_title", tender.Title)
+	conv.Put("lot_id", strconv.Itoa(int(lot.ID)))
+	conv.Put("lot_title", lot.Title)
+	conv.Put("start_price", strconv.FormatFloat(lot.StartPrice, 'f', -1, 64))
+	conv.Put("participants_count", strconv.Itoa(int(tender.ParticipantsCount)))
+	return conv
+}
+
+// bidConversationTenderID достаёт из диалога ставки ID тендера
+func bidConversationTenderID(conv state.Conversation) (int32, error) {
+	tenderID, err := strconv.ParseInt(conv.Get("tender_id"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(tenderID), nil
+}
+
+// bidConversationLotID достаёт из диалога ставки ID лота
+func bidConversationLotID(conv state.Conversation) (int32, error) {
+	lotID, err := strconv.ParseInt(conv.Get("lot_id"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(lotID), nil
+}
+
+// bidRejectionMessage возвращает текст для пользователя по причине отказа в ставке
+func bidRejectionMessage(result db.PlaceBidResult) string {
+	switch result.Rejection {
+	case db.BidRejectedTenderNotFound:
+		return "❌ Тендер не найден"
+	case db.BidRejectedTenderNotActive:
+		return "❌ Тендер не активен. Подача ставок невозможна."
+	case db.BidRejectedNotParticipant:
+		return "❌ Вы не участвуете в этом тендере"
+	case db.BidRejectedInvalidAmount:
+		return "❌ Сумма ставки должна быть больше нуля"
+	case db.BidRejectedAlreadyBid:
+		return "❌ Вы уже подали предложение по этому лоту"
+	case db.BidRejectedLotClosed:
+		return "❌ Торги по этому лоту уже завершены"
+	case db.BidRejectedStepViolation:
+		if result.Tender.Type == db.TenderTypeSealed {
+			return fmt.Sprintf(
+				"❌ Предложение не должно превышать стартовую цену %s руб. Введите другую сумму.",
+				formatPriceFloat(result.NextAllowedBid),
+			)
+		}
+		return fmt.Sprintf(
+			"❌ Ставка не принята: текущая цена лота %s руб. Максимально допустимая ставка сейчас — %s руб. Введите другую сумму.",
+			formatPriceFloat(result.Lot.CurrentPrice),
+			formatPriceFloat(result.NextAllowedBid),
+		)
+	default:
+		return "❌ Ставка не принята"
+	}
+}
+
+func startOrRestartTimer(bot *telebot.Bot, queries *db.Queries, lotID int32, closesAt time.Time) {
+	lotTimers.Lock()
+	defer lotTimers.Unlock()
+
+	// Если уже есть активный таймер для этого лота - останавливаем его
+	if oldTimer, exists := lotTimers.timers[lotID]; exists {
+		oldTimer.Stop()
+		fmt.Printf("Таймер для лота %d перезапущен\n", lotID)
+	}
+
+	// Создаем новый таймер до сохраненного срока завершения
+	timer := time.AfterFunc(time.Until(closesAt), func() {
+		// Удаляем таймер из мапы перед завершением
+		lotTimers.Lock()
+		delete(lotTimers.timers, lotID)
+		lotTimers.Unlock()
+
+		closeLot(bot, queries, lotID)
+	})
+
+	// Сохраняем новый таймер
+	lotTimers.timers[lotID] = timer
+	fmt.Printf("Таймер для лота %d запущен до %s\n", lotID, closesAt.Format("02.01.2006 15:04:05"))
+}
+
+// stopLotTimer останавливает таймер завершения торгов по лоту, если он запущен
+func stopLotTimer(lotID int32) {
+	lotTimers.Lock()
+	defer lotTimers.Unlock()
+
+	if timer, exists := lotTimers.timers[lotID]; exists {
+		timer.Stop()
+		delete(lotTimers.timers, lotID)
+		fmt.Printf("Таймер для лота %d остановлен\n", lotID)
+	}
+}
+
+// TenderScheduler запускает таймеры завершения торгов по лотам по запросу фоновых задач
+type TenderScheduler struct {
+	bot     *telebot.Bot
+	queries *db.Queries
+}
+
+func NewTenderScheduler(bot *telebot.Bot, pool *pgxpool.Pool) *TenderScheduler {
+	return &TenderScheduler{bot: bot, queries: db.New(pool)}
+}
+
+// ScheduleLotClose запускает таймер до срока завершения торгов по лоту
+func (s *TenderScheduler) ScheduleLotClose(lotID int32, closesAt time.Time) {
+	startOrRestartTimer(s.bot, s.queries, lotID, closesAt)
+}
+
+// CloseExpiredTender завершает торги по всем открытым лотам тендера, срок end_at
+// которого наступил, в том числе по лотам без ставок
+func (s *TenderScheduler) CloseExpiredTender(tenderID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lots, err := s.queries.GetOpenTenderLots(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения открытых лотов тендера %d: %v\n", tenderID, err)
+		return
+	}
+
+	for _, lot := range lots {
+		closeLot(s.bot, s.queries, lot.ID)
+	}
+
+	// Лоты могли закрыться раньше, а статус тендера — остаться прежним
+	if len(lots) == 0 {
+		finishTenderIfAllLotsClosed(s.bot, s.queries, tenderID)
+	}
+}
+
+// closeLot завершает торги по лоту по сохраненному сроку: определяет лучшую ставку
+// и предлагает участнику подтвердить победу. Все данные берутся из БД, поэтому
+// функцию можно вызывать и из таймера, и при восстановлении после перезапуска.
+func closeLot(bot *telebot.Bot, queries *db.Queries, lotID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lot, err := queries.GetTenderLot(ctx, lotID)
+	if err != nil {
+		fmt.Printf("Ошибка получения лота %d при завершении: %v\n", lotID, err)
+		return
+	}
+
+	if lot.Status != db.LotStatusOpen {
+		return
+	}
+
+	tender, err := queries.GetTender(ctx, lot.TenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения тендера %d при завершении лота %d: %v\n", lot.TenderID, lotID, err)
+		return
+	}
+
+	if tender.Status != "active" {
+		return
+	}
+
+	// После end_at торги завершаются независимо от времени последней ставки
+	endReached := tender.EndAt.Valid && !tender.EndAt.Time.After(time.Now())
+	if !endReached {
+		// Без ставок у лота открытого тендера срока нет
+		if !lot.ClosesAt.Valid {
+			return
+		}
+		// Срок мог сдвинуться новой ставкой - тогда просто перевзводим таймер
+		if lot.ClosesAt.Time.After(time.Now()) {
+			startOrRestartTimer(bot, queries, lotID, lot.ClosesAt.Time)
+			return
+		}
+	}
+
+	bestBid, err := queries.GetLowestLotBid(ctx, lotID)
+	noBids := errors.Is(err, pgx.ErrNoRows)
+	if err != nil && !noBids {
+		fmt.Printf("Ошибка получения лучшей ставки для лота %d: %v\n", lotID, err)
+		return
+	}
 
-	default:
-		return nil
+	// Лот без ставок считается несостоявшимся, по остальным ждём подтверждения победителя
+	status := db.LotStatusConfirming
+	if noBids {
+		status = db.LotStatusFailed
 	}
-}
 
-func handleConfirmBid(c telebot.Context, queries *db.Queries) error {
-	userID := c.Sender().ID
+	// Лот завершает только один вызов: таймер, фоновая задача и восстановление после перезапуска могут сработать одновременно
+	completed, err := queries.CloseLot(ctx, db.CloseLotParams{
+		ID:     lotID,
+		Status: status,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка обновления статуса лота %d: %v\n", lotID, err)
+		return
+	}
+	if completed == 0 {
+		return
+	}
 
-	if _, exists := bidData[userID]; !exists {
-		return c.Respond(&telebot.CallbackResponse{
-			Text:      "❌ Данные ставки не найдены",
-			ShowAlert: true,
-		})
+	// Срок закрытого тендера наступает и без ставок
+	if noBids {
+		closeLotWithoutBids(bot, queries, tender, lot)
+		finishTenderIfAllLotsClosed(bot, queries, tender.ID)
+		return
 	}
 
-	tenderID := bidData[userID]["tender_id"].(int32)
-	bidAmount := bidData[userID]["bid_amount"].(float64)
-	tenderTitle := bidData[userID]["tender_title"].(string)
-	startPrice := bidData[userID]["start_price"].(float64)
+	notifyLotAwaitingConfirmation(bot, queries, tender, lot, bestBid.UserID)
+	offerLotToNextBidder(bot, queries, tender, lot)
+}
 
-	ctx := context.Background()
+// notifyLotAwaitingConfirmation сообщает участникам, что торги по лоту окончены и
+// победитель будет объявлен после подтверждения
+func notifyLotAwaitingConfirmation(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot, bestBidderID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// ПРОСТАЯ ПРОВЕРКА: есть ли уже такая ставка в тендере
-	existingBidsCount, err := queries.CheckBidExists(ctx, db.CheckBidExistsParams{
-		TenderID: tenderID,
-		Amount:   bidAmount,
-	})
+	participants, err := queries.GetParticipantsForTender(ctx, tender.ID)
 	if err != nil {
-		fmt.Printf("Ошибка проверки ставки: %v\n", err)
-		return c.Respond(&telebot.CallbackResponse{
-			Text:      "❌ Ошибка проверки ставки",
-			ShowAlert: true,
-		})
+		fmt.Printf("Ошибка получения участников тендера %d: %v\n", tender.ID, err)
+		return
 	}
 
-	if existingBidsCount > 0 {
-		// Такая ставка уже есть
-		delete(bidStates, userID)
-		delete(bidData, userID)
-		
-		return c.Respond(&telebot.CallbackResponse{
-			Text:      fmt.Sprintf("❌ К сожалению, ставка на сумму %.2f руб. уже была принята от другого участника. Пожалуйста, введите другую сумму.", bidAmount),
-			ShowAlert: true,
+	message := fmt.Sprintf("🏁 *Торги по лоту завершены*\n\n📋 Тендер: %s\n📦 %s\n⏳ Победитель будет объявлен после подтверждения участия", tender.Title, lotLabel(lot))
+	for _, participantID := range participants {
+		// Лучший участник получает отдельное предложение подтвердить победу
+		if participantID == bestBidderID {
+			continue
+		}
+		msg, err := bot.Send(&telebot.User{ID: participantID}, message, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
 		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %d: %v\n", participantID, err)
+			continue
+		}
+		MessageManagerOperator.AddMessage(participantID, msg.ID)
 	}
+}
 
-	// Сохраняем ставку в базу данных
-	err = queries.CreateBid(ctx, db.CreateBidParams{
-		TenderID: tenderID,
-		UserID:   userID,
-		Amount:   bidAmount,
-		BidTime:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
-	})
-	
+// finishTenderIfAllLotsClosed завершает тендер, когда торги и подтверждение победителей
+// закончились по всем его лотам. Если ни по одному лоту победитель не определён,
+// тендер считается несостоявшимся (failed).
+func finishTenderIfAllLotsClosed(bot *telebot.Bot, queries *db.Queries, tenderID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	unfinishedLots, err := queries.CountUnfinishedLots(ctx, tenderID)
 	if err != nil {
-		fmt.Printf("Ошибка сохранения ставки: %v\n", err)
-		return c.Respond(&telebot.CallbackResponse{
-			Text:      "❌ Ошибка сохранения ставки",
-			ShowAlert: true,
-		})
+		fmt.Printf("Ошибка подсчета незавершенных лотов тендера %d: %v\n", tenderID, err)
+		return
+	}
+	if unfinishedLots > 0 {
+		return
 	}
 
-	fmt.Printf("✅ Ставка успешно сохранена в базу: тендер %d, пользователь %d, сумма %.2f\n", 
-		tenderID, userID, bidAmount)
-
-	// ОБНОВЛЯЕМ ТЕКУЩУЮ ЦЕНУ ТЕНДЕРА
-	err = queries.UpdateTenderCurrentPrice(ctx, db.UpdateTenderCurrentPriceParams{
-		ID:           tenderID,
-		CurrentPrice: bidAmount,
-	})
+	wonLots, err := queries.CountCompletedLots(ctx, tenderID)
 	if err != nil {
-		fmt.Printf("Ошибка обновления текущей цены тендера: %v\n", err)
+		fmt.Printf("Ошибка подсчета завершенных лотов тендера %d: %v\n", tenderID, err)
+		return
+	}
+	status, reason := tender.StatusCompleted, "торги по всем лотам завершены"
+	if wonLots == 0 {
+		status, reason = tender.StatusFailed, "ни по одному лоту не определён победитель"
 	}
 
-	// Получаем все ставки пользователя в этом тендере для отображения
-	allBids, err := queries.GetUserBidsForTender(ctx, db.GetUserBidsForTenderParams{
-		TenderID: tenderID,
-		UserID:   userID,
-	})
+	// Тендер завершает только один вызов: остальные получат ErrStatusChanged
+	err = tender.Transition(ctx, queries, tenderID, tender.StatusActive, status, tender.SystemActor, reason)
+	if errors.Is(err, tender.ErrStatusChanged) {
+		return
+	}
 	if err != nil {
-		fmt.Printf("Ошибка получения списка ставок: %v\n", err)
+		fmt.Printf("Ошибка обновления статуса тендера %d: %v\n", tenderID, err)
+		return
 	}
 
-	// Получаем актуальную информацию о тендере (с обновленной ценой)
-	updatedTender, err := queries.GetTender(ctx, tenderID)
+	// Участники остаются в архиве с итоговым статусом: победил, проиграл или не делал ставок
+	err = queries.FinishParticipants(ctx, tenderID)
 	if err != nil {
-		fmt.Printf("Ошибка получения обновленной информации о тендере: %v\n", err)
-		// Если не удалось получить обновленный тендер, используем bidAmount как текущую цену
-		updatedTender.CurrentPrice = bidAmount
+		fmt.Printf("Ошибка обновления статусов участников тендера %d: %v\n", tenderID, err)
 	}
 
-	formattedBidAmount := formatPriceFloat(bidAmount)
-	formattedCurrentPrice := formatPriceFloat(updatedTender.CurrentPrice)
+	fmt.Printf("Тендер %d завершен со статусом %s: торги по всем лотам окончены\n", tenderID, status)
+
+	if status == tender.StatusFailed {
+		notifyTenderFailed(bot, queries, tenderID)
+	}
+}
+
+// notifyTenderFailed сообщает организатору, что тендер не состоялся, и предлагает
+// перезапустить его с новой датой начала
+func notifyTenderFailed(bot *telebot.Bot, queries *db.Queries, tenderID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tender, err := queries.GetTender(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения тендера %d: %v\n", tenderID, err)
+		return
+	}
 
-	// Формируем сообщение для пользователя, который сделал ставку
 	message := fmt.Sprintf(
-		"✅ *Новая ставка успешно подана!*\n\n"+
+		"⚫ *Тендер не состоялся*\n\n"+
 			"📋 Тендер: %s\n"+
-			"💰 Новая ставка: *%s руб.*\n"+
-			"💰 *Новая текущая цена тендера:* %s руб.\n",
-		tenderTitle,
-		formattedBidAmount,
-		formattedCurrentPrice,
+			"💰 Стартовая цена: %s руб.\n"+
+			"📭 Ни по одному лоту не определён победитель.\n\n"+
+			"🔁 Тендер можно перезапустить с новой датой начала и, при желании, более высокой стартовой ценой.",
+		tender.Title,
+		formatPriceFloat(tender.StartPrice),
 	)
 
-	if len(allBids) > 0 {
-		message += "\n📊 *Все ваши ставки в этом тендере:*\n"
-		for i, bid := range allBids {
-			indicator := ""
-			if bid.Amount == bidAmount {
-				indicator = " 🆕"
-			}
-			formattedAmount := formatPriceFloat(bid.Amount)
-			message += fmt.Sprintf("%d. %s руб. (%s)%s\n",
-				i+1,
-				formattedAmount,
-				bid.BidTime.Time.Format("02.01.2006 15:04"),
-				indicator)
+	for _, organizer := range tenderOrganizerIDs(queries, tender.OrganizerID) {
+		_, err := bot.Send(&telebot.User{ID: organizer}, message, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
+			ReplyMarkup: &telebot.ReplyMarkup{
+				InlineKeyboard: [][]telebot.InlineButton{
+					{
+						{Unique: "relaunch_tender", Text: "🔁 Перезапустить тендер", Data: strconv.Itoa(int(tender.ID))},
+					},
+				},
+			},
+		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления организатору %d: %v\n", organizer, err)
 		}
 	}
+}
 
-	message += "\nВы можете сделать еще одну ставку, нажав кнопку ниже:"
+// closeLotWithoutBids сообщает о завершении торгов по лоту, на который не поступило ни одного предложения
+func closeLotWithoutBids(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Создаем кнопку для новой ставки
-	markup := &telebot.ReplyMarkup{
-		InlineKeyboard: [][]telebot.InlineButton{
-			{
-				{Unique: "make_bid", Text: "💵 Сделать еще одну ставку", Data: fmt.Sprintf("%d|%d", tenderID, userID)},
-			},
-		},
+	participants, err := queries.GetParticipantsForTender(ctx, tender.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения участников тендера %d: %v\n", tender.ID, err)
 	}
 
-	// Обновляем сообщение для пользователя, который сделал ставку
-	_, err = c.Bot().Edit(c.Message(), message, &telebot.SendOptions{
-		ParseMode:   telebot.ModeMarkdown,
-		ReplyMarkup: markup,
+	// Несостоявшиеся торги тоже попадают в архив
+	err = queries.AddToHistory(ctx, db.AddToHistoryParams{
+		TenderID:   tender.ID,
+		Title:      tender.Title + " — " + lotLabel(lot),
+		StartPrice: lot.StartPrice,
+		LotID:      lot.ID,
+		Outcome:    db.LotStatusFailed,
 	})
-
 	if err != nil {
-		fmt.Printf("Ошибка обновления сообщения: %v\n", err)
-		return c.Respond(&telebot.CallbackResponse{
-			Text: "✅ Ставка успешно подана!",
-		})
+		fmt.Printf("Ошибка сохранения лота %d в историю: %v\n", lot.ID, err)
 	}
 
-	// ЗАПУСКАЕМ ТАЙМЕР НА 30 МИНУТ ДЛЯ ПРОВЕРКИ ПОБЕДИТЕЛЯ
-	go startOrRestartTimer(c.Bot(), queries, tenderID, userID, bidAmount, tenderTitle, startPrice)
-
-	go func() {
-		time.Sleep(300 * time.Millisecond)
+	message := fmt.Sprintf("🏁 *Торги по лоту завершены*\n\n📋 Тендер: %s\n📦 %s\n📭 Предложений не поступило", tender.Title, lotLabel(lot))
 
-		// Отправляем сообщение с текстом и клавиатурой
-		keyboardMsg, err := c.Bot().Send(c.Sender(), "⌨️ Используйте меню ниже для дальнейших действий", &telebot.SendOptions{
-			ReplyMarkup: menu.MenuSupplierRegistered,
+	recipients := append(tenderOrganizerIDs(queries, tender.OrganizerID), participants...)
+	for _, userID := range recipients {
+		_, err := bot.Send(&telebot.User{ID: userID}, message, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
 		})
-		if err == nil {
-			MessageManagerOperator.AddMessage(userID, keyboardMsg.ID)
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %d: %v\n", userID, err)
 		}
-	}()
-
-	// РАССЫЛАЕМ УВЕДОМЛЕНИЯ ДРУГИМ УЧАСТНИКАМ ТЕНДЕРА
-	go sendBidNotificationToOtherParticipants(c.Bot(), queries, tenderID, userID, tenderTitle, bidAmount, updatedTender.CurrentPrice)
-
-	// Очищаем состояние
-	delete(bidStates, userID)
-	delete(bidData, userID)
-
-	MessageManagerOperator.CleanupOldMessages(c.Bot(), userID, 2)
+	}
 
-	return c.Respond()
+	fmt.Printf("Лот %d тендера %d завершен без предложений\n", lot.ID, tender.ID)
 }
 
-func startOrRestartTimer(bot *telebot.Bot, queries *db.Queries, tenderID int32, lastBidUserID int64, lastBidAmount float64, tenderTitle string, start_price float64) {
-	tenderTimers.Lock()
-	defer tenderTimers.Unlock()
+// RestoreTenderTimers восстанавливает таймеры открытых лотов активных тендеров после
+// запуска бота. Лоты, срок которых уже истек, завершаются сразу.
+func RestoreTenderTimers(bot *telebot.Bot, pool *pgxpool.Pool) {
+	queries := db.New(pool)
 
-	// Если уже есть активный таймер для этого тендера - останавливаем его
-	if oldTimer, exists := tenderTimers.timers[tenderID]; exists {
-		oldTimer.Stop()
-		fmt.Printf("Таймер для тендера %d перезапущен\n", tenderID)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lots, err := queries.GetOpenLotsWithDeadline(ctx)
+	if err != nil {
+		fmt.Printf("Ошибка получения открытых лотов для восстановления таймеров: %v\n", err)
+		return
 	}
 
-	// Создаем новый таймер
-	timer := time.AfterFunc(5*time.Minute, func() {
-		declareWinner(bot, queries, tenderID, lastBidUserID, lastBidAmount, tenderTitle, start_price)
+	for _, lot := range lots {
+		if lot.ClosesAt.Time.After(time.Now()) {
+			startOrRestartTimer(bot, queries, lot.ID, lot.ClosesAt.Time)
+			continue
+		}
 
-		// Удаляем таймер из мапы после выполнения
-		tenderTimers.Lock()
-		delete(tenderTimers.timers, tenderID)
-		tenderTimers.Unlock()
-	})
+		fmt.Printf("Срок лота %d истек во время простоя, завершаем\n", lot.ID)
+		closeLot(bot, queries, lot.ID)
+	}
 
-	// Сохраняем новый таймер
-	tenderTimers.timers[tenderID] = timer
-	fmt.Printf("Таймер для тендера %d запущен на 5 минуты\n", tenderID)
+	fmt.Printf("Восстановлено таймеров лотов: %d\n", len(lots))
 }
 
-// declareWinner объявляет победителя
-func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winnerUserID int64, winnerAmount float64, tenderTitle string, start_price float64) {
+// declareWinner объявляет победителя торгов по лоту
+func declareWinner(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot, winnerUserID int64, winnerAmount float64) {
 	ctx := context.Background()
+	tenderID := tender.ID
+	tenderTitle := tender.Title
 
 	// Получаем информацию о победителе
 	winner, err := queries.GetUserByTelegramID(ctx, winnerUserID)
@@ -1057,12 +1769,14 @@ func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winner
 
 	// Сообщение о победе
 	winnerMessage := fmt.Sprintf(
-		"🏆 *Тендер завершен!*\n\n"+
+		"🏆 *Торги по лоту завершены!*\n\n"+
 			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
 			"👑 Победитель: %s\n"+
 			"💰 Выигрышная ставка: %s руб.\n\n"+
 			"🎉 Поздравляем победителя!",
 		tenderTitle,
+		lotLabel(lot),
 		winner.OrganizationName.String,
 		formattedAmount,
 	)
@@ -1070,21 +1784,32 @@ func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winner
 	youWinMessage := fmt.Sprintf(
 		"🎯 *ВЫ ПОБЕДИТЕЛЬ!* 🎯\n\n"+
 			"📋 *Тендер:* %s\n"+
+			"📦 *%s*\n"+
 			"💎 *Ваша ставка:* %s руб.\n\n"+
-			"✨ Поздравляем с победой! Ваша ставка оказалась лучшей.\n"+
+			"✨ Поздравляем с победой! Вы подтвердили готовность заключить договор.\n"+
 			"📩 Ожидайте связи от организатора для оформления документов.",
 		tenderTitle,
+		lotLabel(lot),
 		formattedAmount,
 	)
 
-	bidsHistory, err := queries.GetBidsHistoryByTenderID(ctx, tenderID)
+	bidsHistory, err := queries.GetBidsHistoryByLotID(ctx, lot.ID)
 	if err != nil {
-		fmt.Printf("Ошибка получения истории ставок для тендера %d: %v\n", tenderID, err)
+		fmt.Printf("Ошибка получения истории ставок для лота %d: %v\n", lot.ID, err)
+	}
+
+	bidsHistoryTitle := "📊 *История ставок:*"
+	// Закрытые предложения вскрываются организатору по возрастанию суммы
+	if tender.Type == db.TenderTypeSealed {
+		bidsHistoryTitle = "📬 *Вскрытые предложения:*"
+		sort.SliceStable(bidsHistory, func(i, j int) bool {
+			return bidsHistory[i].Amount < bidsHistory[j].Amount
+		})
 	}
 
 	var bidsHistoryText string
 	if len(bidsHistory) > 0 {
-		bidsHistoryText = "\n\n📊 *История ставок:*\n"
+		bidsHistoryText = "\n\n" + bidsHistoryTitle + "\n"
 		for i, bid := range bidsHistory {
 			// Форматируем время
 			bidTime := bid.BidTime.Time.Format("02.01.2006 15:04")
@@ -1098,12 +1823,13 @@ func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winner
 				bidTime)
 		}
 	} else {
-		bidsHistoryText = "\n\n📊 *История ставок:*\nСтавки отсутствуют"
+		bidsHistoryText = "\n\n" + bidsHistoryTitle + "\nСтавки отсутствуют"
 	}
 
 	organizerMessage := fmt.Sprintf(
-		"🏆 *Тендер завершен!*\n\n"+
+		"🏆 *Торги по лоту завершены!*\n\n"+
 			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
 			"👑 Победитель: %s\n"+
 			"📞 Контакты победителя:\n"+
 			"   • Телефон: %s\n"+
@@ -1113,6 +1839,7 @@ func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winner
 			"%s\n\n"+
 			"📞 Свяжитесь с победителем для оформления договора",
 		tenderTitle,
+		lotLabel(lot),
 		winner.OrganizationName.String,
 		winner.PhoneNumber.String,
 		winner.Inn.String,
@@ -1123,36 +1850,39 @@ func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winner
 
 	err = queries.AddToHistory(ctx, db.AddToHistoryParams{
 		TenderID:    tenderID,
-		Title:       tenderTitle,
+		Title:       tenderTitle + " — " + lotLabel(lot),
 		Winner:      winner.OrganizationName,
 		PhoneNumber: winner.PhoneNumber,
 		Inn:         winner.Inn,
 		Fio:         winner.Name,
-		Bid:         winnerAmount,
-		StartPrice:  start_price,
+		Bid:         pgtype.Float8{Float64: winnerAmount, Valid: true},
+		StartPrice:  lot.StartPrice,
+		LotID:       lot.ID,
+		Outcome:     db.LotStatusCompleted,
 	})
 	if err != nil {
 		fmt.Printf("Ошибка сохранения сообщения в историю")
 	}
 
-	// Отправляем сообщение организатору
-	for _, organizer := range config.OrganizerIDs {
+	// Контакты победителя получает только организатор тендера
+	for _, organizer := range tenderOrganizerIDs(queries, tender.OrganizerID) {
 		_, err = bot.Send(&telebot.User{ID: organizer}, organizerMessage, &telebot.SendOptions{
 			ParseMode: telebot.ModeMarkdown,
 		})
 		if err != nil {
 			fmt.Printf("Ошибка отправки уведомления организатору %d: %v\n", organizer, err)
 		}
+	}
 
-		for _, adminID := range config.AdminIDs {
-			_, err = bot.Send(&telebot.User{ID: adminID}, organizerMessage, &telebot.SendOptions{
-				ParseMode: telebot.ModeMarkdown,
-			})
-			if err != nil {
-				fmt.Printf("Ошибка отправки уведомления админу %d: %v\n", organizer, err)
-			}
-			time.Sleep(100 * time.Millisecond)
+	// Админы видят результаты всех тендеров
+	for _, adminID := range usersWithRole(queries, "admin") {
+		_, err = bot.Send(&telebot.User{ID: adminID}, organizerMessage, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
+		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления админу %d: %v\n", adminID, err)
 		}
+		time.Sleep(100 * time.Millisecond)
 	}
 
 	// Рассылаем уведомление всем участникам
@@ -1179,28 +1909,16 @@ func declareWinner(bot *telebot.Bot, queries *db.Queries, tenderID int32, winner
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	// Обновляем статус тендера на завершенный
-	err = queries.UpdateTenderStatus(ctx, db.UpdateTenderStatusParams{
-		ID:     tenderID,
-		Status: "completed",
-	})
-	if err != nil {
-		fmt.Printf("Ошибка обновления статуса тендера %d: %v\n", tenderID, err)
-	}
-
-	err = queries.RemoveParticipants(ctx, tenderID)
-	if err != nil {
-		fmt.Printf("Ошибка удаления участников из тендера")
-	}
-
-	fmt.Printf("Тендер %d завершен. Победитель: %s (%d)\n", tenderID, winner.OrganizationName.String, winnerUserID)
+	fmt.Printf("Лот %d тендера %d завершен. Победитель: %s (%d)\n", lot.ID, tenderID, winner.OrganizationName.String, winnerUserID)
 }
 
 // Функция для рассылки уведомлений другим участникам
 // Функция для рассылки уведомлений другим участникам
-func sendBidNotificationToOtherParticipants(bot *telebot.Bot, queries *db.Queries, tenderID int32, bidderUserID int64, tenderTitle string, bidAmount float64, currentPrice float64) {
+func sendBidNotificationToOtherParticipants(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot, bidderUserID int64, bidAmount float64) {
     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
     defer cancel()
+    tenderID := tender.ID
+    tenderTitle := tender.Title
 
     // Получаем номер участника, который сделал ставку
     participantNumber, err := queries.GetParticipantNumber(ctx, db.GetParticipantNumberParams{
@@ -1221,22 +1939,29 @@ func sendBidNotificationToOtherParticipants(bot *telebot.Bot, queries *db.Querie
 
     // Форматируем цены для красивого отображения
     formattedBidAmount := formatPriceFloat(bidAmount)
-    formattedCurrentPrice := formatPriceFloat(currentPrice)
+    formattedCurrentPrice := formatPriceFloat(lot.CurrentPrice)
 
     // Формируем сообщение для других участников с номером участника
     messageForUsers := fmt.Sprintf(
         "📢 *Новая ставка в тендере!*\n\n"+
             "📋 Тендер: %s\n"+
+            "📦 %s\n"+
             "👤 Участник: *Участник %d*\n"+
             "💰 Новая ставка: *%s руб.*\n"+
-            "💰 Текущая цена тендера: *%s руб.*\n\n"+
+            "💰 Текущая цена лота: *%s руб.*\n\n"+
             "💡 *Не упустите возможность сделать свою ставку!*",
         tenderTitle,
+        lotLabel(lot),
         participantNumber,
         formattedBidAmount,
         formattedCurrentPrice,
     )
 
+    // Срок мог быть продлён этой ставкой - показываем актуальный
+    if tender.EndAt.Valid {
+        messageForUsers += fmt.Sprintf("\n⏳ *Окончание торгов:* %s", tender.EndAt.Time.Format("02.01.2006 15:04"))
+    }
+
     fmt.Printf("Тендер %s имеет %d участников\n", tenderTitle, len(userIds))
 
     // Отправляем уведомления всем участникам, кроме того, кто сделал ставку
@@ -1263,7 +1988,7 @@ func sendBidNotificationToOtherParticipants(bot *telebot.Bot, queries *db.Querie
             ReplyMarkup: &telebot.ReplyMarkup{
                 InlineKeyboard: [][]telebot.InlineButton{
                     {
-                        {Unique: "make_bid", Text: "💵 Сделать ставку", Data: fmt.Sprintf("%d|%d", tenderID, userId)},
+                        {Unique: "select_lot", Text: "💵 Сделать ставку", Data: strconv.Itoa(int(lot.ID))},
                     },
                 },
             },
@@ -1277,45 +2002,29 @@ func sendBidNotificationToOtherParticipants(bot *telebot.Bot, queries *db.Querie
     }
 }
 
-func handleSupplierClassification(c telebot.Context, classCode string) error {
+func handleSupplierClassification(c telebot.Context, queries *db.Queries, classCode string) error {
 	userID := c.Sender().ID
-	if _, ok := supplierData[userID]; !ok {
-		supplierData[userID] = make(map[string]string)
-	}
-
-	data := supplierData[userID]["classifications"]
-	selected := strings.Split(data, ",")
-	selectedSet := make(map[string]bool)
-	for _, s := range selected {
-		if s != "" {
-			selectedSet[s] = true
-		}
-	}
-
-	if selectedSet[classCode] {
-		delete(selectedSet, classCode)
-	} else {
-		if len(selectedSet) >= 2 {
-			return c.Respond(&telebot.CallbackResponse{
-				Text:      "Можно выбрать только две классификации!",
-				ShowAlert: true,
-			})
-		}
-		selectedSet[classCode] = true
+	conv, ok := loadConversation(userID, state.FlowSupplier)
+	if !ok || !isClassificationStep(conv) {
+		return c.Respond(&telebot.CallbackResponse{Text: "Регистрация не начата или устарела"})
 	}
 
-	var newSelected []string
-	for _, code := range allCodes {
-		if selectedSet[code] {
-			newSelected = append(newSelected, code)
-		}
+	// Коды храним в порядке справочника; архивные классификации выбрать нельзя
+	classifications := activeClassifications(queries)
+	selected, err := toggleClassification(classifications, conv.Get("classifications"), classCode)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      err.Error(),
+			ShowAlert: true,
+		})
 	}
-	supplierData[userID]["classifications"] = strings.Join(newSelected, ",")
+	conv.Put("classifications", selected)
+	saveConversation(userID, state.FlowSupplier, conv)
 
-	markup := showSupplierClassificationKeyboard(userID)
+	markup := showSupplierClassificationKeyboard(classifications, selected)
 
 	msg := c.Message()
-	currentText := "Выберите до двух классификаций вашей организации:"
+	currentText := supplierClassificationPrompt
 	if msg != nil && msg.Text != "" {
 		currentText = msg.Text
 	}
@@ -1323,9 +2032,20 @@ func handleSupplierClassification(c telebot.Context, classCode string) error {
 	return c.Edit(currentText, &telebot.SendOptions{ReplyMarkup: markup})
 }
 
-func handleSupplierClassificationDone(c telebot.Context) error {
+// isClassificationStep сообщает, что поставщик выбирает классификации при регистрации
+// или в профиле
+func isClassificationStep(conv state.Conversation) bool {
+	step := SupplierState(conv.Step)
+	return step == StateSelectClassification || step == StateProfileClassification
+}
+
+func handleSupplierClassificationDone(c telebot.Context, queries *db.Queries) error {
 	userID := c.Sender().ID
-	data := supplierData[userID]["classifications"]
+	conv, ok := loadConversation(userID, state.FlowSupplier)
+	if !ok || !isClassificationStep(conv) {
+		return c.Respond(&telebot.CallbackResponse{Text: "Регистрация не начата или устарела"})
+	}
+	data := conv.Get("classifications")
 
 	if data == "" {
 		return c.Respond(&telebot.CallbackResponse{
@@ -1334,18 +2054,21 @@ func handleSupplierClassificationDone(c telebot.Context) error {
 		})
 	}
 
-	codes := strings.Split(data, ",")
-	var selectedNames []string
-	for _, code := range codes {
-		if name, ok := classificationNames[code]; ok {
-			selectedNames = append(selectedNames, name)
+	if SupplierState(conv.Step) == StateProfileClassification {
+		if err := c.Respond(); err != nil {
+			fmt.Printf("Ошибка ответа на callback: %v\n", err)
+		}
+		if err := c.Edit("Выбранные классификации:\n" + classificationList(data)); err != nil {
+			fmt.Printf("Ошибка обновления сообщения: %v\n", err)
 		}
+		return updateSupplierClassifications(c, queries, userID, splitCodes(data))
 	}
 
-	supplierStates[userID] = StateFIO
+	conv.Step = int(StateFIO)
+	saveConversation(userID, state.FlowSupplier, conv)
 
 	return c.Edit(
-		fmt.Sprintf("Выбранные классификации:\n%s\n\nВведите ФИО участника:", strings.Join(selectedNames, ", ")),
+		fmt.Sprintf("Выбранные классификации:\n%s\n\nВведите ФИО участника:", classificationList(data)),
 		&telebot.SendOptions{
 			ReplyMarkup: nil,
 		},
@@ -1365,6 +2088,13 @@ func handleJoinTender(c telebot.Context, queries *db.Queries) error {
 	tenderID, _ := strconv.ParseInt(parts[0], 10, 32)
 	userID := c.Sender().ID
 
+	if getUserRole(userID, queries) != "supplier" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Участвовать в тендерах могут только поставщики",
+			ShowAlert: true,
+		})
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -1377,28 +2107,6 @@ func handleJoinTender(c telebot.Context, queries *db.Queries) error {
 		})
 	}
 
-	// Проверяем, активен ли тендер и начался ли он
-
-	// Проверяем, участвует ли пользователь уже в других тендерах
-	// hasOtherParticipation, err := queries.CheckUserHasAnyTenderParticipation(ctx, db.CheckUserHasAnyTenderParticipationParams{
-	// 	UserID:   userID,
-	// 	TenderID: int32(tenderID),
-	// })
-	// if err != nil {
-	// 	fmt.Printf("Ошибка при проверке участия пользователя: %v\n", err)
-	// 	return c.Respond(&telebot.CallbackResponse{
-	// 		Text:      "❌ Ошибка при проверке участия",
-	// 		ShowAlert: true,
-	// 	})
-	// }
-
-	// if hasOtherParticipation {
-	// 	return c.Respond(&telebot.CallbackResponse{
-	// 		Text:      "❌ Вы уже участвуете в другом тендере. Для участия в этом тендере необходимо сначала отменить участие в текущем тендере.",
-	// 		ShowAlert: true,
-	// 	})
-	// }
-
 	// Проверяем, не участвует ли пользователь уже в этом тендере
 	isAlreadyParticipating, err := queries.CheckTenderParticipation(ctx, db.CheckTenderParticipationParams{
 		TenderID: int32(tenderID),
@@ -1479,12 +2187,15 @@ func updateTenderMessageAfterJoin(c telebot.Context, tender db.Tender, userID in
 	// Форматируем статус с эмодзи
 	statusEmoji, statusText := getStatusWithEmoji(tender.Status)
 
+	lots := tenderLots(queries, tender.ID)
+
 	// Создаем сообщение с информацией о тендере
 	tenderInfo := fmt.Sprintf(
 		"📋 *Тендер:* %s\n\n"+
 			"📝 *Описание:* %s\n"+
 			"💰 *Стартовая цена:* %s руб.\n"+
 			"💰 *Текущая цена:* %s руб.\n"+
+			"%s"+
 			"📅 *Дата начала:* %s\n"+
 			"🗂️ *Классификация:* %s\n"+
 			"%s *Статус:* %s\n\n"+
@@ -1495,8 +2206,9 @@ func updateTenderMessageAfterJoin(c telebot.Context, tender db.Tender, userID in
 		tender.Description.String,
 		formattedPrice,
 		formattedCurrentPrice,
+		formatTenderTerms(tender, lots),
 		formattedDate,
-		classificationNames[tender.Classification.String],
+		tenderClassificationNames(queries, tender, lots),
 		statusEmoji,
 		statusText,
 		tender.ParticipantsCount,
@@ -1618,11 +2330,11 @@ func handleLeaveTender(c telebot.Context, queries *db.Queries) error {
 	}
 
 	// ОБНОВЛЯЕМ СООБЩЕНИЕ С ТЕНДЕРОМ - возвращаем кнопку "Участвовать"
-	return updateTenderMessageAfterLeave(c, tender, userID)
+	return updateTenderMessageAfterLeave(c, queries, tender, tenderLots(queries, tender.ID), userID)
 }
 
 // Функция для обновления сообщения после выхода из тендера
-func updateTenderMessageAfterLeave(c telebot.Context, tender db.Tender, userID int64) error {
+func updateTenderMessageAfterLeave(c telebot.Context, queries *db.Queries, tender db.Tender, lots []db.TenderLot, userID int64) error {
 	// Форматируем дату
 	var formattedDate string
 	if tender.StartAt.Valid {
@@ -1644,6 +2356,7 @@ func updateTenderMessageAfterLeave(c telebot.Context, tender db.Tender, userID i
 			"📝 *Описание:* %s\n"+
 			"💰 *Стартовая цена:* %s руб.\n"+
 			"💰 *Текущая цена:* %s руб.\n"+
+			"%s"+
 			"📅 *Дата начала:* %s\n"+
 			"🗂️ *Классификация:* %s\n"+
 			"%s *Статус:* %s\n\n"+
@@ -1653,8 +2366,9 @@ func updateTenderMessageAfterLeave(c telebot.Context, tender db.Tender, userID i
 		tender.Description.String,
 		formattedPrice,
 		formattedCurrentPrice,
+		formatTenderTerms(tender, lots),
 		formattedDate,
-		classificationNames[tender.Classification.String],
+		tenderClassificationNames(queries, tender, lots),
 		statusEmoji,
 		statusText,
 		tender.ParticipantsCount,
@@ -1690,27 +2404,13 @@ func updateTenderMessageAfterLeave(c telebot.Context, tender db.Tender, userID i
 		Text: "❌ Вы больше не участвуете в тендере",
 	})
 }
-func showSupplierClassificationKeyboard(userID int64) *telebot.ReplyMarkup {
-	selectedCodes := strings.Split(supplierData[userID]["classifications"], ",")
-	selectedSet := make(map[string]bool)
-	for _, code := range selectedCodes {
-		if code != "" {
-			selectedSet[code] = true
-		}
-	}
+// supplierClassificationPrompt — подсказка к выбору классификаций поставщика
+const supplierClassificationPrompt = "Выберите классификации вашей организации — сколько угодно. Группа (📁) включает все свои подкатегории:"
 
-	var rows [][]telebot.InlineButton
-	for _, code := range allCodes {
-		name := classificationNames[code]
-		text := name
-		if selectedSet[code] {
-			text = "✅ " + name
-		}
-		btn := telebot.InlineButton{Unique: "supplier_class_" + code, Text: text}
-		rows = append(rows, []telebot.InlineButton{btn})
-	}
+func showSupplierClassificationKeyboard(classifications []db.Classification, selected string) *telebot.ReplyMarkup {
+	rows := classificationButtons(classifications, selected, "supplier_class")
 
-	if len(selectedSet) > 0 {
+	if selected != "" {
 		rows = append(rows, []telebot.InlineButton{{Unique: "supplier_class_done", Text: "✅ Завершить выбор "}})
 	}
 
@@ -1739,35 +2439,8 @@ func sendSupplierTendersList(c telebot.Context, queries *db.Queries, userId int6
 		return err
 	}
 
-	classifications := strings.Split(user.Classification.String, ",")
-
-	// Создаем параметры для запроса
-	params := db.GetTendersForSuppliersParams{}
-
-	// Первая классификация (обязательно есть, так как есть хотя бы одна)
-	if len(classifications) > 0 {
-		params.Classification = pgtype.Text{
-			String: classifications[0],
-			Valid:  true,
-		}
-	} else {
-		// Если вообще нет классификаций (маловероятно, но для безопасности)
-		params.Classification = pgtype.Text{Valid: false}
-	}
-
-	// Вторая классификация (может отсутствовать)
-	if len(classifications) > 1 {
-		params.Classification_2 = pgtype.Text{
-			String: classifications[1],
-			Valid:  true,
-		}
-	} else {
-		// Если второй классификации нет, отправляем пустую
-		params.Classification_2 = pgtype.Text{Valid: false}
-	}
-
-	// Выполняем запрос
-	tenders, err := queries.GetTendersForSuppliers(ctx, params)
+	// Выполняем запрос: тендеры с лотами по классификациям поставщика
+	tenders, err := queries.GetTendersForSuppliers(ctx, user.TelegramID)
 	if err != nil {
 		fmt.Printf("Ошибка получения тендеров: %v\n", err)
 		msg, err := c.Bot().Send(c.Sender(), "Не удалось получить список тендеров", &telebot.SendOptions{
@@ -1817,12 +2490,15 @@ func sendSupplierTendersList(c telebot.Context, queries *db.Queries, userId int6
 		// Форматируем статус с эмодзи
 		statusEmoji, statusText := getStatusWithEmoji(tender.Status)
 
+		lots := tenderLots(queries, tender.ID)
+
 		// Создаем сообщение с информацией о тендере
 		tenderInfo := fmt.Sprintf(
 			"📋 *Тендер:* %s\n\n"+
 				"📝 *Описание:* %s\n"+
 				"💰 *Стартовая цена:* %s руб.\n"+
 				"💰 *Текущая цена:* %s руб.\n"+
+				"%s"+
 				"📅 *Дата начала:* %s\n"+
 				"🗂️ *Классификация:* %s\n"+
 				"%s *Статус:* %s\n\n"+
@@ -1832,8 +2508,9 @@ func sendSupplierTendersList(c telebot.Context, queries *db.Queries, userId int6
 			tender.Description.String,
 			formattedPrice,
 			formattedCurrentPrice,
+			formatTenderTerms(tender, lots),
 			formattedDate,
-			classificationNames[tender.Classification.String],
+			tenderClassificationNames(queries, tender, lots),
 			statusEmoji,
 			statusText,
 			tender.ParticipantsCount,
@@ -2031,6 +2708,7 @@ func updateTenderMessage(c telebot.Context, tender db.Tender, userID int64, just
 			"📝 *Описание:* %s\n"+
 			"💰 *Стартовая цена:* %s руб.\n"+
 			"💰 *Текущая цена:* %s руб.\n"+ // ДОБАВЬТЕ ЭТУ СТРОКУ
+			"%s"+
 			"📅 *Дата начала:* %s\n"+
 			"🗂️ *Классификация:* %s\n"+
 			"%s *Статус:* %s\n\n"+
@@ -2040,8 +2718,9 @@ func updateTenderMessage(c telebot.Context, tender db.Tender, userID int64, just
 		tender.Description.String,
 		formattedPrice,
 		currentPriceFormatted, // ТЕКУЩАЯ ЦЕНА
+		formatTenderTerms(tender, nil),
 		formattedDate,
-		classificationNames[tender.Classification.String],
+		classificationName(tender.Classification.String),
 		statusEmoji,
 		statusText,
 		tender.ParticipantsCount,
@@ -2090,3 +2769,90 @@ func updateTenderMessage(c telebot.Context, tender db.Tender, userID int64, just
 	// Отправляем пустой callback response чтобы убрать "часики"
 	return c.Respond()
 }
+
+// participationStatusLabel — итог участия поставщика в тендере для «Мои участия»
+func participationStatusLabel(status string) string {
+	switch status {
+	case db.ParticipantStatusWon:
+		return "🏆 Победа"
+	case db.ParticipantStatusLost:
+		return "📉 Не выиграл"
+	case db.ParticipantStatusWithdrew:
+		return "🚫 Отказался от участия"
+	case db.ParticipantStatusNoBid:
+		return "📭 Ставок не делал"
+	case db.ParticipantStatusCancelled:
+		return "❌ Тендер отменён"
+	default:
+		return "❓ Неизвестно"
+	}
+}
+
+// sendSupplierParticipations показывает поставщику архив его участий: по каждому
+// тендеру итог, а по лотам со ставками — лучшую ставку, место и победную цену
+func sendSupplierParticipations(c telebot.Context, queries *db.Queries, userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rows, err := queries.GetSupplierParticipations(ctx, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения участий поставщика %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось загрузить ваши участия", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuSupplierRegistered,
+		})
+	}
+
+	if len(rows) == 0 {
+		return c.Send("📭 У вас пока нет завершённых участий в тендерах", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuSupplierRegistered,
+		})
+	}
+
+	// Строки идут по тендерам, внутри тендера — по лотам
+	var entries []string
+	var entry string
+	var tendersCount int
+	for i, row := range rows {
+		if i == 0 || rows[i-1].TenderID != row.TenderID {
+			if entry != "" {
+				entries = append(entries, entry)
+			}
+			tendersCount++
+			_, tenderStatus := getStatusWithEmoji(row.TenderStatus)
+			entry = fmt.Sprintf("📋 *%s*\n%s · тендер: %s\n", row.TenderTitle, participationStatusLabel(row.ParticipationStatus), tenderStatus)
+		}
+
+		if !row.BestBid.Valid {
+			continue
+		}
+		winningPrice := "победитель не определён"
+		if row.WinningPrice.Valid {
+			winningPrice = "победная цена " + formatPriceFloat(row.WinningPrice.Float64) + " руб."
+		}
+		entry += fmt.Sprintf("   📦 Лот №%d: %s — ваша ставка %s руб., место %d, %s\n",
+			row.LotNumber,
+			row.LotTitle,
+			formatPriceFloat(row.BestBid.Float64),
+			row.BidRank,
+			winningPrice)
+	}
+	entries = append(entries, entry)
+
+	// Telegram ограничивает длину сообщения, поэтому длинный архив отправляется частями
+	message := "🗂️ *Мои участия*\n\n"
+	for _, entry := range entries {
+		if len(message)+len(entry) > 3500 {
+			if err := c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeMarkdown}); err != nil {
+				fmt.Printf("Ошибка отправки участий поставщику %d: %v\n", userID, err)
+			}
+			message = ""
+		}
+		message += entry + "\n"
+	}
+	message += fmt.Sprintf("✅ Всего тендеров: %d", tendersCount)
+
+	return c.Send(message, &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuSupplierRegistered,
+	})
+}
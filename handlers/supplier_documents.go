@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tender_bot_go/db"
+	"tender_bot_go/state"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+)
+
+// maxSupplierDocumentSize — наибольший размер документа поставщика; больше Bot API
+// скачать не даст
+const maxSupplierDocumentSize = 20 << 20
+
+// documentKinds — виды документов в порядке кнопок выбора
+var documentKinds = []string{
+	db.DocumentKindEGRUL,
+	db.DocumentKindSRO,
+	db.DocumentKindPowerOfAttorney,
+}
+
+// documentKindNames — подписи видов документов
+var documentKindNames = map[string]string{
+	db.DocumentKindEGRUL:           "Выписка ЕГРЮЛ",
+	db.DocumentKindSRO:             "Свидетельство СРО",
+	db.DocumentKindPowerOfAttorney: "Доверенность",
+}
+
+const supplierDocumentsPrompt = "📨 Заявка передана администратору.\n\n" +
+	"📎 Приложите документы для проверки: выписку ЕГРЮЛ, " +
+	"свидетельство СРО, доверенность. Присылайте их по одному файлом.\n\n" +
+	"Шаг необязательный — нажмите «Готово», когда закончите, или сразу, чтобы пропустить."
+
+// documentsDoneMarkup — кнопка завершения шага документов
+func documentsDoneMarkup() *telebot.ReplyMarkup {
+	return &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{
+			{{Unique: "documents_done", Text: "✅ Готово"}},
+		},
+	}
+}
+
+// startSupplierDocuments отправляет сохранённую заявку администраторам и переводит
+// регистрацию на шаг документов. Документы уходят администраторам по мере загрузки,
+// поэтому заявка не теряется, если поставщик не дойдёт до «Готово».
+func startSupplierDocuments(c telebot.Context, queries *db.Queries, userID int64, conv state.Conversation) error {
+	sendRegistrationRequestToAdmins(c, queries, userID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pendingUser, err := queries.GetPendingUser(ctx, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения заявки пользователя %d: %v\n", userID, err)
+		return finishRegistrationRequest(c, userID)
+	}
+
+	conv.Put("pending_user_id", strconv.Itoa(int(pendingUser.ID)))
+	conv.Step = int(StateDocuments)
+	saveConversation(userID, state.FlowSupplier, conv)
+
+	return c.Send(supplierDocumentsPrompt, &telebot.SendOptions{
+		ReplyMarkup: documentsDoneMarkup(),
+	})
+}
+
+// finishRegistrationRequest завершает регистрацию после шага документов
+func finishRegistrationRequest(c telebot.Context, userID int64) error {
+	clearConversation(userID, state.FlowSupplier)
+
+	msg, err := c.Bot().Send(c.Sender(), "✅ Заявка на регистрацию отправлена на модерацию!\n\nОжидайте подтверждения администратора.", &telebot.SendOptions{
+		ReplyMarkup: &telebot.ReplyMarkup{
+			RemoveKeyboard: true,
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	MessageManagerOperator.AddMessage(userID, msg.ID)
+
+	return nil
+}
+
+func isDocumentStep(conv state.Conversation) bool {
+	switch SupplierState(conv.Step) {
+	case StateDocuments, StateDocumentKind, StateDocumentExpiry:
+		return true
+	}
+	return false
+}
+
+// HandleSupplierDocument принимает файл документа при регистрации или по запросу
+// администратора на повторную загрузку
+func HandleSupplierDocument(c telebot.Context, queries *db.Queries, userID int64) error {
+	conv, ok := loadConversation(userID, state.FlowSupplier)
+	if !ok || !isDocumentStep(conv) {
+		return nil
+	}
+	if SupplierState(conv.Step) != StateDocuments {
+		return c.Send("Сначала закончите с предыдущим документом.")
+	}
+
+	doc := c.Message().Document
+	if doc.FileSize > maxSupplierDocumentSize {
+		return c.Send("❌ Файл больше 20 МБ. Пришлите файл меньшего размера.")
+	}
+
+	filePath, err := saveSupplierDocumentFile(c, userID, doc)
+	if err != nil {
+		fmt.Printf("Ошибка сохранения документа пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось сохранить файл. Попробуйте ещё раз.")
+	}
+
+	conv.Put("doc_path", filePath)
+	conv.Put("doc_name", doc.FileName)
+
+	// При повторной загрузке вид документа уже известен
+	if conv.Get("document_id") != "" {
+		conv.Step = int(StateDocumentExpiry)
+		saveConversation(userID, state.FlowSupplier, conv)
+		return c.Send(documentExpiryPrompt)
+	}
+
+	conv.Step = int(StateDocumentKind)
+	saveConversation(userID, state.FlowSupplier, conv)
+
+	var rows [][]telebot.InlineButton
+	for _, kind := range documentKinds {
+		rows = append(rows, []telebot.InlineButton{{
+			Unique: "document_kind",
+			Text:   documentKindNames[kind],
+			Data:   kind,
+		}})
+	}
+	return c.Send("Какой это документ?", &telebot.SendOptions{
+		ReplyMarkup: &telebot.ReplyMarkup{InlineKeyboard: rows},
+	})
+}
+
+// saveSupplierDocumentFile сохраняет присланный файл в каталог documents и возвращает путь к нему
+func saveSupplierDocumentFile(c telebot.Context, userID int64, doc *telebot.Document) (string, error) {
+	dir := filepath.Join(config.FilesDir, "documents")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(dir, fmt.Sprintf("%d_%d_%s", userID, time.Now().UnixNano(), filepath.Base(doc.FileName)))
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	reader, err := c.Bot().File(&doc.File)
+	if err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(f, reader); err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+	return filePath, nil
+}
+
+const documentExpiryPrompt = "Введите дату окончания срока действия документа (ДД.ММ.ГГГГ) " +
+	"или «-», если срок не ограничен:"
+
+func handleDocumentKind(c telebot.Context) error {
+	userID := c.Sender().ID
+	conv, ok := loadConversation(userID, state.FlowSupplier)
+	if !ok || SupplierState(conv.Step) != StateDocumentKind {
+		return c.Respond(&telebot.CallbackResponse{Text: "Загрузка документа не начата или устарела"})
+	}
+
+	kind := c.Data()
+	name, ok := documentKindNames[kind]
+	if !ok {
+		return c.Respond(&telebot.CallbackResponse{Text: "Неизвестный вид документа"})
+	}
+
+	conv.Put("doc_kind", kind)
+	conv.Step = int(StateDocumentExpiry)
+	saveConversation(userID, state.FlowSupplier, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Edit(fmt.Sprintf("📄 %s\n\n%s", name, documentExpiryPrompt))
+}
+
+// parseDocumentExpiry разбирает срок действия документа; «-» означает бессрочный документ
+func parseDocumentExpiry(text string) (pgtype.Date, error) {
+	text = strings.TrimSpace(text)
+	if text == "-" {
+		return pgtype.Date{}, nil
+	}
+
+	expiresAt, err := time.Parse("02.01.2006", text)
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("неверный формат даты, используйте ДД.ММ.ГГГГ")
+	}
+	if expiresAt.Before(time.Now().Truncate(24 * time.Hour)) {
+		return pgtype.Date{}, fmt.Errorf("срок действия документа уже истёк, пришлите действующий документ")
+	}
+	return pgtype.Date{Time: expiresAt, Valid: true}, nil
+}
+
+// handleDocumentExpiryText сохраняет документ после ввода срока действия
+func handleDocumentExpiryText(c telebot.Context, queries *db.Queries, text string, userID int64, conv state.Conversation) error {
+	expiresAt, err := parseDocumentExpiry(text)
+	if err != nil {
+		return c.Send("❌ " + err.Error() + ". Введите дату ещё раз:")
+	}
+
+	if conv.Get("document_id") != "" {
+		return replaceSupplierDocument(c, queries, userID, conv, expiresAt)
+	}
+
+	pendingUserID, _ := strconv.Atoi(conv.Get("pending_user_id"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	document, err := queries.CreateSupplierDocument(ctx, db.CreateSupplierDocumentParams{
+		UserID:        userID,
+		PendingUserID: pgtype.Int4{Int32: int32(pendingUserID), Valid: pendingUserID != 0},
+		Kind:          conv.Get("doc_kind"),
+		FilePath:      conv.Get("doc_path"),
+		FileName:      conv.Get("doc_name"),
+		ExpiresAt:     expiresAt,
+	})
+	// Заявку уже отклонили, пока поставщик загружал документы
+	if isForeignKeyViolation(err) {
+		clearConversation(userID, state.FlowSupplier)
+		os.Remove(conv.Get("doc_path"))
+		return c.Send("Заявка уже рассмотрена администратором, документ не сохранён.")
+	}
+	if err != nil {
+		fmt.Printf("Ошибка сохранения документа пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось сохранить документ. Попробуйте ещё раз:")
+	}
+
+	sendDocumentToAdmins(c, queries, fmt.Sprintf("📎 Поставщик @%s (ID: %d) приложил документ к заявке", c.Sender().Username, userID), document)
+
+	kind := conv.Get("doc_kind")
+	delete(conv.Data, "doc_path")
+	delete(conv.Data, "doc_name")
+	delete(conv.Data, "doc_kind")
+	conv.Step = int(StateDocuments)
+	saveConversation(userID, state.FlowSupplier, conv)
+
+	return c.Send(fmt.Sprintf("✅ Документ «%s» добавлен к заявке. Пришлите следующий или нажмите «Готово».", documentKindNames[kind]), &telebot.SendOptions{
+		ReplyMarkup: documentsDoneMarkup(),
+	})
+}
+
+// replaceSupplierDocument заменяет документ, повторную загрузку которого запросил администратор
+func replaceSupplierDocument(c telebot.Context, queries *db.Queries, userID int64, conv state.Conversation, expiresAt pgtype.Date) error {
+	documentID, _ := strconv.Atoi(conv.Get("document_id"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	old, err := queries.GetSupplierDocument(ctx, int32(documentID))
+	if err != nil {
+		fmt.Printf("Ошибка получения документа %d: %v\n", documentID, err)
+		clearConversation(userID, state.FlowSupplier)
+		os.Remove(conv.Get("doc_path"))
+		return c.Send("❌ Документ не найден")
+	}
+
+	document, err := queries.ReplaceSupplierDocument(ctx, db.ReplaceSupplierDocumentParams{
+		ID:        old.ID,
+		FilePath:  conv.Get("doc_path"),
+		FileName:  conv.Get("doc_name"),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка замены документа %d: %v\n", documentID, err)
+		return c.Send("❌ Не удалось сохранить документ. Попробуйте ещё раз:")
+	}
+	if err := os.Remove(old.FilePath); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Ошибка удаления файла %s: %v\n", old.FilePath, err)
+	}
+
+	clearConversation(userID, state.FlowSupplier)
+
+	sendDocumentToAdmins(c, queries, fmt.Sprintf("🔁 Поставщик @%s (ID: %d) загрузил документ заново", c.Sender().Username, userID), document)
+
+	return c.Send(fmt.Sprintf("✅ Документ «%s» обновлён и отправлен администратору.", documentKindNames[document.Kind]))
+}
+
+// sendDocumentToAdmins отправляет администраторам документ с поясняющим сообщением
+func sendDocumentToAdmins(c telebot.Context, queries *db.Queries, header string, document db.SupplierDocument) {
+	for _, adminID := range usersWithRole(queries, "admin") {
+		recipient := &telebot.User{ID: adminID}
+		if _, err := c.Bot().Send(recipient, header); err != nil {
+			fmt.Printf("Ошибка уведомления администратора %d: %v\n", adminID, err)
+			continue
+		}
+		sendSupplierDocuments(c.Bot(), recipient, []db.SupplierDocument{document})
+	}
+}
+
+func handleDocumentsDone(c telebot.Context) error {
+	userID := c.Sender().ID
+	conv, ok := loadConversation(userID, state.FlowSupplier)
+	if !ok || !isDocumentStep(conv) || conv.Get("pending_user_id") == "" {
+		return c.Respond(&telebot.CallbackResponse{Text: "Регистрация не начата или устарела"})
+	}
+
+	// Файл, для которого не указали вид или срок, в заявку не попадает
+	if path := conv.Get("doc_path"); path != "" {
+		os.Remove(path)
+	}
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	if _, err := c.Bot().EditReplyMarkup(c.Message(), nil); err != nil {
+		fmt.Printf("Ошибка обновления сообщения: %v\n", err)
+	}
+	return finishRegistrationRequest(c, userID)
+}
+
+// pendingUserDocuments возвращает документы, приложенные к заявке
+func pendingUserDocuments(queries *db.Queries, pendingUserID int32) []db.SupplierDocument {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	documents, err := queries.GetPendingUserDocuments(ctx, pgtype.Int4{Int32: pendingUserID, Valid: true})
+	if err != nil {
+		fmt.Printf("Ошибка получения документов заявки %d: %v\n", pendingUserID, err)
+		return nil
+	}
+	return documents
+}
+
+// documentCaption описывает документ: вид, срок действия и запрос повторной загрузки
+func documentCaption(document db.SupplierDocument) string {
+	caption := "📄 " + documentKindNames[document.Kind]
+	switch {
+	case !document.ExpiresAt.Valid:
+		caption += "\nСрок действия: не ограничен"
+	case document.ExpiresAt.Time.Before(time.Now().Truncate(24 * time.Hour)):
+		caption += "\n⚠️ Срок действия истёк " + document.ExpiresAt.Time.Format("02.01.2006")
+	default:
+		caption += "\nДействует до " + document.ExpiresAt.Time.Format("02.01.2006")
+	}
+	if document.ReuploadRequestedAt.Valid {
+		caption += "\n🔁 Запрошена повторная загрузка " + document.ReuploadRequestedAt.Time.Format("02.01.2006")
+	}
+	return caption
+}
+
+// sendSupplierDocuments отправляет администратору файлы документов с кнопкой запроса
+// повторной загрузки
+func sendSupplierDocuments(bot *telebot.Bot, to telebot.Recipient, documents []db.SupplierDocument) {
+	for _, document := range documents {
+		file := &telebot.Document{
+			File:     telebot.FromDisk(document.FilePath),
+			FileName: document.FileName,
+			Caption:  documentCaption(document),
+		}
+		_, err := bot.Send(to, file, &telebot.SendOptions{
+			ReplyMarkup: &telebot.ReplyMarkup{
+				InlineKeyboard: [][]telebot.InlineButton{{{
+					Unique: "request_document_reupload",
+					Text:   "🔁 Запросить повторную загрузку",
+					Data:   strconv.Itoa(int(document.ID)),
+				}}},
+			},
+		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки документа %d: %v\n", document.ID, err)
+		}
+	}
+}
+
+// deletePendingUserDocuments удаляет документы отклонённой заявки вместе с файлами
+func deletePendingUserDocuments(ctx context.Context, queries *db.Queries, pendingUserID int32) {
+	paths, err := queries.DeletePendingUserDocuments(ctx, pgtype.Int4{Int32: pendingUserID, Valid: true})
+	if err != nil {
+		fmt.Printf("Ошибка удаления документов заявки %d: %v\n", pendingUserID, err)
+		return
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("Ошибка удаления файла %s: %v\n", path, err)
+		}
+	}
+}
+
+// handleRequestDocumentReupload просит поставщика загрузить документ заново
+func handleRequestDocumentReupload(c telebot.Context, queries *db.Queries) error {
+	if !isAdmin(c.Sender().ID, queries) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ У вас нет прав для этого действия",
+			ShowAlert: true,
+		})
+	}
+
+	documentID, err := strconv.Atoi(c.Data())
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Ошибка формата данных"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	document, err := queries.GetSupplierDocument(ctx, int32(documentID))
+	if err != nil {
+		fmt.Printf("Ошибка получения документа %d: %v\n", documentID, err)
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Документ не найден", ShowAlert: true})
+	}
+
+	if err := queries.RequestSupplierDocumentReupload(ctx, document.ID); err != nil {
+		fmt.Printf("Ошибка запроса повторной загрузки документа %d: %v\n", document.ID, err)
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Не удалось отправить запрос", ShowAlert: true})
+	}
+
+	message := fmt.Sprintf("🔁 Администратор просит загрузить заново документ «%s» (%s).", documentKindNames[document.Kind], document.FileName)
+	if document.ExpiresAt.Valid {
+		message += "\nСрок действия текущего документа: до " + document.ExpiresAt.Time.Format("02.01.2006")
+	}
+	_, err = c.Bot().Send(&telebot.User{ID: document.UserID}, message, &telebot.SendOptions{
+		ReplyMarkup: &telebot.ReplyMarkup{
+			InlineKeyboard: [][]telebot.InlineButton{{{
+				Unique: "reupload_document",
+				Text:   "📤 Загрузить заново",
+				Data:   strconv.Itoa(int(document.ID)),
+			}}},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Ошибка уведомления поставщика %d: %v\n", document.UserID, err)
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Не удалось уведомить поставщика", ShowAlert: true})
+	}
+
+	_, err = c.Bot().EditReplyMarkup(c.Message(), &telebot.ReplyMarkup{
+		InlineKeyboard: [][]telebot.InlineButton{{{
+			Unique: "request_document_reupload",
+			Text:   "✅ Повторная загрузка запрошена",
+			Data:   strconv.Itoa(int(document.ID)),
+		}}},
+	})
+	if err != nil {
+		fmt.Printf("Ошибка при обновлении кнопки: %v\n", err)
+	}
+
+	return c.Respond(&telebot.CallbackResponse{Text: "✅ Запрос отправлен поставщику"})
+}
+
+// handleReuploadDocument начинает повторную загрузку документа поставщиком
+func handleReuploadDocument(c telebot.Context, queries *db.Queries) error {
+	userID := c.Sender().ID
+	documentID, err := strconv.Atoi(c.Data())
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Ошибка формата данных"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	document, err := queries.GetSupplierDocument(ctx, int32(documentID))
+	if err != nil || document.UserID != userID {
+		return c.Respond(&telebot.CallbackResponse{Text: "❌ Документ не найден", ShowAlert: true})
+	}
+
+	saveConversation(userID, state.FlowSupplier, state.Conversation{
+		Step: int(StateDocuments),
+		Data: map[string]string{"document_id": strconv.Itoa(int(document.ID))},
+	})
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(fmt.Sprintf("📎 Пришлите новый файл документа «%s».", documentKindNames[document.Kind]))
+}
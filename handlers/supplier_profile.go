@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"tender_bot_go/db"
+	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+)
+
+// Поля профиля, которые поставщик меняет кнопкой edit_profile. Телефон, контактное
+// лицо и классификации меняются сразу, реквизиты — после одобрения админом.
+const (
+	profileFieldPhone          = "phone"
+	profileFieldName           = "name"
+	profileFieldClassification = "classification"
+	profileFieldRequisites     = "requisites"
+)
+
+// maxContactNameLength — наибольшая длина ФИО контактного лица (users.name)
+const maxContactNameLength = 255
+
+// pendingKindNames — подписи видов заявок для админа
+var pendingKindNames = map[string]string{
+	db.PendingKindRegistration: "регистрация",
+	db.PendingKindRequisites:   "изменение реквизитов",
+}
+
+// parsePhone оставляет в номере телефона только цифры и проверяет, что их не меньше десяти
+func parsePhone(text string) (string, bool) {
+	phone := ""
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			phone += string(r)
+		}
+	}
+	return phone, len(phone) >= 10
+}
+
+// classificationList возвращает названия классификаций по списку кодов через запятую
+func classificationList(codes string) string {
+	var names []string
+	for _, code := range splitCodes(codes) {
+		if _, ok := lookupClassification(code); ok {
+			names = append(names, classificationTitle(code))
+		}
+	}
+	if len(names) == 0 {
+		return "не выбраны"
+	}
+	return strings.Join(names, ", ")
+}
+
+// pendingRequisites возвращает заявку поставщика на изменение реквизитов, если она есть
+func pendingRequisites(ctx context.Context, queries *db.Queries, userID int64) (db.PendingUser, bool) {
+	pendingUser, err := queries.GetPendingUser(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			fmt.Printf("Ошибка проверки заявки пользователя %d: %v\n", userID, err)
+		}
+		return db.PendingUser{}, false
+	}
+	return pendingUser, pendingUser.Kind == db.PendingKindRequisites
+}
+
+// sendSupplierProfile показывает поставщику сохранённые данные организации и кнопки их изменения
+func sendSupplierProfile(c telebot.Context, queries *db.Queries, userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := queries.GetUserByTelegramID(ctx, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения профиля пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось загрузить профиль", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuSupplierRegistered,
+		})
+	}
+
+	codes, err := userClassificationCodes(ctx, queries, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения классификаций пользователя %d: %v\n", userID, err)
+	}
+
+	message := fmt.Sprintf(
+		"👤 *Профиль поставщика*\n\n"+
+			"🏢 *Организация:* %s\n"+
+			"🆔 *ИНН:* %s\n"+
+			"🧾 *ОГРН:* %s\n"+
+			"📞 *Телефон:* %s\n"+
+			"👨‍💼 *Контактное лицо:* %s\n"+
+			"🗂️ *Классификации:* %s",
+		user.OrganizationName.String,
+		user.Inn.String,
+		valueOrDash(user.Ogrn),
+		user.PhoneNumber.String,
+		user.Name.String,
+		classificationList(codes),
+	)
+
+	if pending, ok := pendingRequisites(ctx, queries, userID); ok {
+		message += fmt.Sprintf(
+			"\n\n⏳ *На проверке у администратора:*\n"+
+				"🏢 %s\n"+
+				"🆔 ИНН %s, ОГРН %s",
+			pending.OrganizationName.String,
+			pending.Inn.String,
+			pendingOGRN(pending),
+		)
+	}
+
+	inlineKeyboard := [][]telebot.InlineButton{
+		{
+			{Unique: "edit_profile", Text: "📞 Телефон", Data: profileFieldPhone},
+			{Unique: "edit_profile", Text: "👨‍💼 Контактное лицо", Data: profileFieldName},
+		},
+		{
+			{Unique: "edit_profile", Text: "🗂️ Классификации", Data: profileFieldClassification},
+		},
+		{
+			{Unique: "edit_profile", Text: "🏢 Название, ИНН и ОГРН", Data: profileFieldRequisites},
+		},
+	}
+
+	return c.Send(message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{
+			InlineKeyboard: inlineKeyboard,
+		},
+	})
+}
+
+// valueOrDash возвращает значение поля или прочерк, если оно не заполнено
+func valueOrDash(value pgtype.Text) string {
+	if !value.Valid || value.String == "" {
+		return "—"
+	}
+	return value.String
+}
+
+// handleEditProfile начинает изменение выбранного поля профиля
+func handleEditProfile(c telebot.Context, queries *db.Queries) error {
+	userID := c.Sender().ID
+	user, err := getUserWithTimeout(queries, userID)
+	if err != nil || user.Role != "supplier" || !user.OrganizationName.Valid {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Профиль доступен только зарегистрированным поставщикам",
+			ShowAlert: true,
+		})
+	}
+
+	var conv state.Conversation
+	var prompt string
+	var markup *telebot.ReplyMarkup
+	switch c.Data() {
+	case profileFieldPhone:
+		conv.Step = int(StateProfilePhone)
+		prompt = "Введите новый контактный телефон:"
+	case profileFieldName:
+		conv.Step = int(StateProfileName)
+		prompt = "Введите ФИО контактного лица:"
+	case profileFieldClassification:
+		// Архивные классификации выбрать нельзя, поэтому в выбор попадают только действующие
+		classifications := activeClassifications(queries)
+		active := make(map[string]bool, len(classifications))
+		for _, classification := range classifications {
+			active[classification.Code] = true
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		codes, err := userClassificationCodes(ctx, queries, userID)
+		if err != nil {
+			fmt.Printf("Ошибка получения классификаций пользователя %d: %v\n", userID, err)
+		}
+		var selected []string
+		for _, code := range splitCodes(codes) {
+			if active[code] {
+				selected = append(selected, code)
+			}
+		}
+
+		conv.Step = int(StateProfileClassification)
+		conv.Put("classifications", strings.Join(selected, ","))
+		prompt = supplierClassificationPrompt
+		markup = showSupplierClassificationKeyboard(classifications, conv.Get("classifications"))
+	case profileFieldRequisites:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, ok := pendingRequisites(ctx, queries, userID); ok {
+			return c.Respond(&telebot.CallbackResponse{
+				Text:      "⏳ Предыдущее изменение реквизитов ещё на проверке у администратора",
+				ShowAlert: true,
+			})
+		}
+		conv.Step = int(StateOrgName)
+		conv.Put("kind", db.PendingKindRequisites)
+		prompt = "Изменение реквизитов проверяет администратор, до его решения действуют прежние данные.\n\nВведите наименование вашей организации:"
+	default:
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Неизвестное поле профиля",
+			ShowAlert: true,
+		})
+	}
+	saveConversation(userID, state.FlowSupplier, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	if markup != nil {
+		return c.Send(prompt, markup)
+	}
+	return c.Send(prompt)
+}
+
+// getUserWithTimeout загружает пользователя по Telegram ID
+func getUserWithTimeout(queries *db.Queries, userID int64) (db.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return queries.GetUserByTelegramID(ctx, userID)
+}
+
+// updateSupplierProfile меняет телефон или контактное лицо поставщика
+// без участия админа и показывает обновлённый профиль
+func updateSupplierProfile(c telebot.Context, queries *db.Queries, userID int64, change func(*db.UpdateUserProfileParams)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := queries.GetUserByTelegramID(ctx, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения профиля пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось загрузить профиль")
+	}
+
+	params := db.UpdateUserProfileParams{
+		TelegramID:  userID,
+		PhoneNumber: user.PhoneNumber,
+		Name:        user.Name,
+	}
+	change(&params)
+
+	if err := queries.UpdateUserProfile(ctx, params); err != nil {
+		fmt.Printf("Ошибка обновления профиля пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось сохранить изменения. Попробуйте снова.")
+	}
+	return profileUpdated(c, queries, userID)
+}
+
+// updateSupplierClassifications заменяет классификации поставщика выбранными в профиле
+func updateSupplierClassifications(c telebot.Context, queries *db.Queries, userID int64, codes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := queries.SetUserClassifications(ctx, userID, codes); err != nil {
+		fmt.Printf("Ошибка обновления классификаций пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось сохранить изменения. Попробуйте снова.")
+	}
+	return profileUpdated(c, queries, userID)
+}
+
+// profileUpdated завершает изменение профиля и показывает обновлённые данные
+func profileUpdated(c telebot.Context, queries *db.Queries, userID int64) error {
+	clearConversation(userID, state.FlowSupplier)
+
+	if err := c.Send("✅ Профиль обновлён", &telebot.SendOptions{
+		ReplyMarkup: menu.MenuSupplierRegistered,
+	}); err != nil {
+		return err
+	}
+	return sendSupplierProfile(c, queries, userID)
+}
+
+// submitRequisitesChange отправляет админам заявку на изменение названия организации,
+// ИНН и ОГРН. Остальные поля профиля копируются в заявку только для справки.
+func submitRequisitesChange(c telebot.Context, queries *db.Queries, userID int64, conv state.Conversation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := queries.GetUserByTelegramID(ctx, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения профиля пользователя %d: %v\n", userID, err)
+		return c.Send("❌ Не удалось загрузить профиль")
+	}
+
+	err = queries.CreatePendingUser(ctx, db.CreatePendingUserParams{
+		TelegramID: userID,
+		OrganizationName: pgtype.Text{
+			String: conv.Get("org_name"),
+			Valid:  true,
+		},
+		Inn: pgtype.Text{
+			String: conv.Get("inn"),
+			Valid:  true,
+		},
+		Ogrn: pgtype.Text{
+			String: conv.Get("ogrn"),
+			Valid:  true,
+		},
+		PhoneNumber: user.PhoneNumber,
+		Name:        user.Name,
+		Kind:        db.PendingKindRequisites,
+	})
+	clearConversation(userID, state.FlowSupplier)
+	if isUniqueViolation(err) {
+		return c.Send("⏳ Предыдущее изменение реквизитов ещё на проверке у администратора", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuSupplierRegistered,
+		})
+	}
+	if err != nil {
+		fmt.Printf("Ошибка сохранения заявки на изменение реквизитов: %v\n", err)
+		return c.Send("❌ Ошибка при сохранении данных. Попробуйте снова.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuSupplierRegistered,
+		})
+	}
+
+	sendRegistrationRequestToAdmins(c, queries, userID)
+
+	return c.Send("✅ Новые реквизиты отправлены на проверку администратору. До его решения действуют прежние данные.", &telebot.SendOptions{
+		ReplyMarkup: menu.MenuSupplierRegistered,
+	})
+}
+
+// pendingUserHeadline возвращает заголовок заявки для админа. К заявке на изменение
+// реквизитов добавляются реквизиты, которые действуют сейчас.
+func pendingUserHeadline(queries *db.Queries, pendingUser db.PendingUser) string {
+	if pendingUser.Kind != db.PendingKindRequisites {
+		return "🆕 *НОВАЯ ЗАЯВКА НА РЕГИСТРАЦИЮ*"
+	}
+
+	headline := "✏️ *ИЗМЕНЕНИЕ РЕКВИЗИТОВ ПОСТАВЩИКА*"
+	user, err := getUserWithTimeout(queries, pendingUser.TelegramID)
+	if err != nil {
+		fmt.Printf("Ошибка получения профиля пользователя %d: %v\n", pendingUser.TelegramID, err)
+		return headline
+	}
+	return headline + fmt.Sprintf(
+		"\n\nСейчас: %s, ИНН %s, ОГРН %s\nНовые данные:",
+		user.OrganizationName.String,
+		user.Inn.String,
+		valueOrDash(user.Ogrn),
+	)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"tender_bot_go/db"
+	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"tender_bot_go/tender"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// isTenderDeletable сообщает, можно ли удалить тендер совсем: он ни разу не покидал
+// pending_approval, то есть его не одобряли, не отклоняли и к нему никто не присоединялся
+func isTenderDeletable(ctx context.Context, queries *db.Queries, t db.Tender) bool {
+	if t.Status != tender.StatusPendingApproval {
+		return false
+	}
+	events, err := queries.GetTenderEvents(ctx, t.ID)
+	return err == nil && len(events) == 0
+}
+
+// handleCancelTender начинает отмену тендера: спрашивает у организатора причину,
+// которую получат участники
+func handleCancelTender(c telebot.Context, queries *db.Queries) error {
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Отменять тендеры может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Отмена необратима, поэтому тендер без владельца (organizer_id IS NULL), который
+	// видят все организаторы, отменить нельзя — только свой собственный
+	current, err := queries.GetTender(ctx, int32(tenderID))
+	if err != nil || current.OrganizerID != organizerOwnerID(userID) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не найден среди ваших тендеров",
+			ShowAlert: true,
+		})
+	}
+	if !tender.CanTransition(current.Status, tender.StatusCancelled) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер уже завершён и не может быть отменён",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateCancelReason)}
+	conv.Put("cancel_tender_id", strconv.Itoa(int(current.ID)))
+	saveConversation(userID, state.FlowOrganizer, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(fmt.Sprintf("🚫 *Отмена тендера «%s»*\n\nНапишите причину отмены — её получат все участники тендера:", current.Title), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// cancelTender переводит тендер в cancelled с указанной причиной: останавливает
+// таймеры торгов, снимает лоты и предложения победителям и сообщает участникам.
+// Ставки, участники и архив сохраняются.
+func cancelTender(bot *telebot.Bot, queries *db.Queries, tenderID int32, actorID int64, reason string) (db.Tender, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	current, err := queries.GetTender(ctx, tenderID)
+	if err != nil {
+		return db.Tender{}, err
+	}
+
+	// Лоты, предложения победителям и участники снимаются в одной транзакции со сменой
+	// статуса: отменённый тендер не остаётся с лотом в торгах или ожидающим победителем
+	var participants []int64
+	err = tender.TransitionWith(ctx, queries, tenderID, current.Status, tender.StatusCancelled, actorID, reason, func(q *db.Queries) error {
+		if err := q.CancelTenderLots(ctx, tenderID); err != nil {
+			return fmt.Errorf("отмена лотов: %w", err)
+		}
+		if err := q.CancelTenderWinnerOffers(ctx, tenderID); err != nil {
+			return fmt.Errorf("снятие предложений победителям: %w", err)
+		}
+
+		var err error
+		participants, err = q.GetParticipantsForTender(ctx, tenderID)
+		if err != nil {
+			return fmt.Errorf("получение участников: %w", err)
+		}
+		if err := q.CancelParticipants(ctx, tenderID); err != nil {
+			return fmt.Errorf("обновление участников: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Tender{}, err
+	}
+	current.Status = tender.StatusCancelled
+
+	lots := tenderLots(queries, tenderID)
+	for _, lot := range lots {
+		stopLotTimer(lot.ID)
+	}
+
+	message := fmt.Sprintf(
+		"❌ *Тендер отменён организатором*\n\n"+
+			"📋 *Тендер:* %s\n"+
+			"❗ *Причина:* %s",
+		current.Title,
+		escapeMarkdown(reason),
+	)
+	for _, participantID := range participants {
+		msg, err := bot.Send(&telebot.User{ID: participantID}, message, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
+		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %d: %v\n", participantID, err)
+			continue
+		}
+		MessageManagerOperator.AddMessage(participantID, msg.ID)
+	}
+
+	fmt.Printf("Тендер %d отменён пользователем %d, уведомлено участников: %d\n", tenderID, actorID, len(participants))
+	return current, nil
+}
+
+// confirmTenderCancel отменяет тендер по введённой причине и отвечает организатору
+func confirmTenderCancel(c telebot.Context, queries *db.Queries, tenderID int32, reason string) error {
+	cancelled, err := cancelTender(c.Bot(), queries, tenderID, c.Sender().ID, reason)
+	var transitionErr *tender.TransitionError
+	if errors.Is(err, tender.ErrStatusChanged) || errors.As(err, &transitionErr) {
+		return c.Send("❌ Тендер уже завершён или отменён", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+	if err != nil {
+		fmt.Printf("Ошибка при отмене тендера %d: %v\n", tenderID, err)
+		return c.Send("❌ Не удалось отменить тендер", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	return c.Send(fmt.Sprintf("✅ Тендер «%s» отменён. Участники получили уведомление с причиной, ставки и история сохранены.", cancelled.Title), &telebot.SendOptions{
+		ReplyMarkup: menu.MenuOrganizer,
+	})
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"tender_bot_go/db"
+	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"tender_bot_go/tender"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+)
+
+// Поля тендера, которые организатор может изменить до начала торгов
+const (
+	editFieldTitle       = "title"
+	editFieldDescription = "description"
+	editFieldStartDate   = "start_date"
+	editFieldEndDate     = "end_date"
+	editFieldExtension   = "extension"
+	editFieldConditions  = "conditions"
+)
+
+var editFieldNames = map[string]string{
+	editFieldTitle:       "Название",
+	editFieldDescription: "Описание",
+	editFieldStartDate:   "Дата начала",
+	editFieldEndDate:     "Срок окончания",
+	editFieldExtension:   "Продление",
+	editFieldConditions:  "Файл условий",
+}
+
+// isTenderEditable сообщает, можно ли ещё редактировать тендер: торги не начались
+func isTenderEditable(status string) bool {
+	return status == tender.StatusPendingApproval || status == tender.StatusActivePending
+}
+
+// editableTender загружает тендер организатора, который ещё можно редактировать
+func editableTender(ctx context.Context, queries *db.Queries, tenderID int32, userID int64) (db.Tender, error) {
+	current, err := queries.GetTender(ctx, tenderID)
+	if err != nil {
+		return db.Tender{}, err
+	}
+	if current.OrganizerID.Valid && current.OrganizerID != organizerOwnerID(userID) {
+		return db.Tender{}, pgx.ErrNoRows
+	}
+	if !isTenderEditable(current.Status) {
+		return db.Tender{}, tender.ErrStatusChanged
+	}
+	return current, nil
+}
+
+// handleEditTender показывает организатору поля тендера, которые можно изменить
+func handleEditTender(c telebot.Context, queries *db.Queries) error {
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Редактировать тендеры может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := editableTender(ctx, queries, int32(tenderID), userID)
+	if errors.Is(err, tender.ErrStatusChanged) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Торги уже начались, тендер нельзя изменить",
+			ShowAlert: true,
+		})
+	}
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не найден среди ваших тендеров",
+			ShowAlert: true,
+		})
+	}
+
+	fields := []string{editFieldTitle, editFieldDescription, editFieldStartDate, editFieldEndDate}
+	if current.Type == db.TenderTypeOpen && current.EndAt.Valid {
+		fields = append(fields, editFieldExtension)
+	}
+	fields = append(fields, editFieldConditions)
+
+	var keyboard [][]telebot.InlineButton
+	for _, field := range fields {
+		keyboard = append(keyboard, []telebot.InlineButton{
+			{
+				Unique: "edit_tender_field",
+				Text:   editFieldNames[field],
+				Data:   fmt.Sprintf("%d|%s", current.ID, field),
+			},
+		})
+	}
+
+	message := fmt.Sprintf("✏️ *Редактирование тендера «%s»*\n\nВыберите, что изменить:", current.Title)
+	if current.Status == tender.StatusActivePending {
+		message += "\n\n⚠️ Тендер уже одобрен: после изменения он снова уйдёт на модерацию, а участники получат уведомление."
+	}
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{
+			InlineKeyboard: keyboard,
+		},
+	})
+}
+
+// handleEditTenderField запоминает выбранное поле и спрашивает новое значение
+func handleEditTenderField(c telebot.Context, queries *db.Queries) error {
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 || editFieldNames[parts[1]] == "" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверные данные",
+			ShowAlert: true,
+		})
+	}
+	tenderID, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+	field := parts[1]
+
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Редактировать тендеры может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := editableTender(ctx, queries, int32(tenderID), userID)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер больше нельзя изменить",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateEditValue)}
+	conv.Put("edit_tender_id", strconv.Itoa(int(current.ID)))
+	conv.Put("edit_field", field)
+	saveConversation(userID, state.FlowOrganizer, conv)
+
+	location := dbLocation(queries)
+	var prompt string
+	switch field {
+	case editFieldTitle:
+		prompt = fmt.Sprintf("Сейчас: %s\n\nВведите новое название тендера:", current.Title)
+	case editFieldDescription:
+		prompt = fmt.Sprintf("Сейчас: %s\n\nВведите новое описание тендера:", current.Description.String)
+	case editFieldStartDate:
+		prompt = fmt.Sprintf("Сейчас: %s\n\nВведите новую дату и время начала в формате ДД.ММ.ГГГГ ЧЧ:ММ:", formatTenderTime(current.StartAt, location))
+	case editFieldEndDate:
+		prompt = fmt.Sprintf("Сейчас: %s\n\nВведите новый срок окончания в формате ДД.ММ.ГГГГ ЧЧ:ММ", formatTenderTime(current.EndAt, location))
+		if current.Type == db.TenderTypeOpen {
+			prompt += " или отправьте 'нет', чтобы торги завершались только через 5 минут без новых ставок"
+		}
+		prompt += ":"
+	case editFieldExtension:
+		prompt = fmt.Sprintf("Сейчас: %d мин.\n\nВведите продление в минутах от 0 до %d (0 — без продления):", current.ExtensionMinutes, tender.MaxExtensionMinutes)
+	case editFieldConditions:
+		prompt = "Прикрепите новый файл с условиями или отправьте 'нет', чтобы убрать файл:"
+	}
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(prompt, &telebot.SendOptions{
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// formatTenderTime форматирует дату тендера или сообщает, что она не задана
+func formatTenderTime(t pgtype.Timestamptz, location *time.Location) string {
+	if !t.Valid {
+		return "не указан"
+	}
+	return t.Time.In(location).Format("02.01.2006 15:04")
+}
+
+// applyTenderEdit проверяет новое значение поля теми же правилами, что и мастер
+// создания, и сохраняет его. Одобренный тендер возвращается на модерацию, а
+// вступившие участники получают список изменений.
+func applyTenderEdit(c telebot.Context, queries *db.Queries, conv state.Conversation, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userID := c.Sender().ID
+	tenderID, _ := strconv.ParseInt(conv.Get("edit_tender_id"), 10, 32)
+	field := conv.Get("edit_field")
+
+	current, err := editableTender(ctx, queries, int32(tenderID), userID)
+	if err != nil {
+		clearConversation(userID, state.FlowOrganizer)
+		return c.Send("❌ Тендер больше нельзя изменить: торги уже начались или тендер удалён.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	params := db.UpdateTenderDetailsParams{
+		ID:               current.ID,
+		Title:            current.Title,
+		Description:      current.Description,
+		StartAt:          current.StartAt,
+		EndAt:            current.EndAt,
+		ExtensionMinutes: current.ExtensionMinutes,
+		ConditionsPath:   current.ConditionsPath,
+	}
+
+	location := dbLocation(queries)
+	retry := func(message string) error {
+		return c.Send(message, &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	noValue := value == "нет" || value == "Нет"
+
+	var change string
+	switch field {
+	case editFieldTitle:
+		if err := tender.ValidateTitle(value); err != nil {
+			return retry(err.Error() + ". Введите название тендера:")
+		}
+		params.Title = value
+		change = fmt.Sprintf("%s → %s", current.Title, value)
+	case editFieldDescription:
+		params.Description = pgtype.Text{String: value, Valid: true}
+		change = value
+	case editFieldStartDate:
+		startAt, err := time.ParseInLocation("02.01.2006 15:04", value, location)
+		if err != nil {
+			return retry("Введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ, например: 25.12.2024 14:30")
+		}
+		if err := tender.ValidateStartAt(startAt, time.Now()); err != nil {
+			return retry(err.Error() + "!")
+		}
+		var endAt time.Time
+		if current.EndAt.Valid {
+			endAt = current.EndAt.Time
+		}
+		if err := tender.ValidateEndAt(current.Type, startAt, endAt); err != nil {
+			return retry(err.Error() + ". Сначала измените срок окончания или введите более раннюю дату начала:")
+		}
+		params.StartAt = pgtype.Timestamptz{Time: startAt, Valid: true}
+		change = fmt.Sprintf("%s → %s", formatTenderTime(current.StartAt, location), formatTenderTime(params.StartAt, location))
+	case editFieldEndDate:
+		var endAt time.Time
+		if !noValue || current.Type == db.TenderTypeSealed {
+			endAt, err = time.ParseInLocation("02.01.2006 15:04", value, location)
+			if err != nil {
+				return retry("Введите дату и время в формате ДД.ММ.ГГГГ ЧЧ:ММ, например: 25.12.2024 18:00")
+			}
+		}
+		if err := tender.ValidateEndAt(current.Type, current.StartAt.Time, endAt); err != nil {
+			return retry(err.Error() + "!")
+		}
+		params.EndAt = pgtype.Timestamptz{Time: endAt, Valid: !endAt.IsZero()}
+		// Без срока окончания продлевать нечего
+		if endAt.IsZero() {
+			params.ExtensionMinutes = 0
+		}
+		change = fmt.Sprintf("%s → %s", formatTenderTime(current.EndAt, location), formatTenderTime(params.EndAt, location))
+	case editFieldExtension:
+		minutes, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return retry("Введите количество минут целым числом, например: 5")
+		}
+		if err := tender.ValidateExtension(current.Type, current.EndAt.Time, int32(minutes)); err != nil {
+			return retry(err.Error() + "!")
+		}
+		params.ExtensionMinutes = int32(minutes)
+		change = fmt.Sprintf("%d → %d мин.", current.ExtensionMinutes, minutes)
+	case editFieldConditions:
+		if noValue {
+			params.ConditionsPath = pgtype.Text{}
+			change = "файл убран"
+		} else if c.Message().Document != nil {
+			params.ConditionsPath = pgtype.Text{String: value, Valid: true}
+			change = "загружен новый файл"
+		} else {
+			return retry("Пожалуйста, отправьте файл или напишите 'нет'.")
+		}
+	default:
+		clearConversation(userID, state.FlowOrganizer)
+		return nil
+	}
+
+	// Одобренный тендер возвращается на модерацию в одной транзакции с изменением:
+	// если торги уже начались, переход не выполнится и изменения не сохранятся
+	var updated db.Tender
+	backToApproval := current.Status == tender.StatusActivePending
+	if backToApproval {
+		reason := fmt.Sprintf("изменён организатором: %s", strings.ToLower(editFieldNames[field]))
+		err = tender.TransitionWith(ctx, queries, current.ID, tender.StatusActivePending, tender.StatusPendingApproval, userID, reason, func(q *db.Queries) error {
+			var err error
+			updated, err = q.UpdateTenderDetails(ctx, params)
+			return err
+		})
+		if errors.Is(err, tender.ErrStatusChanged) {
+			clearConversation(userID, state.FlowOrganizer)
+			return c.Send("❌ Тендер больше нельзя изменить: торги уже начались.", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizer,
+			})
+		}
+	} else {
+		updated, err = queries.UpdateTenderDetails(ctx, params)
+	}
+	if err != nil {
+		fmt.Printf("Ошибка при изменении тендера %d: %v\n", current.ID, err)
+		clearConversation(userID, state.FlowOrganizer)
+		return c.Send("❌ Не удалось сохранить изменения", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+	clearConversation(userID, state.FlowOrganizer)
+
+	if current.ConditionsPath.Valid && current.ConditionsPath != updated.ConditionsPath {
+		removeUnusedConditionsFile(queries, current.ConditionsPath)
+	}
+
+	// Одобренный тендер снова попадает к админам; у тендера на модерации карточка
+	// уже есть, а одобряется всегда текущая версия из базы
+	if backToApproval {
+		go sendTenderApprovalNotification(c.Bot(), queries, updated, tenderLots(queries, updated.ID))
+	}
+	go notifyTenderEdited(c.Bot(), queries, updated, editFieldNames[field], change, backToApproval)
+
+	message := fmt.Sprintf("✅ *Тендер «%s» изменён*\n\n%s: %s", updated.Title, editFieldNames[field], change)
+	if backToApproval {
+		message += "\n\n⏳ Тендер снова отправлен на модерацию, участники получили уведомление об изменениях."
+	}
+	return c.Send(message, &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizer,
+	})
+}
+
+// removeUnusedConditionsFile удаляет заменённый файл условий, если на него не ссылается
+// ни один тендер или шаблон: раньше копии тендеров делили файл с исходным
+func removeUnusedConditionsFile(queries *db.Queries, path pgtype.Text) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	inUse, err := queries.IsConditionsFileInUse(ctx, path)
+	if err != nil {
+		fmt.Printf("Ошибка проверки файла условий %s: %v\n", path.String, err)
+		return
+	}
+	if inUse {
+		return
+	}
+	if err := os.Remove(path.String); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Ошибка удаления файла условий %s: %v\n", path.String, err)
+	}
+}
+
+// notifyTenderEdited сообщает вступившим в тендер поставщикам, что организатор изменил условия
+func notifyTenderEdited(bot *telebot.Bot, queries *db.Queries, updated db.Tender, fieldName, change string, backToApproval bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	participants, err := queries.GetParticipantsForTender(ctx, updated.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения участников тендера %d: %v\n", updated.ID, err)
+		return
+	}
+
+	message := fmt.Sprintf("✏️ *Организатор изменил тендер*\n\n📋 Тендер: %s\n%s: %s", updated.Title, fieldName, change)
+	if backToApproval {
+		message += "\n\n⏳ Тендер снова проходит проверку администратора."
+	}
+	for _, participantID := range participants {
+		msg, err := bot.Send(&telebot.User{ID: participantID}, message, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
+		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления пользователю %d: %v\n", participantID, err)
+			continue
+		}
+		MessageManagerOperator.AddMessage(participantID, msg.ID)
+	}
+}
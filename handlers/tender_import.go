@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"tender_bot_go/db"
+	"tender_bot_go/importer"
+	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// maxImportFileSize — наибольший размер файла импорта тендеров
+const maxImportFileSize = 1 << 20
+
+// maxImportErrors — сколько ошибок по строкам показывать в отчёте об импорте
+const maxImportErrors = 30
+
+// startTenderImport просит организатора прислать файл с тендерами и описывает его формат
+func startTenderImport(c telebot.Context, userID int64) error {
+	saveConversation(userID, state.FlowOrganizer, state.Conversation{Step: int(StateImportFile)})
+
+	var columns strings.Builder
+	for i, column := range importer.Columns {
+		fmt.Fprintf(&columns, "%d. %s\n", i+1, column)
+	}
+
+	return c.Send(fmt.Sprintf(
+		"📥 *Импорт тендеров из файла*\n\n"+
+			"Пришлите файл CSV или XLSX, в котором каждая строка — открытый тендер с одним лотом. Колонки по порядку:\n\n"+
+			"%s\n"+
+			"Классификацию можно указать кодом или названием. Строка заголовка необязательна, в файле может быть до %d тендеров.",
+		columns.String(),
+		importer.MaxRows,
+	), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// classificationResolver находит код действующей классификации по коду или названию из файла
+func classificationResolver(queries *db.Queries) importer.ClassificationResolver {
+	classifications := activeClassifications(queries)
+	return func(value string) (string, bool) {
+		for _, classification := range classifications {
+			if classification.Code == value || strings.EqualFold(classification.Name, value) {
+				return classification.Code, true
+			}
+		}
+		return "", false
+	}
+}
+
+// handleTenderImport разбирает присланный файл, создаёт одним пакетом тендеры из
+// строк без ошибок и сообщает организатору, какие строки пропущены и почему
+func handleTenderImport(c telebot.Context, queries *db.Queries, userID int64) error {
+	doc := c.Message().Document
+	if doc == nil || !importer.IsSupported(doc.FileName) {
+		return c.Send("❌ "+importer.ErrUnsupportedFormat.Error()+". Пришлите другой файл:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	if doc.FileSize > maxImportFileSize {
+		return c.Send("❌ Файл слишком большой, допускается не больше 1 МБ. Разделите его на части:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	reader, err := c.Bot().File(&doc.File)
+	if err != nil {
+		fmt.Printf("Ошибка получения файла от Telegram: %v\n", err)
+		return c.Send("Не удалось прочитать файл.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxImportFileSize+1))
+	if err != nil {
+		fmt.Printf("Ошибка чтения файла импорта: %v\n", err)
+		return c.Send("Не удалось прочитать файл.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	if len(data) > maxImportFileSize {
+		return c.Send("❌ Файл слишком большой, допускается не больше 1 МБ. Разделите его на части:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	sheet, err := importer.ReadRows(doc.FileName, data)
+	if err != nil {
+		return c.Send("❌ "+err.Error()+". Пришлите другой файл:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	rows, err := importer.ParseRows(sheet, time.Now(), dbLocation(queries), userID, classificationResolver(queries))
+	if err != nil {
+		return c.Send("❌ "+err.Error()+". Пришлите другой файл:", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+
+	var batch []db.TenderWithLotsParams
+	var rowErrors []string
+	for _, row := range rows {
+		if row.Err != nil {
+			rowErrors = append(rowErrors, fmt.Sprintf("Строка %d: %s", row.Line, row.Err.Error()))
+			continue
+		}
+		batch = append(batch, db.TenderWithLotsParams{
+			Tender:          row.Draft.CreateParams(),
+			Lots:            row.Draft.LotParams(),
+			Classifications: row.Draft.ClassificationCodes(),
+		})
+	}
+
+	var created []db.Tender
+	if len(batch) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		created, err = queries.CreateTendersWithLots(ctx, batch)
+		if err != nil {
+			fmt.Printf("Ошибка импорта тендеров пользователя %d: %v\n", userID, err)
+			return c.Send("❌ Не удалось сохранить тендеры, ни один из них не создан. Попробуйте ещё раз:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+	}
+
+	clearConversation(userID, state.FlowOrganizer)
+	fmt.Printf("Импорт тендеров пользователя %d: создано %d из %d\n", userID, len(created), len(rows))
+
+	if len(created) > 0 {
+		go notifyImportedTenders(c.Bot(), queries, created)
+	}
+
+	report := fmt.Sprintf("📥 Импорт завершён: создано тендеров %d из %d.", len(created), len(rows))
+	if len(created) > 0 {
+		report += "\nСозданные тендеры отправлены на одобрение администратору."
+	}
+	if len(rowErrors) > 0 {
+		shown := rowErrors
+		if len(shown) > maxImportErrors {
+			shown = shown[:maxImportErrors]
+		}
+		report += "\n\nПропущенные строки:\n" + strings.Join(shown, "\n")
+		if len(rowErrors) > len(shown) {
+			report += fmt.Sprintf("\n…и ещё %d", len(rowErrors)-len(shown))
+		}
+	}
+
+	return c.Send(report, &telebot.SendOptions{
+		ReplyMarkup: menu.MenuOrganizer,
+	})
+}
+
+// notifyImportedTenders по очереди отправляет админам запросы на одобрение
+// импортированных тендеров, чтобы не упереться в ограничения Telegram
+func notifyImportedTenders(bot *telebot.Bot, queries *db.Queries, created []db.Tender) {
+	for _, t := range created {
+		sendTenderApprovalNotification(bot, queries, t, tenderLots(queries, t.ID))
+		time.Sleep(500 * time.Millisecond)
+	}
+}
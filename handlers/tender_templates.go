@@ -0,0 +1,443 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tender_bot_go/db"
+	"tender_bot_go/menu"
+	"tender_bot_go/state"
+	"tender_bot_go/tender"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// keepPriceAnswer — ответ, которым организатор оставляет прежнюю стартовую цену лота
+const keepPriceAnswer = "-"
+
+// handleSaveTemplate начинает сохранение тендера как шаблона: спрашивает название шаблона
+func handleSaveTemplate(c telebot.Context, queries *db.Queries) error {
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Сохранять шаблоны может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	original, err := queries.GetTender(ctx, int32(tenderID))
+	if err != nil || (original.OrganizerID.Valid && original.OrganizerID != organizerOwnerID(userID)) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не найден среди ваших тендеров",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateTemplateName)}
+	conv.Put("template_tender_id", strconv.Itoa(int(original.ID)))
+	saveConversation(userID, state.FlowOrganizer, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(fmt.Sprintf("💾 *Шаблон по тендеру «%s»*\n\nВведите название шаблона, например «Краска, ежеквартально»:", original.Title), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// saveTenderTemplate сохраняет тендер как шаблон с указанным названием. Файл условий
+// копируется в FilesDir, чтобы шаблон не зависел от исходного тендера.
+func saveTenderTemplate(c telebot.Context, queries *db.Queries, tenderID int32, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userID := c.Sender().ID
+	original, err := queries.GetTender(ctx, tenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения тендера %d для шаблона: %v\n", tenderID, err)
+		return c.Send("❌ Тендер для шаблона не найден.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	templates, err := queries.GetOrganizerTemplates(ctx, userID)
+	if err != nil {
+		fmt.Printf("Ошибка получения шаблонов организатора %d: %v\n", userID, err)
+	}
+	for _, existing := range templates {
+		if strings.EqualFold(existing.Name, strings.TrimSpace(name)) {
+			return c.Send("Шаблон с таким названием уже есть. Введите другое название:", &telebot.SendOptions{
+				ReplyMarkup: menu.MenuOrganizerCancel,
+			})
+		}
+	}
+
+	// Без копии файла шаблон всё равно сохраняется, но организатор узнаёт об этом
+	conditionsPath, copyErr := copyConditionsFile(original.ConditionsPath.String, filepath.Join(config.FilesDir, "templates"))
+	if copyErr != nil {
+		fmt.Printf("Ошибка копирования файла условий для шаблона: %v\n", copyErr)
+	}
+	params, lotParams := tender.TemplateParams(name, original, tenderLots(queries, original.ID), tenderClassificationCodes(queries, original.ID), userID, conditionsPath)
+
+	template, lots, err := queries.CreateTemplateWithLots(ctx, params, lotParams)
+	clearConversation(userID, state.FlowOrganizer)
+	if err != nil {
+		if conditionsPath != "" {
+			os.Remove(conditionsPath)
+		}
+		fmt.Printf("Ошибка при сохранении шаблона: %v\n", err)
+		return c.Send("❌ Не удалось сохранить шаблон", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	message := fmt.Sprintf("✅ Шаблон «%s» сохранён: лотов — %d.", template.Name, len(lots))
+	if template.ConditionsPath.Valid {
+		message += " Файл условий сохранён вместе с шаблоном."
+	}
+	if copyErr != nil {
+		message += "\n\n⚠️ Файл условий скопировать не удалось, шаблон сохранён без него. Приложить файл к тендеру по шаблону можно через «Редактировать» в «Мои тендеры»."
+	}
+	return c.Send(message+"\n\nСоздать тендер по шаблону можно в разделе «Шаблоны».", &telebot.SendOptions{
+		ReplyMarkup: menu.MenuOrganizer,
+	})
+}
+
+// copyConditionsFile копирует файл условий в каталог dir и возвращает новый путь.
+// Пустой src означает тендер без файла. При ошибке недописанная копия удаляется.
+func copyConditionsFile(src, dir string) (string, error) {
+	if src == "" {
+		return "", nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("файл условий %s недоступен: %w", src, err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("создание директории: %w", err)
+	}
+
+	dst := filepath.Join(dir, fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(src)))
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", fmt.Errorf("создание файла: %w", err)
+	}
+
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return "", fmt.Errorf("копирование файла: %w", err)
+	}
+	return dst, nil
+}
+
+// copyDraftConditions заменяет файл условий в черновике собственной копией, чтобы новый
+// тендер не делил файл с исходным тендером или шаблоном. Если скопировать не удалось,
+// тендер создаётся без файла, а возвращённое предупреждение показывается организатору.
+func copyDraftConditions(draft *tender.Draft) string {
+	conditionsPath, err := copyConditionsFile(draft.ConditionsPath, config.FilesDir)
+	draft.ConditionsPath = conditionsPath
+	if err != nil {
+		fmt.Printf("Ошибка копирования файла условий: %v\n", err)
+		return "\n\n⚠️ Файл условий скопировать не удалось, тендер создан без него. Приложить файл можно через «Редактировать» в «Мои тендеры»."
+	}
+	return ""
+}
+
+// sendOrganizerTemplates показывает шаблоны организатора с кнопками создания тендера
+func sendOrganizerTemplates(c telebot.Context, queries *db.Queries) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	templates, err := queries.GetOrganizerTemplates(ctx, c.Sender().ID)
+	if err != nil {
+		fmt.Printf("Ошибка при получении шаблонов: %v\n", err)
+		return c.Send("❌ Не удалось загрузить шаблоны", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	if len(templates) == 0 {
+		return c.Send("📭 Шаблонов пока нет. Сохраните тендер кнопкой «💾 Сохранить как шаблон» в разделе «Мои тендеры».", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	for _, template := range templates {
+		lots, err := queries.GetTenderTemplateLots(ctx, template.ID)
+		if err != nil {
+			fmt.Printf("Ошибка получения лотов шаблона %d: %v\n", template.ID, err)
+		}
+
+		var lotsText string
+		for _, lot := range lots {
+			lotsText += fmt.Sprintf("📦 Лот №%d: %s — %s руб.\n", lot.Number, lot.Title, formatPriceFloat(lot.StartPrice))
+		}
+
+		conditions := "📭 Файл условий не прикреплен"
+		if template.ConditionsPath.Valid {
+			conditions = "📎 Файл условий: " + filepath.Base(template.ConditionsPath.String)
+		}
+
+		templateInfo := fmt.Sprintf(
+			"📄 *Шаблон:* %s\n\n"+
+				"📋 *Название тендера:* %s\n"+
+				"📝 *Описание:* %s\n"+
+				"%s"+
+				"%s",
+			template.Name,
+			template.Title,
+			template.Description.String,
+			lotsText,
+			conditions,
+		)
+
+		_, err = c.Bot().Send(c.Sender(), templateInfo, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
+			ReplyMarkup: &telebot.ReplyMarkup{
+				InlineKeyboard: [][]telebot.InlineButton{
+					{
+						{Unique: "use_template", Text: "🆕 Создать тендер", Data: strconv.Itoa(int(template.ID))},
+						{Unique: "delete_template", Text: "🗑️ Удалить", Data: strconv.Itoa(int(template.ID))},
+					},
+				},
+			},
+		})
+		if err != nil {
+			fmt.Printf("Ошибка при отправке шаблона: %v\n", err)
+			continue
+		}
+
+		// Небольшая задержка между отправками чтобы не превысить лимиты Telegram
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return c.Send(fmt.Sprintf("✅ Всего шаблонов: %d", len(templates)), &telebot.SendOptions{
+		ReplyMarkup: menu.MenuOrganizer,
+	})
+}
+
+func handleDeleteTemplate(c telebot.Context, queries *db.Queries) error {
+	templateID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID шаблона",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deleted, err := queries.DeleteOrganizerTemplate(ctx, db.DeleteOrganizerTemplateParams{
+		ID:          int32(templateID),
+		OrganizerID: c.Sender().ID,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка при удалении шаблона: %v\n", err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось удалить шаблон",
+			ShowAlert: true,
+		})
+	}
+	if deleted == 0 {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Шаблон не найден среди ваших шаблонов",
+			ShowAlert: true,
+		})
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: "✅ Шаблон удалён"}); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Edit(c.Message().Text + "\n\n🗑️ Шаблон удалён")
+}
+
+// handleUseTemplate начинает создание тендера по шаблону
+func handleUseTemplate(c telebot.Context, queries *db.Queries) error {
+	templateID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID шаблона",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userID := c.Sender().ID
+	template, err := queries.GetTenderTemplate(ctx, int32(templateID))
+	if err != nil || template.OrganizerID != userID {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Шаблон не найден среди ваших шаблонов",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateCloneStartDate)}
+	conv.Put("template_id", strconv.Itoa(int(template.ID)))
+	return startClone(c, conv, fmt.Sprintf("🆕 *Тендер по шаблону «%s»*", template.Name))
+}
+
+// handleCloneTender начинает создание тендера по образцу прошедшего тендера из истории
+func handleCloneTender(c telebot.Context, queries *db.Queries) error {
+	tenderID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Ошибка: неверный ID тендера",
+			ShowAlert: true,
+		})
+	}
+
+	userID := c.Sender().ID
+	if getUserRole(userID, queries) != "organizer" {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Создавать тендеры может только организатор",
+			ShowAlert: true,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	original, err := queries.GetTender(ctx, int32(tenderID))
+	if err != nil || (original.OrganizerID.Valid && original.OrganizerID != organizerOwnerID(userID)) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Тендер не найден среди ваших тендеров",
+			ShowAlert: true,
+		})
+	}
+
+	conv := state.Conversation{Step: int(StateCloneStartDate)}
+	conv.Put("clone_tender_id", strconv.Itoa(int(original.ID)))
+	return startClone(c, conv, fmt.Sprintf("🔁 *Новый тендер по образцу «%s»*", original.Title))
+}
+
+func startClone(c telebot.Context, conv state.Conversation, headline string) error {
+	saveConversation(c.Sender().ID, state.FlowOrganizer, conv)
+
+	if err := c.Respond(); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	return c.Send(headline+"\n\nУсловия, лоты и файл условий будут взяты как есть. Введите дату и время начала в формате ДД.ММ.ГГГГ ЧЧ:ММ:", &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizerCancel,
+	})
+}
+
+// cloneDraft собирает черновик нового тендера из шаблона или прошедшего тендера,
+// выбранного в диалоге, с введёнными датой начала и ценами лотов
+func cloneDraft(queries *db.Queries, conv state.Conversation, userID int64) (tender.Draft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	startAt, _ := time.Parse(time.RFC3339, conv.Get("start_date_parsed"))
+
+	var draft tender.Draft
+	if conv.Get("template_id") != "" {
+		templateID, _ := strconv.ParseInt(conv.Get("template_id"), 10, 32)
+		template, err := queries.GetTenderTemplate(ctx, int32(templateID))
+		if err != nil {
+			return tender.Draft{}, err
+		}
+		lots, err := queries.GetTenderTemplateLots(ctx, template.ID)
+		if err != nil {
+			return tender.Draft{}, err
+		}
+		draft = tender.TemplateDraft(template, lots, startAt)
+	} else {
+		tenderID, _ := strconv.ParseInt(conv.Get("clone_tender_id"), 10, 32)
+		original, err := queries.GetTender(ctx, int32(tenderID))
+		if err != nil {
+			return tender.Draft{}, err
+		}
+		// Копия тендера — тот же перезапуск, только без повышения цены
+		draft = tender.RelaunchDraft(original, tenderLots(queries, original.ID), tenderClassificationCodes(queries, original.ID), startAt, 0)
+	}
+	draft.OrganizerID = userID
+
+	for i := range draft.Lots {
+		if price := conv.Get(fmt.Sprintf("clone_price_%d", i)); price != "" && price != keepPriceAnswer {
+			draft.Lots[i].StartPrice, _ = strconv.ParseFloat(price, 64)
+		}
+	}
+	return draft, nil
+}
+
+// clonePricePrompt спрашивает стартовую цену очередного лота нового тендера
+func clonePricePrompt(draft tender.Draft, index int) string {
+	lot := draft.Lots[index]
+	return fmt.Sprintf("Введите стартовую цену лота №%d «%s» в рублях (было %s руб.) или отправьте «%s», чтобы оставить прежнюю:",
+		index+1, lot.Title, formatPriceFloat(lot.StartPrice), keepPriceAnswer)
+}
+
+// createClonedTender создаёт тендер по черновику из шаблона или истории и отправляет его на модерацию
+func createClonedTender(c telebot.Context, queries *db.Queries, draft tender.Draft) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userID := c.Sender().ID
+	if err := draft.Validate(time.Now()); err != nil {
+		clearConversation(userID, state.FlowOrganizer)
+		return c.Send("❌ "+err.Error()+". Создание тендера отменено.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizer,
+		})
+	}
+
+	warning := copyDraftConditions(&draft)
+	created, createdLots, err := queries.CreateTenderWithLots(ctx, draft.CreateParams(), draft.LotParams(), draft.ClassificationCodes())
+	if err != nil {
+		if draft.ConditionsPath != "" {
+			os.Remove(draft.ConditionsPath)
+		}
+		fmt.Printf("Ошибка при создании тендера по образцу: %v\n", err)
+		return c.Send("Ошибка при сохранении данных в БД. Попробуйте снова.", &telebot.SendOptions{
+			ReplyMarkup: menu.MenuOrganizerCancel,
+		})
+	}
+	clearConversation(userID, state.FlowOrganizer)
+
+	go sendTenderApprovalNotification(c.Bot(), queries, created, createdLots)
+
+	return c.Send(fmt.Sprintf(
+		"✅ *Тендер создан и отправлен на модерацию!*\n\n"+
+			"📋 *Название:* %s\n"+
+			"💰 *Стартовая цена:* %s руб.\n"+
+			"%s"+
+			"📅 *Дата начала:* %s\n\n"+
+			"⏳ *Ожидайте одобрения администратора*"+
+			"%s",
+		created.Title,
+		formatPriceFloat(created.StartPrice),
+		formatTenderTerms(created, createdLots),
+		created.StartAt.Time.In(draft.StartAt.Location()).Format("02.01.2006 15:04"),
+		warning,
+	), &telebot.SendOptions{
+		ParseMode:   telebot.ModeMarkdown,
+		ReplyMarkup: menu.MenuOrganizer,
+	})
+}
@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"tender_bot_go/db"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/telebot.v3"
+)
+
+// Подтверждение победы: после окончания торгов лот предлагается участнику с лучшей
+// ставкой. Если он отказывается или не отвечает за config.WinnerConfirmTimeout, лот
+// предлагается следующему по ставке участнику. Каждый шаг записывается в историю,
+// организатор получает уведомление о каждом шаге.
+
+// offerLotToNextBidder предлагает лот лучшему из участников, которым он ещё не
+// предлагался. Вышедшие из тендера участники в очередь не попадают. Если таких не
+// осталось, торги по лоту признаются несостоявшимися.
+func offerLotToNextBidder(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ranking, err := queries.GetLotBidRanking(ctx, lot.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения рейтинга ставок лота %d: %v\n", lot.ID, err)
+		return
+	}
+
+	offers, err := queries.GetLotWinnerOffers(ctx, lot.ID)
+	if err != nil {
+		fmt.Printf("Ошибка получения предложений по лоту %d: %v\n", lot.ID, err)
+		return
+	}
+
+	offered := make(map[int64]bool, len(offers))
+	for _, offer := range offers {
+		offered[offer.UserID] = true
+	}
+
+	for _, candidate := range ranking {
+		if offered[candidate.UserID] {
+			continue
+		}
+
+		offer, err := queries.CreateWinnerOffer(ctx, db.CreateWinnerOfferParams{
+			TenderID: tender.ID,
+			LotID:    lot.ID,
+			UserID:   candidate.UserID,
+			Amount:   candidate.Amount,
+			Rank:     int32(len(offers) + 1),
+			ExpiresAt: pgtype.Timestamptz{
+				Time:  time.Now().Add(config.WinnerConfirmTimeout),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			fmt.Printf("Ошибка создания предложения по лоту %d участнику %d: %v\n", lot.ID, candidate.UserID, err)
+			return
+		}
+
+		sendWinnerOffer(bot, queries, tender, lot, offer)
+		return
+	}
+
+	failLotWithoutWinner(bot, queries, tender, lot)
+}
+
+// sendWinnerOffer отправляет участнику предложение подтвердить победу, а организатору —
+// уведомление о том, кому предложен лот
+func sendWinnerOffer(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot, offer db.WinnerOffer) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	candidate, err := queries.GetUserByTelegramID(ctx, offer.UserID)
+	if err != nil {
+		fmt.Printf("Ошибка получения участника %d: %v\n", offer.UserID, err)
+	}
+
+	addWinnerOfferToHistory(ctx, queries, tender, lot, offer, candidate, db.HistoryOutcomeOffered)
+
+	deadline := offer.ExpiresAt.Time.In(dbLocation(queries)).Format("02.01.2006 15:04")
+
+	header := "🏆 *Вы победили в торгах по лоту!*"
+	if offer.Rank > 1 {
+		header = "📨 *Вам предложен лот*\n\nУчастники с лучшими ставками отказались от заключения договора."
+	}
+	message := fmt.Sprintf(
+		"%s\n\n"+
+			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
+			"💎 Ваша ставка: %s руб.\n\n"+
+			"✍️ Подтвердите готовность заключить договор до %s.\n"+
+			"Если не ответить до этого срока, лот будет предложен следующему участнику.",
+		header,
+		tender.Title,
+		lotLabel(lot),
+		formatPriceFloat(offer.Amount),
+		deadline,
+	)
+
+	offerID := strconv.Itoa(int(offer.ID))
+	msg, err := bot.Send(&telebot.User{ID: offer.UserID}, message, &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+		ReplyMarkup: &telebot.ReplyMarkup{
+			InlineKeyboard: [][]telebot.InlineButton{
+				{
+					{Unique: "winner_accept", Text: "✅ Подтвердить", Data: offerID},
+					{Unique: "winner_decline", Text: "❌ Отказаться", Data: offerID},
+				},
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Ошибка отправки предложения участнику %d: %v\n", offer.UserID, err)
+	} else {
+		MessageManagerOperator.AddMessage(offer.UserID, msg.ID)
+	}
+
+	notifyLotOrganizers(bot, queries, tender, fmt.Sprintf(
+		"📨 *Лот предложен участнику*\n\n"+
+			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
+			"👤 Участник: %s (%d-е место)\n"+
+			"💰 Ставка: %s руб.\n"+
+			"⏳ Ожидаем подтверждения до %s",
+		tender.Title,
+		lotLabel(lot),
+		candidate.OrganizationName.String,
+		offer.Rank,
+		formatPriceFloat(offer.Amount),
+		deadline,
+	))
+
+	fmt.Printf("Лот %d тендера %d предложен участнику %d (место %d)\n", lot.ID, tender.ID, offer.UserID, offer.Rank)
+}
+
+// handleWinnerAccept — участник подтверждает победу: лот завершается, победитель объявляется
+func handleWinnerAccept(c telebot.Context, queries *db.Queries) error {
+	offer, tender, lot, ok := winnerOfferFromCallback(c, queries)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !offer.ExpiresAt.Time.After(time.Now()) {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "⌛ Срок подтверждения истёк",
+			ShowAlert: true,
+		})
+	}
+
+	// Ответ принимается один раз и только пока лот ждёт подтверждения: повторное
+	// нажатие, истечение срока или отмена тендера не пройдут
+	accepted, err := queries.AcceptWinnerOffer(ctx, offer.ID, lot.ID)
+	if err != nil {
+		fmt.Printf("Ошибка подтверждения предложения %d: %v\n", offer.ID, err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось подтвердить, попробуйте позже",
+			ShowAlert: true,
+		})
+	}
+	if !accepted {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Предложение уже неактуально",
+			ShowAlert: true,
+		})
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: "✅ Победа подтверждена"}); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	if err := c.Edit(c.Message().Text + "\n\n✅ Вы подтвердили победу"); err != nil {
+		fmt.Printf("Ошибка редактирования сообщения: %v\n", err)
+	}
+
+	declareWinner(c.Bot(), queries, tender, lot, offer.UserID, offer.Amount)
+	finishTenderIfAllLotsClosed(c.Bot(), queries, tender.ID)
+	return nil
+}
+
+// handleWinnerDecline — участник отказывается от лота, лот предлагается следующему
+func handleWinnerDecline(c telebot.Context, queries *db.Queries) error {
+	offer, tender, lot, ok := winnerOfferFromCallback(c, queries)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	updated, err := queries.RespondWinnerOffer(ctx, db.RespondWinnerOfferParams{
+		ID:     offer.ID,
+		Status: db.WinnerOfferDeclined,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка отказа от предложения %d: %v\n", offer.ID, err)
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Не удалось отказаться, попробуйте позже",
+			ShowAlert: true,
+		})
+	}
+	if updated == 0 {
+		return c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Предложение уже неактуально",
+			ShowAlert: true,
+		})
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: "Вы отказались от лота"}); err != nil {
+		fmt.Printf("Ошибка ответа на callback: %v\n", err)
+	}
+	if err := c.Edit(c.Message().Text + "\n\n❌ Вы отказались от заключения договора"); err != nil {
+		fmt.Printf("Ошибка редактирования сообщения: %v\n", err)
+	}
+
+	closeWinnerOffer(c.Bot(), queries, tender, lot, offer, db.WinnerOfferDeclined)
+	return nil
+}
+
+// winnerOfferFromCallback загружает предложение из данных кнопки и проверяет, что
+// отвечает тот участник, которому оно адресовано. Ошибки сразу показываются пользователю.
+func winnerOfferFromCallback(c telebot.Context, queries *db.Queries) (db.WinnerOffer, db.Tender, db.TenderLot, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	respondNotFound := func() {
+		err := c.Respond(&telebot.CallbackResponse{
+			Text:      "❌ Предложение не найдено",
+			ShowAlert: true,
+		})
+		if err != nil {
+			fmt.Printf("Ошибка ответа на callback: %v\n", err)
+		}
+	}
+
+	offerID, err := strconv.ParseInt(c.Data(), 10, 32)
+	if err != nil {
+		respondNotFound()
+		return db.WinnerOffer{}, db.Tender{}, db.TenderLot{}, false
+	}
+
+	offer, err := queries.GetWinnerOffer(ctx, int32(offerID))
+	if err != nil || offer.UserID != c.Sender().ID {
+		respondNotFound()
+		return db.WinnerOffer{}, db.Tender{}, db.TenderLot{}, false
+	}
+
+	tender, err := queries.GetTender(ctx, offer.TenderID)
+	if err != nil {
+		respondNotFound()
+		return db.WinnerOffer{}, db.Tender{}, db.TenderLot{}, false
+	}
+
+	lot, err := queries.GetTenderLot(ctx, offer.LotID)
+	if err != nil {
+		respondNotFound()
+		return db.WinnerOffer{}, db.Tender{}, db.TenderLot{}, false
+	}
+
+	return offer, tender, lot, true
+}
+
+// ExpireWinnerOffer закрывает предложение, на которое участник не ответил в срок,
+// и предлагает лот следующему участнику
+func (s *TenderScheduler) ExpireWinnerOffer(offerID int32) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	offer, err := s.queries.GetWinnerOffer(ctx, offerID)
+	if err != nil {
+		fmt.Printf("Ошибка получения предложения %d: %v\n", offerID, err)
+		return
+	}
+
+	updated, err := s.queries.RespondWinnerOffer(ctx, db.RespondWinnerOfferParams{
+		ID:     offer.ID,
+		Status: db.WinnerOfferExpired,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка закрытия просроченного предложения %d: %v\n", offer.ID, err)
+		return
+	}
+	// Участник успел ответить
+	if updated == 0 {
+		return
+	}
+
+	tender, err := s.queries.GetTender(ctx, offer.TenderID)
+	if err != nil {
+		fmt.Printf("Ошибка получения тендера %d: %v\n", offer.TenderID, err)
+		return
+	}
+
+	lot, err := s.queries.GetTenderLot(ctx, offer.LotID)
+	if err != nil {
+		fmt.Printf("Ошибка получения лота %d: %v\n", offer.LotID, err)
+		return
+	}
+
+	_, err = s.bot.Send(&telebot.User{ID: offer.UserID}, fmt.Sprintf(
+		"⌛ *Срок подтверждения истёк*\n\n📋 Тендер: %s\n📦 %s\n\nЛот будет предложен следующему участнику.",
+		tender.Title,
+		lotLabel(lot),
+	), &telebot.SendOptions{
+		ParseMode: telebot.ModeMarkdown,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка отправки уведомления участнику %d: %v\n", offer.UserID, err)
+	}
+
+	closeWinnerOffer(s.bot, s.queries, tender, lot, offer, db.WinnerOfferExpired)
+}
+
+// closeWinnerOffer записывает отказ или истечение срока в историю, сообщает организатору
+// и передаёт лот следующему участнику
+func closeWinnerOffer(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot, offer db.WinnerOffer, outcome string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	candidate, err := queries.GetUserByTelegramID(ctx, offer.UserID)
+	if err != nil {
+		fmt.Printf("Ошибка получения участника %d: %v\n", offer.UserID, err)
+	}
+
+	addWinnerOfferToHistory(ctx, queries, tender, lot, offer, candidate, outcome)
+
+	reason := "❌ *Участник отказался от лота*"
+	if outcome == db.WinnerOfferExpired {
+		reason = "⌛ *Участник не подтвердил победу в срок*"
+	}
+	notifyLotOrganizers(bot, queries, tender, fmt.Sprintf(
+		"%s\n\n"+
+			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
+			"👤 Участник: %s (%d-е место)\n"+
+			"💰 Ставка: %s руб.\n\n"+
+			"Лот будет предложен следующему участнику, если он есть.",
+		reason,
+		tender.Title,
+		lotLabel(lot),
+		candidate.OrganizationName.String,
+		offer.Rank,
+		formatPriceFloat(offer.Amount),
+	))
+
+	fmt.Printf("Предложение %d по лоту %d закрыто: %s\n", offer.ID, lot.ID, outcome)
+
+	offerLotToNextBidder(bot, queries, tender, lot)
+}
+
+// failLotWithoutWinner признаёт торги по лоту несостоявшимися, когда от него
+// отказались все участники со ставками
+func failLotWithoutWinner(bot *telebot.Bot, queries *db.Queries, tender db.Tender, lot db.TenderLot) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := queries.SetLotStatus(ctx, db.SetLotStatusParams{
+		ID:     lot.ID,
+		Status: db.LotStatusFailed,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка обновления статуса лота %d: %v\n", lot.ID, err)
+		return
+	}
+
+	err = queries.AddToHistory(ctx, db.AddToHistoryParams{
+		TenderID:   tender.ID,
+		Title:      tender.Title + " — " + lotLabel(lot),
+		StartPrice: lot.StartPrice,
+		LotID:      lot.ID,
+		Outcome:    db.LotStatusFailed,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка сохранения лота %d в историю: %v\n", lot.ID, err)
+	}
+
+	notifyLotOrganizers(bot, queries, tender, fmt.Sprintf(
+		"⚫ *Торги по лоту не состоялись*\n\n"+
+			"📋 Тендер: %s\n"+
+			"📦 %s\n"+
+			"Все участники со ставками отказались от заключения договора или не ответили в срок.",
+		tender.Title,
+		lotLabel(lot),
+	))
+
+	fmt.Printf("Лот %d тендера %d не состоялся: победитель не подтвердил участие\n", lot.ID, tender.ID)
+
+	finishTenderIfAllLotsClosed(bot, queries, tender.ID)
+}
+
+// addWinnerOfferToHistory записывает шаг подтверждения победы в историю торгов
+func addWinnerOfferToHistory(ctx context.Context, queries *db.Queries, tender db.Tender, lot db.TenderLot, offer db.WinnerOffer, candidate db.User, outcome string) {
+	err := queries.AddToHistory(ctx, db.AddToHistoryParams{
+		TenderID:    tender.ID,
+		Title:       tender.Title + " — " + lotLabel(lot),
+		Winner:      candidate.OrganizationName,
+		PhoneNumber: candidate.PhoneNumber,
+		Inn:         candidate.Inn,
+		Fio:         candidate.Name,
+		Bid:         pgtype.Float8{Float64: offer.Amount, Valid: true},
+		StartPrice:  lot.StartPrice,
+		LotID:       lot.ID,
+		Outcome:     outcome,
+	})
+	if err != nil {
+		fmt.Printf("Ошибка сохранения шага подтверждения по лоту %d в историю: %v\n", lot.ID, err)
+	}
+}
+
+// notifyLotOrganizers отправляет сообщение организатору тендера
+func notifyLotOrganizers(bot *telebot.Bot, queries *db.Queries, tender db.Tender, message string) {
+	for _, organizer := range tenderOrganizerIDs(queries, tender.OrganizerID) {
+		_, err := bot.Send(&telebot.User{ID: organizer}, message, &telebot.SendOptions{
+			ParseMode: telebot.ModeMarkdown,
+		})
+		if err != nil {
+			fmt.Printf("Ошибка отправки уведомления организатору %d: %v\n", organizer, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"tender_bot_go/db"
+	"tender_bot_go/tender"
+)
+
+// MaxRows — сколько тендеров можно загрузить одним файлом
+const MaxRows = 100
+
+// Колонки файла импорта по порядку
+const (
+	columnTitle = iota
+	columnDescription
+	columnStartPrice
+	columnStartDate
+	columnClassification
+	columnBidStep
+	columnCount
+)
+
+// Columns — подписи колонок файла импорта для подсказки организатору
+var Columns = []string{"Название", "Описание", "Стартовая цена", "Дата начала (ДД.ММ.ГГГГ ЧЧ:ММ)", "Классификация", "Шаг понижения (5000 или 1%)"}
+
+// Row — результат разбора одной строки файла: черновик тендера или ошибка
+type Row struct {
+	// Line — номер строки в файле, начиная с единицы
+	Line  int
+	Draft tender.Draft
+	Err   error
+}
+
+// ClassificationResolver возвращает код классификации по коду или названию из файла
+type ClassificationResolver func(value string) (string, bool)
+
+// ParseRows превращает строки таблицы в черновики открытых тендеров из одного лота
+// и проверяет их теми же правилами, что и мастер создания тендера. Строка заголовка
+// и пустые строки пропускаются.
+func ParseRows(rows [][]string, now time.Time, location *time.Location, organizerID int64, classify ClassificationResolver) ([]Row, error) {
+	var result []Row
+	for i, cells := range rows {
+		if isBlank(cells) || (i == 0 && isHeader(cells)) {
+			continue
+		}
+		if len(result) == MaxRows {
+			return nil, fmt.Errorf("в файле больше %d тендеров, разделите его на части", MaxRows)
+		}
+
+		draft, err := parseRow(cells, location, organizerID, classify)
+		if err == nil {
+			err = draft.Validate(now)
+		}
+		result = append(result, Row{Line: i + 1, Draft: draft, Err: err})
+	}
+	if len(result) == 0 {
+		return nil, errors.New("в файле нет ни одной строки с тендером")
+	}
+	return result, nil
+}
+
+func parseRow(cells []string, location *time.Location, organizerID int64, classify ClassificationResolver) (tender.Draft, error) {
+	for len(cells) < columnCount {
+		cells = append(cells, "")
+	}
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+
+	startPrice, err := parsePrice(cells[columnStartPrice])
+	if err != nil {
+		return tender.Draft{}, errors.New("Стартовая цена должна быть числом")
+	}
+
+	startAt, err := parseDate(cells[columnStartDate], location)
+	if err != nil {
+		return tender.Draft{}, errors.New("Дата начала должна быть в формате ДД.ММ.ГГГГ ЧЧ:ММ")
+	}
+
+	classification, ok := classify(cells[columnClassification])
+	if !ok {
+		return tender.Draft{}, fmt.Errorf("Неизвестная классификация «%s»", cells[columnClassification])
+	}
+
+	stepType, stepValue, err := tender.ParseBidStep(cells[columnBidStep])
+	if err != nil {
+		return tender.Draft{}, err
+	}
+
+	return tender.Draft{
+		Title:       cells[columnTitle],
+		Description: cells[columnDescription],
+		StartAt:     startAt,
+		Type:        db.TenderTypeOpen,
+		OrganizerID: organizerID,
+		Lots: []tender.LotDraft{
+			{
+				Title:          cells[columnTitle],
+				StartPrice:     startPrice,
+				MinBidDecrease: stepValue,
+				MinBidStepType: stepType,
+				Classification: classification,
+			},
+		},
+	}, nil
+}
+
+// parsePrice разбирает цену с пробелами между разрядами и запятой в дробной части
+func parsePrice(value string) (float64, error) {
+	value = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(value)
+	return strconv.ParseFloat(value, 64)
+}
+
+// excelEpoch — нулевой день дат Excel (с учётом ошибки 1900 года)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseDate разбирает дату в формате мастера или дату Excel, сохранённую числом
+func parseDate(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("02.01.2006 15:04", value, location); err == nil {
+		return t, nil
+	}
+
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial <= 0 {
+		return time.Time{}, errors.New("invalid date")
+	}
+	days := math.Floor(serial)
+	minutes := math.Round((serial - days) * 24 * 60)
+	t := excelEpoch.AddDate(0, 0, int(days)).Add(time.Duration(minutes) * time.Minute)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, location), nil
+}
+
+func isBlank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func isHeader(cells []string) bool {
+	first := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cells[0], "\ufeff")))
+	return first == "название" || first == "title"
+}
@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"tender_bot_go/db"
+)
+
+var testLocation = time.FixedZone("MSK", 3*60*60)
+
+var testNow = time.Date(2030, 1, 1, 0, 0, 0, 0, testLocation)
+
+func testClassify(value string) (string, bool) {
+	switch strings.ToLower(value) {
+	case "1", "трубы":
+		return "1", true
+	}
+	return "", false
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2030, 12, 25, 10, 0, 0, 0, testLocation)
+	tests := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"формат мастера", "25.12.2030 10:00", true},
+		{"дата Excel числом", "47842.416666666664", true},
+		{"дата Excel с округлением до минуты", "47842.41667", true},
+		{"дата без времени", "25.12.2030", false},
+		{"текст", "завтра", false},
+		{"ноль", "0", false},
+		{"отрицательное число", "-5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.value, testLocation)
+			if !tt.ok {
+				if err == nil {
+					t.Errorf("parseDate(%q) = %v, ожидалась ошибка", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDate(%q): %v", tt.value, err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("parseDate(%q) = %v, want %v", tt.value, got, want)
+			}
+		})
+	}
+}
+
+func TestParseRows(t *testing.T) {
+	rows := [][]string{
+		{"Название", "Описание", "Стартовая цена", "Дата начала", "Классификация", "Шаг"},
+		{"Трубы ПНД", "Поставка труб", "1 000 000,50", "25.12.2030 10:00", "Трубы", "5%"},
+		{},
+		{" ", ""},
+		{"Фитинги", "", "абв", "25.12.2030 10:00", "1", "5000"},
+		{"Краны", "", "50000", "вчера", "1", "5000"},
+		{"Насосы", "", "50000", "25.12.2030 10:00", "Мебель", "5000"},
+		{"Задвижки", "", "50000", "25.12.2030 10:00", "1", "шаг"},
+		{"Кабель", "", "50000", "01.01.2020 10:00", "1", "5000"},
+		// Недостающие колонки дополняются пустыми значениями
+		{"Клапаны", "", "50000", "47842.416666666664", "1"},
+	}
+
+	result, err := ParseRows(rows, testNow, testLocation, 42, testClassify)
+	if err != nil {
+		t.Fatalf("ParseRows: %v", err)
+	}
+
+	wantErrs := map[int]string{
+		5:  "Стартовая цена",
+		6:  "Дата начала",
+		7:  "Неизвестная классификация «Мебель»",
+		8:  "Шаг",
+		9:  "в будущем",
+		10: "Шаг",
+	}
+	if len(result) != 7 {
+		t.Fatalf("разобрано %d строк, want 7", len(result))
+	}
+
+	first := result[0]
+	if first.Line != 2 || first.Err != nil {
+		t.Fatalf("первая строка: Line=%d Err=%v", first.Line, first.Err)
+	}
+	lot := first.Draft.Lots[0]
+	if first.Draft.Title != "Трубы ПНД" || first.Draft.OrganizerID != 42 || first.Draft.Type != db.TenderTypeOpen {
+		t.Errorf("черновик = %+v", first.Draft)
+	}
+	if lot.StartPrice != 1000000.5 || lot.Classification != "1" || lot.MinBidStepType != db.BidStepPercent || lot.MinBidDecrease != 5 {
+		t.Errorf("лот = %+v", lot)
+	}
+
+	for _, row := range result[1:] {
+		want, ok := wantErrs[row.Line]
+		if !ok {
+			t.Errorf("неожиданная строка %d", row.Line)
+			continue
+		}
+		if row.Err == nil || !strings.Contains(row.Err.Error(), want) {
+			t.Errorf("строка %d: ошибка %v, want содержащую %q", row.Line, row.Err, want)
+		}
+	}
+}
+
+func TestParseRowsHeader(t *testing.T) {
+	valid := []string{"Трубы", "", "1000", "25.12.2030 10:00", "1", "100"}
+
+	tests := []struct {
+		name     string
+		rows     [][]string
+		wantLine int
+	}{
+		{"русский заголовок", [][]string{{"Название", "Описание"}, valid}, 2},
+		{"английский заголовок с BOM", [][]string{{"\ufeffTitle"}, valid}, 2},
+		{"без заголовка", [][]string{valid}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRows(tt.rows, testNow, testLocation, 1, testClassify)
+			if err != nil {
+				t.Fatalf("ParseRows: %v", err)
+			}
+			if len(result) != 1 || result[0].Line != tt.wantLine || result[0].Err != nil {
+				t.Errorf("ParseRows = %+v, want одну строку %d без ошибки", result, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestParseRowsLimits(t *testing.T) {
+	if _, err := ParseRows([][]string{{"Название"}, {}}, testNow, testLocation, 1, testClassify); err == nil {
+		t.Error("файл без тендеров: ожидалась ошибка")
+	}
+
+	rows := [][]string{{"Название"}}
+	for i := 0; i <= MaxRows; i++ {
+		rows = append(rows, []string{fmt.Sprintf("Тендер %d", i), "", "1000", "25.12.2030 10:00", "1", "100"})
+	}
+	if _, err := ParseRows(rows, testNow, testLocation, 1, testClassify); err == nil {
+		t.Errorf("%d тендеров: ожидалась ошибка", MaxRows+1)
+	}
+	if _, err := ParseRows(rows[:MaxRows+1], testNow, testLocation, 1, testClassify); err != nil {
+		t.Errorf("%d тендеров: %v", MaxRows, err)
+	}
+}
@@ -0,0 +1,278 @@
+// Package importer читает план закупок из таблицы (CSV или XLSX) и превращает
+// строки в черновики тендеров
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedFormat — файл не CSV и не XLSX
+var ErrUnsupportedFormat = errors.New("поддерживаются только файлы CSV и XLSX")
+
+// IsSupported сообщает, можно ли прочитать файл с таким именем
+func IsSupported(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".xlsx":
+		return true
+	}
+	return false
+}
+
+// ReadRows читает строки таблицы из файла CSV или XLSX. Формат определяется по
+// расширению имени файла. У XLSX читается первый лист.
+func ReadRows(filename string, data []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSV(data)
+	case ".xlsx":
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// readCSV читает CSV с разделителем «;» (так сохраняет Excel с русской локалью) или «,»
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows [][]string
+	filled := 0
+	for filled < maxSheetRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать CSV: %w", err)
+		}
+		rows = append(rows, record)
+		if !isBlank(record) {
+			filled++
+		}
+	}
+	return rows, nil
+}
+
+// maxSheetRows — сколько непустых строк читать из файла: заголовок, MaxRows тендеров
+// и ещё одна, чтобы ParseRows сообщил о превышении лимита
+const maxSheetRows = MaxRows + 2
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText — строка XLSX: простой текст или набор фрагментов с форматированием
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxRow struct {
+	Cells []struct {
+		Ref    string   `xml:"r,attr"`
+		Type   string   `xml:"t,attr"`
+		Value  string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// readXLSX читает первый лист книги XLSX без сторонних библиотек: значения берутся
+// из XML листа и таблицы общих строк
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть XLSX: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, errors.New("в книге XLSX нет ни одного листа")
+	}
+	rc, err := sheetFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", sheetFile.Name, err)
+	}
+	defer rc.Close()
+
+	// Лист читается по строкам, чтобы не разбирать целиком файл, в котором строк
+	// больше лимита
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxXMLSize))
+	var rows [][]string
+	filled := 0
+	for filled < maxSheetRows {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("не удалось разобрать %s: %w", sheetFile.Name, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, fmt.Errorf("не удалось разобрать %s: %w", sheetFile.Name, err)
+		}
+		values := rowValues(row, shared)
+		rows = append(rows, values)
+		if !isBlank(values) {
+			filled++
+		}
+	}
+	return rows, nil
+}
+
+// rowValues раскладывает ячейки строки по колонкам. Ячейки правее колонок файла
+// импорта отбрасываются, поэтому ссылка вида "XFD1" не раздувает строку.
+func rowValues(row xlsxRow, shared xlsxSharedStrings) []string {
+	var values []string
+	for i, cell := range row.Cells {
+		column := columnIndex(cell.Ref)
+		if column < 0 {
+			column = i
+		}
+		if column >= columnCount {
+			continue
+		}
+		for len(values) <= column {
+			values = append(values, "")
+		}
+
+		switch cell.Type {
+		case "s":
+			index, err := strconv.Atoi(cell.Value)
+			if err == nil && index >= 0 && index < len(shared.Items) {
+				values[column] = shared.Items[index].String()
+			}
+		case "inlineStr":
+			values[column] = cell.Inline.String()
+		default:
+			values[column] = cell.Value
+		}
+	}
+	return values
+}
+
+// firstSheetPath находит файл первого листа по описанию книги, а если его нет —
+// возвращает стандартный путь
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, relsOk := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !relsOk || decodeXML(workbookFile, &workbook) != nil || decodeXML(relsFile, &rels) != nil || len(workbook.Sheets) == 0 {
+		return fallback
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join("xl", rel.Target)
+	}
+	return fallback
+}
+
+func decodeXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("не удалось прочитать %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXMLSize)).Decode(v); err != nil {
+		return fmt.Errorf("не удалось разобрать %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// maxXMLSize ограничивает распакованный размер одной части XLSX
+const maxXMLSize = 20 << 20
+
+// maxXLSXColumns — число колонок листа Excel
+const maxXLSXColumns = 16384
+
+// columnIndex переводит ссылку на ячейку вида "C7" в номер колонки с нуля
+func columnIndex(ref string) int {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		letters++
+		// Дальше XFD (16384 колонки) Excel не бывает
+		if column > maxXLSXColumns {
+			return maxXLSXColumns
+		}
+	}
+	if letters == 0 {
+		return -1
+	}
+	return column - 1
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want [][]string
+	}{
+		{
+			name: "точка с запятой",
+			data: "Название;Цена\nТрубы;1 000,50\n",
+			want: [][]string{{"Название", "Цена"}, {"Трубы", "1 000,50"}},
+		},
+		{
+			name: "запятая",
+			data: "Название,Цена\nТрубы,1000\n",
+			want: [][]string{{"Название", "Цена"}, {"Трубы", "1000"}},
+		},
+		{
+			name: "BOM",
+			data: "\xef\xbb\xbfНазвание;Цена\nТрубы;1000\n",
+			want: [][]string{{"Название", "Цена"}, {"Трубы", "1000"}},
+		},
+		{
+			name: "запятая внутри значений при разделителе «;»",
+			data: "Трубы, фитинги;1000,5;5%;x\n",
+			want: [][]string{{"Трубы, фитинги", "1000,5", "5%", "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCSV([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("readCSV: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readCSV = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadCSVStopsAfterRowLimit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < MaxRows*5; i++ {
+		fmt.Fprintf(&b, "Тендер %d;1000\n\n", i)
+	}
+
+	rows, err := readCSV([]byte(b.String()))
+	if err != nil {
+		t.Fatalf("readCSV: %v", err)
+	}
+	if len(rows) != maxSheetRows {
+		t.Errorf("прочитано %d строк, want %d", len(rows), maxSheetRows)
+	}
+}
+
+// buildXLSX собирает минимальную книгу XLSX с листом sheet и общими строками shared
+func buildXLSX(t *testing.T, sheet string, shared []string) []byte {
+	t.Helper()
+
+	var sst strings.Builder
+	sst.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for _, s := range shared {
+		sst.WriteString("<si><t>" + s + "</t></si>")
+	}
+	sst.WriteString("</sst>")
+
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="План" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/plan.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":   sst.String(),
+		"xl/worksheets/plan.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + sheet + `</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	sheet := `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+		// Пропущенные ячейки B2 и D2
+		`<row r="2"><c r="A2" t="inlineStr"><is><t>Трубы</t></is></c><c r="C2"><v>1000</v></c><c r="E2" t="inlineStr"><is><r><t>Сан</t></r><r><t>техника</t></r></is></c></row>` +
+		// Ячейки за последней колонкой импорта отбрасываются
+		`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="ZZZZZZ3"><v>1</v></c><c r="XFD3"><v>1</v></c></row>`
+
+	got, err := readXLSX(buildXLSX(t, sheet, []string{"Название", "Описание", "Кабель"}))
+	if err != nil {
+		t.Fatalf("readXLSX: %v", err)
+	}
+
+	want := [][]string{
+		{"Название", "Описание"},
+		{"Трубы", "", "1000", "", "Сантехника"},
+		{"Кабель"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readXLSX = %q, want %q", got, want)
+	}
+}
+
+func TestReadXLSXStopsAfterRowLimit(t *testing.T) {
+	var sheet strings.Builder
+	for i := 1; i <= MaxRows*5; i++ {
+		fmt.Fprintf(&sheet, `<row r="%d"><c r="A%d" t="inlineStr"><is><t>Тендер</t></is></c></row><row/>`, i, i)
+	}
+
+	rows, err := readXLSX(buildXLSX(t, sheet.String(), nil))
+	if err != nil {
+		t.Fatalf("readXLSX: %v", err)
+	}
+	filled := 0
+	for _, row := range rows {
+		if !isBlank(row) {
+			filled++
+		}
+	}
+	if filled != maxSheetRows {
+		t.Errorf("прочитано %d непустых строк, want %d", filled, maxSheetRows)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"C7", 2},
+		{"Z1", 25},
+		{"AA1", 26},
+		{"XFD1", maxXLSXColumns - 1},
+		{"ZZZZZZZZZZZZ1", maxXLSXColumns},
+		{"", -1},
+		{"1", -1},
+	}
+
+	for _, tt := range tests {
+		if got := columnIndex(tt.ref); got != tt.want {
+			t.Errorf("columnIndex(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
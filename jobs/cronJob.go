@@ -6,22 +6,30 @@ import (
 	"strconv"
 	"strings"
 	"tender_bot_go/db"
-	"tender_bot_go/settings"
+	"tender_bot_go/tender"
 	"time"
 
 	"github.com/gofiber/fiber/v2/log"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/robfig/cron/v3"
 	"gopkg.in/telebot.v3"
 )
 
-var config = settings.LoadSettings()
-
 type MessageManager interface {
 	AddMessage(userID int64, messageID int)
 }
 
-func ActivatePendingTenders(bot *telebot.Bot, pool *pgxpool.Pool, msgManager MessageManager) {
+// TenderScheduler запускает таймер завершения лота с известным сроком,
+// завершает торги тендера, срок окончания которого наступил, и передаёт лот
+// следующему участнику, если победитель не подтвердил его вовремя
+type TenderScheduler interface {
+	ScheduleLotClose(lotID int32, closesAt time.Time)
+	CloseExpiredTender(tenderID int32)
+	ExpireWinnerOffer(offerID int32)
+}
+
+func ActivatePendingTenders(bot *telebot.Bot, pool *pgxpool.Pool, msgManager MessageManager, scheduler TenderScheduler) {
 	queries := db.New(pool)
 
 	c := cron.New(cron.WithSeconds())
@@ -32,11 +40,22 @@ func ActivatePendingTenders(bot *telebot.Bot, pool *pgxpool.Pool, msgManager Mes
 		
 
 		// Активируем pending тендеры
-		err := queries.ActivatePendingTenders(ctx)
+		toActivate, err := queries.GetTendersToActivate(ctx)
 		if err != nil {
-			log.Errorf("Failed to activate tenders: %v", err)
+			log.Errorf("Failed to get tenders to activate: %v", err)
 			return
 		}
+		for _, pending := range toActivate {
+			err := tender.Transition(ctx, queries, pending.ID, tender.StatusActivePending, tender.StatusActive, tender.SystemActor, "наступила дата начала")
+			if err != nil {
+				log.Errorf("Failed to activate tender %d: %v", pending.ID, err)
+				continue
+			}
+			// Срок закрытого тендера становится сроком завершения всех его лотов
+			if err := queries.SetSealedTenderDeadlines(ctx, pending.ID); err != nil {
+				log.Errorf("Failed to set sealed deadlines for tender %d: %v", pending.ID, err)
+			}
+		}
 		log.Info("Pending tenders activation check completed")
 
 		// Уведомления о начавшихся тендерах
@@ -62,6 +81,31 @@ func ActivatePendingTenders(bot *telebot.Bot, pool *pgxpool.Pool, msgManager Mes
 				tender.Title,
 			)
 
+			// Закрытый тендер завершается в срок приёма предложений
+			if tender.Type == db.TenderTypeSealed {
+				messageForUsers = fmt.Sprintf(
+					"🎉 *Тендер начался!*\n\n"+
+						"Закрытый тендер *%s* начался. Подайте одно предложение — другие участники его не увидят\n"+
+						"💰 *Стартовая цена:* %s руб.\n"+
+						"⏳ *Приём предложений до:* %s\n",
+					tender.Title,
+					formatPriceFloat(tender.StartPrice),
+					tender.ClosesAt.Time.Format("02.01.2006 15:04"),
+				)
+			}
+			// Срок закрытого тендера общий для всех его лотов
+			if tender.ClosesAt.Valid {
+				lots, err := queries.GetTenderLots(ctx, tender.ID)
+				if err != nil {
+					log.Errorf("Failed to get lots for tender %d: %v", tender.ID, err)
+				}
+				for _, lot := range lots {
+					if lot.ClosesAt.Valid {
+						scheduler.ScheduleLotClose(lot.ID, lot.ClosesAt.Time)
+					}
+				}
+			}
+
 			tenderId := tender.ID
 
 			err := queries.MessageSent(ctx, tenderId)
@@ -69,8 +113,12 @@ func ActivatePendingTenders(bot *telebot.Bot, pool *pgxpool.Pool, msgManager Mes
 				log.Errorf("Failed to set message_sent to true")
 			}
 
-			// Отправляем организатору
-			for _, organizer := range config.OrganizerIDs {
+			// Отправляем организатору тендера
+			organizers, err := queries.TenderOrganizerIDs(ctx, tender.OrganizerID)
+			if err != nil {
+				log.Errorf("Failed to get organizers for tender %d: %v", tenderId, err)
+			}
+			for _, organizer := range organizers {
 				_, err = bot.Send(&telebot.User{ID: organizer}, messageForOrganizer, &telebot.SendOptions{
 					ParseMode: telebot.ModeMarkdown,
 				})
@@ -163,10 +211,117 @@ func ActivatePendingTenders(bot *telebot.Bot, pool *pgxpool.Pool, msgManager Mes
 
 	})
 
+	// Каждую минуту завершаем тендеры, у которых наступил срок окончания (end_at),
+	// в том числе тендеры без ставок
+	c.AddFunc("0 * * * * *", func() {
+		expired, err := queries.GetExpiredTenders(context.Background())
+		if err != nil {
+			log.Errorf("Failed to get expired tenders: %v", err)
+			return
+		}
+
+		for _, tender := range expired {
+			log.Infof("Tender %d reached end_at, closing", tender.ID)
+			scheduler.CloseExpiredTender(tender.ID)
+		}
+	})
+
+	// Каждую минуту передаём лоты следующему участнику, если победитель не подтвердил
+	// их в срок
+	c.AddFunc("30 * * * * *", func() {
+		offers, err := queries.GetExpiredWinnerOffers(context.Background())
+		if err != nil {
+			log.Errorf("Failed to get expired winner offers: %v", err)
+			return
+		}
+
+		for _, offer := range offers {
+			log.Infof("Winner offer %d for lot %d expired", offer.ID, offer.LotID)
+			scheduler.ExpireWinnerOffer(offer.ID)
+		}
+	})
+
+	// Раз в час удаляем истёкшие состояния диалогов
+	c.AddFunc("0 0 * * * *", func() {
+		if err := queries.DeleteExpiredConversationStates(context.Background()); err != nil {
+			log.Errorf("Failed to delete expired conversation states: %v", err)
+		}
+	})
+
+	// Каждый день в 9:00 предупреждаем админов о документах поставщиков, срок действия
+	// которых истекает в ближайшие две недели или уже истёк
+	c.AddFunc("0 0 9 * * *", func() {
+		notifyExpiringDocuments(bot, queries)
+	})
+
 	c.Start()
 	log.Info("Tender activation job started - checking every 5 minutes")
 }
 
+// documentExpiryWarning — за сколько до окончания срока действия документа предупреждать админов
+const documentExpiryWarning = 14 * 24 * time.Hour
+
+func notifyExpiringDocuments(bot *telebot.Bot, queries *db.Queries) {
+	ctx := context.Background()
+
+	documents, err := queries.GetExpiringSupplierDocuments(ctx, pgtype.Date{
+		Time:  time.Now().Add(documentExpiryWarning),
+		Valid: true,
+	})
+	if err != nil {
+		log.Errorf("Failed to get expiring supplier documents: %v", err)
+		return
+	}
+	if len(documents) == 0 {
+		return
+	}
+
+	admins, err := queries.GetUserIDsByRole(ctx, "admin")
+	if err != nil {
+		log.Errorf("Failed to get admins: %v", err)
+		return
+	}
+
+	for _, document := range documents {
+		organization := fmt.Sprintf("ID %d", document.UserID)
+		if user, err := queries.GetUserByTelegramID(ctx, document.UserID); err == nil && user.OrganizationName.Valid {
+			organization = fmt.Sprintf("%s (ID %d)", user.OrganizationName.String, document.UserID)
+		}
+
+		expiresAt := document.ExpiresAt.Time.Format("02.01.2006")
+		status := "истекает " + expiresAt
+		if document.ExpiresAt.Time.Before(time.Now().Truncate(24 * time.Hour)) {
+			status = "истёк " + expiresAt
+		}
+
+		message := fmt.Sprintf(
+			"⏰ Срок действия документа поставщика %s\n\n🏢 %s\n📄 %s",
+			status,
+			organization,
+			document.FileName,
+		)
+
+		for _, adminID := range admins {
+			_, err := bot.Send(&telebot.User{ID: adminID}, message, &telebot.SendOptions{
+				ReplyMarkup: &telebot.ReplyMarkup{
+					InlineKeyboard: [][]telebot.InlineButton{{{
+						Unique: "request_document_reupload",
+						Text:   "🔁 Запросить повторную загрузку",
+						Data:   strconv.Itoa(int(document.ID)),
+					}}},
+				},
+			})
+			if err != nil {
+				log.Errorf("Failed to send document expiry notice to admin %d: %v", adminID, err)
+			}
+		}
+
+		if err := queries.MarkSupplierDocumentExpiryNotified(ctx, document.ID); err != nil {
+			log.Errorf("Failed to mark document %d as notified: %v", document.ID, err)
+		}
+	}
+}
+
 // Функция для форматирования цены в финансовый формат (из строки)
 func formatPrice(priceStr string) string {
 	// Пытаемся преобразовать строку в число
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"slices"
 	"time"
 
+	"tender_bot_go/api"
 	"tender_bot_go/db"
 	"tender_bot_go/handlers"
 	"tender_bot_go/jobs"
@@ -44,7 +46,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	jobs.ActivatePendingTenders(bot, pool, handlers.MessageManagerOperator)
+	jobs.ActivatePendingTenders(bot, pool, handlers.MessageManagerOperator, handlers.NewTenderScheduler(bot, pool))
 
 	// ===== /start =====
 	bot.Handle("/start", func(c telebot.Context) error {
@@ -56,32 +58,9 @@ func main() {
 		user, err := queries.GetUserByTelegramID(ctx, userID)
 		if err != nil {
 			if err == pgx.ErrNoRows {
-				role := "supplier"
-
-				// Проверяем, является ли пользователь организатором
-				isOrganizer := false
-				for _, organizerID := range settings.OrganizerIDs {
-					if userID == organizerID {
-						isOrganizer = true
-						break
-					}
-				}
-
-				if isOrganizer {
-					role = "organizer"
-				} else {
-					// Проверяем, является ли пользователь админом
-					for _, adminID := range settings.AdminIDs {
-						if userID == adminID {
-							role = "admin"
-							break
-						}
-					}
-				}
-
 				user, err = queries.CreateUser(ctx, db.CreateUserParams{
 					TelegramID: userID,
-					Role:       role,
+					Role:       "supplier",
 				})
 				if err != nil {
 					log.Println("DB create user error:", err)
@@ -97,6 +76,27 @@ func main() {
 			return c.Send("Ваш аккаунт заблокирован. Обратитесь к администратору.")
 		}
 
+		// ADMIN_IDS только назначает первого администратора, пока в БД нет ни одного.
+		// Дальше роли выдаются из панели администратора
+		if user.Role != "admin" && slices.Contains(settings.AdminIDs, userID) {
+			admins, err := queries.CountAdmins(ctx)
+			if err != nil {
+				log.Println("DB count admins error:", err)
+				return c.Send("Сервер временно недоступен. Попробуйте позже.")
+			}
+			if admins == 0 {
+				err = queries.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+					TelegramID: userID,
+					Role:       "admin",
+				})
+				if err != nil {
+					log.Println("DB update role error:", err)
+					return c.Send("Сервер временно недоступен. Попробуйте позже.")
+				}
+				user.Role = "admin"
+			}
+		}
+
 		switch user.Role {
 		case "organizer":
 			return c.Send("Панель организатора", &telebot.SendOptions{
@@ -121,6 +121,27 @@ func main() {
 	bot.Use(handlers.BlockedUserMiddleware(queries))
 	handlers.RegisterHandlers(bot, pool)
 
+	// Восстанавливаем таймеры тендеров, прерванные перезапуском
+	handlers.RestoreTenderTimers(bot, pool)
+
+	// REST API для внешних систем
+	if settings.APIKey != "" {
+		server := api.NewServer(queries, settings.APIKey)
+		server.OnCreated = func(tender db.Tender) {
+			handlers.NotifyTenderCreated(bot, pool, tender)
+		}
+		server.OnApproved = func(tenderID int32) {
+			handlers.NotifyTenderApproved(bot, pool, tenderID)
+		}
+		go func() {
+			if err := server.Listen(":" + settings.APIPort); err != nil {
+				log.Println("API server error:", err)
+			}
+		}()
+	} else {
+		log.Println("API_KEY не задан, REST API отключён")
+	}
+
 	bot.Start()
 }
 
@@ -11,7 +11,11 @@ var MenuOrganizer = &telebot.ReplyMarkup{
         },
 		{
             {Text: "История"},
-            {Text: "Удалить тендер"},
+            {Text: "Отменить тендер"},
+        },
+        {
+            {Text: "Шаблоны"},
+            {Text: "Импорт из файла"},
         },
     },
     ResizeKeyboard: true,
@@ -23,6 +27,10 @@ var MenuSupplierRegistered = &telebot.ReplyMarkup {
             {Text: "Тендеры"},
             {Text: "Подать заявку"},
 		},
+        {
+            {Text: "Мои участия"},
+            {Text: "Профиль"},
+        },
     },
     ResizeKeyboard: true,
     OneTimeKeyboard: false,
@@ -37,6 +45,32 @@ var MenuOrganizerCancel = &telebot.ReplyMarkup{
     ResizeKeyboard: true,
 }
 
+var MenuOrganizerTenderType = &telebot.ReplyMarkup{
+    ReplyKeyboard: [][]telebot.ReplyButton{
+        {
+            {Text: "Открытый аукцион"},
+            {Text: "Закрытый конверт"},
+        },
+        {
+            {Text: "Отмена"},
+        },
+    },
+    ResizeKeyboard: true,
+}
+
+var MenuOrganizerLots = &telebot.ReplyMarkup{
+    ReplyKeyboard: [][]telebot.ReplyButton{
+        {
+            {Text: "Добавить лот"},
+            {Text: "Продолжить"},
+        },
+        {
+            {Text: "Отмена"},
+        },
+    },
+    ResizeKeyboard: true,
+}
+
 var MenuSupplierUnregistered = &telebot.ReplyMarkup {
 	ReplyKeyboard: [][]telebot.ReplyButton{
         {
@@ -57,12 +91,22 @@ var MenuAdmin = &telebot.ReplyMarkup {
 		},
         {
             {Text: "История"},
+            {Text: "Классификации"},
         },
     },
     ResizeKeyboard: true,
     OneTimeKeyboard: false,
 }
 
+var MenuAdminCancel = &telebot.ReplyMarkup{
+    ReplyKeyboard: [][]telebot.ReplyButton{
+        {
+            {Text: "Отмена"},
+        },
+    },
+    ResizeKeyboard: true,
+}
+
 var BtnDeleteTender = telebot.InlineButton{
     Unique: "delete_tender",
     Text:   "🗑️ Удалить тендер",
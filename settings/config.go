@@ -4,6 +4,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,9 +12,14 @@ import (
 type Settings struct {
     BotToken    string
     AdminIDs    []int64
-    OrganizerIDs []int64
     DatabaseURL string
     FilesDir    string
+    StateStore  string
+    StateTTL    time.Duration
+    APIKey      string
+    APIPort     string
+    // WinnerConfirmTimeout — сколько победитель торгов по лоту может думать над подтверждением
+    WinnerConfirmTimeout time.Duration
 }
 
 func LoadSettings() *Settings {
@@ -23,7 +29,8 @@ func LoadSettings() *Settings {
 
     s.BotToken = os.Getenv("BOT_TOKEN")
 
-    // Админы
+    // Первый администратор: роль admin выдаётся, пока в БД нет ни одного админа.
+    // Остальные роли назначаются из панели администратора
     adminIDsStr := os.Getenv("ADMIN_IDS")
     s.AdminIDs = []int64{}
     for _, x := range strings.Split(adminIDsStr, ",") {
@@ -37,20 +44,6 @@ func LoadSettings() *Settings {
         }
     }
 
-    // Организатор
-    organizerIDsStr := os.Getenv("ORGANIZER_ID")
-    s.OrganizerIDs = []int64{}
-    for _, x := range strings.Split(organizerIDsStr, ",") {
-        x = strings.TrimSpace(x)
-        if x == "" {
-            continue
-        }
-        id, err := strconv.ParseInt(x, 10, 64)
-        if err == nil {
-            s.OrganizerIDs = append(s.OrganizerIDs, id)
-        }
-    }
-
     // Остальное
     s.DatabaseURL = os.Getenv("DATABASE_URL")
     if s.DatabaseURL == "" {
@@ -62,5 +55,29 @@ func LoadSettings() *Settings {
         s.FilesDir = "./files"
     }
 
+    // Хранилище состояний диалогов
+    s.StateStore = os.Getenv("STATE_STORE")
+    if s.StateStore == "" {
+        s.StateStore = "postgres"
+    }
+
+    s.StateTTL = 24 * time.Hour
+    if hours, err := strconv.Atoi(os.Getenv("STATE_TTL_HOURS")); err == nil && hours > 0 {
+        s.StateTTL = time.Duration(hours) * time.Hour
+    }
+
+    // REST API
+    s.APIKey = os.Getenv("API_KEY")
+    s.APIPort = os.Getenv("API_PORT")
+    if s.APIPort == "" {
+        s.APIPort = "80"
+    }
+
+    // Срок подтверждения победы, после которого лот предлагается следующему участнику
+    s.WinnerConfirmTimeout = 24 * time.Hour
+    if hours, err := strconv.Atoi(os.Getenv("WINNER_CONFIRM_HOURS")); err == nil && hours > 0 {
+        s.WinnerConfirmTimeout = time.Duration(hours) * time.Hour
+    }
+
     return s
 }
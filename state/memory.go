@@ -0,0 +1,72 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryKey struct {
+	userID int64
+	flow   Flow
+}
+
+type memoryEntry struct {
+	conv      Conversation
+	expiresAt time.Time
+}
+
+// MemoryStore хранит состояния в памяти процесса. Подходит для разработки:
+// после перезапуска все незавершённые диалоги теряются.
+type MemoryStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[memoryKey]memoryEntry
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &MemoryStore{
+		ttl:     ttl,
+		entries: make(map[memoryKey]memoryEntry),
+	}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, userID int64, flow Flow) (Conversation, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryKey{userID: userID, flow: flow}
+	entry, ok := s.entries[key]
+	if !ok {
+		return Conversation{}, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return Conversation{}, false, nil
+	}
+
+	// Отдаём копию, чтобы вызывающий код не менял данные в обход Set
+	return Conversation{Step: entry.conv.Step, Data: copyData(entry.conv.Data)}, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, userID int64, flow Flow, conv Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[memoryKey{userID: userID, flow: flow}] = memoryEntry{
+		conv:      Conversation{Step: conv.Step, Data: copyData(conv.Data)},
+		expiresAt: time.Now().Add(s.ttl),
+	}
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, userID int64, flow Flow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, memoryKey{userID: userID, flow: flow})
+	return nil
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"tender_bot_go/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PostgresStore хранит состояния в таблице conversation_states, поэтому
+// пользователь может продолжить заполнение формы после перезапуска бота.
+type PostgresStore struct {
+	queries db.Querier
+	ttl     time.Duration
+}
+
+func NewPostgresStore(queries db.Querier, ttl time.Duration) *PostgresStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &PostgresStore{queries: queries, ttl: ttl}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, userID int64, flow Flow) (Conversation, bool, error) {
+	row, err := s.queries.GetConversationState(ctx, db.GetConversationStateParams{
+		UserID: userID,
+		Flow:   string(flow),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Conversation{}, false, nil
+	}
+	if err != nil {
+		return Conversation{}, false, err
+	}
+
+	conv := Conversation{Step: int(row.Step), Data: map[string]string{}}
+	if len(row.Data) > 0 {
+		if err := json.Unmarshal(row.Data, &conv.Data); err != nil {
+			return Conversation{}, false, err
+		}
+	}
+	return conv, true, nil
+}
+
+func (s *PostgresStore) Set(ctx context.Context, userID int64, flow Flow, conv Conversation) error {
+	data := conv.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return s.queries.UpsertConversationState(ctx, db.UpsertConversationStateParams{
+		UserID:    userID,
+		Flow:      string(flow),
+		Step:      int32(conv.Step),
+		Data:      raw,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(s.ttl), Valid: true},
+	})
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, userID int64, flow Flow) error {
+	return s.queries.DeleteConversationState(ctx, db.DeleteConversationStateParams{
+		UserID: userID,
+		Flow:   string(flow),
+	})
+}
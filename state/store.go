@@ -0,0 +1,60 @@
+package state
+
+import (
+	"context"
+	"time"
+)
+
+// Flow — сценарий диалога, в котором находится пользователь.
+// У одного пользователя одновременно может быть открыто несколько сценариев.
+type Flow string
+
+const (
+	FlowOrganizer Flow = "organizer"
+	FlowSupplier  Flow = "supplier"
+	FlowBid       Flow = "bid"
+	FlowAdmin     Flow = "admin"
+)
+
+// Conversation — состояние незавершённого диалога: текущий шаг мастера
+// и уже введённые пользователем данные.
+type Conversation struct {
+	Step int
+	Data map[string]string
+}
+
+// Get возвращает значение из данных диалога или пустую строку.
+func (c Conversation) Get(key string) string {
+	return c.Data[key]
+}
+
+// Put записывает значение в данные диалога.
+func (c *Conversation) Put(key, value string) {
+	if c.Data == nil {
+		c.Data = map[string]string{}
+	}
+	c.Data[key] = value
+}
+
+// Store хранит состояния диалогов пользователей. Реализации должны быть
+// безопасны для вызова из нескольких горутин.
+type Store interface {
+	// Get возвращает состояние диалога. Второй результат false, если диалога
+	// нет или истёк его срок хранения.
+	Get(ctx context.Context, userID int64, flow Flow) (Conversation, bool, error)
+	// Set сохраняет состояние диалога и продлевает срок его хранения.
+	Set(ctx context.Context, userID int64, flow Flow, conv Conversation) error
+	// Delete завершает диалог.
+	Delete(ctx context.Context, userID int64, flow Flow) error
+}
+
+// DefaultTTL — срок хранения незавершённого диалога по умолчанию
+const DefaultTTL = 24 * time.Hour
+
+func copyData(data map[string]string) map[string]string {
+	out := make(map[string]string, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	return out
+}
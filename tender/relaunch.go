@@ -0,0 +1,51 @@
+package tender
+
+import (
+	"math"
+	"time"
+
+	"tender_bot_go/db"
+)
+
+// MaxRelaunchPriceIncrease — на сколько процентов можно поднять стартовую цену при перезапуске
+const MaxRelaunchPriceIncrease = 100
+
+// ValidatePriceIncrease проверяет, на сколько процентов поднимается стартовая цена при перезапуске
+func ValidatePriceIncrease(percent float64) error {
+	if percent < 0 || percent > MaxRelaunchPriceIncrease {
+		return invalid("price_increase", "Повышение цены должно быть от 0 до 100%")
+	}
+	return nil
+}
+
+// RelaunchDraft возвращает черновик нового тендера по несостоявшемуся: те же лоты,
+// классификации и условия, новая дата начала и стартовые цены, поднятые на
+// priceIncrease процентов. Срок окончания сдвигается вместе с датой начала.
+// ConditionsPath указывает на файл исходного тендера: перед созданием нового тендера
+// файл нужно скопировать, чтобы тендеры не делили его.
+func RelaunchDraft(original db.Tender, lots []db.TenderLot, classifications []string, startAt time.Time, priceIncrease float64) Draft {
+	draft := Draft{
+		Title:            original.Title,
+		Description:      original.Description.String,
+		StartAt:          startAt,
+		ConditionsPath:   original.ConditionsPath.String,
+		Type:             original.Type,
+		ExtensionMinutes: original.ExtensionMinutes,
+		OrganizerID:      original.OrganizerID.Int64,
+		Classifications:  classifications,
+	}
+	if original.EndAt.Valid {
+		draft.EndAt = startAt.Add(original.EndAt.Time.Sub(original.StartAt.Time))
+	}
+
+	for _, lot := range lots {
+		draft.Lots = append(draft.Lots, LotDraft{
+			Title:          lot.Title,
+			StartPrice:     math.Round(lot.StartPrice*(100+priceIncrease)) / 100,
+			MinBidDecrease: lot.MinBidDecrease,
+			MinBidStepType: lot.MinBidStepType,
+			Classification: lot.Classification.String,
+		})
+	}
+	return draft
+}
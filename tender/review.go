@@ -0,0 +1,56 @@
+package tender
+
+import (
+	"context"
+	"strings"
+
+	"tender_bot_go/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Решение администратора по тендеру (tender_reviews.decision)
+const (
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// reviewStatus — статус, в который тендер переходит после решения модерации
+var reviewStatus = map[string]string{
+	ReviewApproved: StatusActivePending,
+	ReviewRejected: StatusRejected,
+}
+
+// Review переводит тендер из pending_approval по решению decision и в той же транзакции
+// записывает раунд модерации. Номер раунда считается после того, как смена статуса
+// заблокировала строку тендера, поэтому параллельные решения не получат один номер.
+// Причина сохраняется в раунде только для отклонения.
+func Review(ctx context.Context, store StatusStore, tenderID int32, decision string, actorID int64, reason string) (db.TenderReview, error) {
+	var review db.TenderReview
+	err := TransitionWith(ctx, store, tenderID, StatusPendingApproval, reviewStatus[decision], actorID, reason, func(q *db.Queries) error {
+		params := db.AddTenderReviewParams{
+			TenderID:   tenderID,
+			Decision:   decision,
+			ReviewerID: pgtype.Int8{Int64: actorID, Valid: actorID != SystemActor},
+		}
+		if decision == ReviewRejected {
+			params.Reason = reason
+		}
+
+		var err error
+		review, err = q.AddTenderReview(ctx, params)
+		return err
+	})
+	return review, err
+}
+
+// ValidateRejectReason проверяет причину отклонения, которую получит организатор
+func ValidateRejectReason(reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return invalid("reason", "Причина отклонения не может быть пустой")
+	}
+	if len([]rune(reason)) > MaxReasonLength {
+		return invalid("reason", "Причина отклонения не должна быть длиннее 1000 символов")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package tender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"tender_bot_go/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Статусы тендера (tenders.status)
+const (
+	StatusPendingApproval = "pending_approval"
+	StatusActivePending   = "active_pending"
+	StatusActive          = "active"
+	StatusCompleted       = "completed"
+	StatusFailed          = "failed"
+	StatusCancelled       = "cancelled"
+	StatusRejected        = "rejected"
+)
+
+// transitions — допустимые переходы между статусами. completed, failed и cancelled
+// конечные: из них тендер никуда не переходит, несостоявшийся тендер перезапускается копией.
+// Отклонённый тендер после исправления снова отправляется на модерацию, как и
+// одобренный тендер, который организатор изменил до начала торгов.
+var transitions = map[string][]string{
+	StatusPendingApproval: {StatusActivePending, StatusRejected, StatusCancelled},
+	StatusRejected:        {StatusPendingApproval, StatusCancelled},
+	StatusActivePending:   {StatusActive, StatusPendingApproval, StatusCancelled},
+	StatusActive:          {StatusCompleted, StatusFailed, StatusCancelled},
+}
+
+// MaxReasonLength — наибольшая длина причины, с которой меняется статус тендера
+const MaxReasonLength = 1000
+
+// ValidateCancelReason проверяет причину отмены, которую получат участники тендера
+func ValidateCancelReason(reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return invalid("reason", "Причина отмены не может быть пустой")
+	}
+	if len([]rune(reason)) > MaxReasonLength {
+		return invalid("reason", "Причина отмены не должна быть длиннее 1000 символов")
+	}
+	return nil
+}
+
+// SystemActor — автор перехода, который выполнила система, а не пользователь
+const SystemActor int64 = 0
+
+// ErrStatusChanged — статус тендера изменился раньше, чем переход был выполнен
+var ErrStatusChanged = errors.New("статус тендера уже изменён")
+
+// TransitionError — переход между статусами не предусмотрен жизненным циклом тендера
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("недопустимый переход тендера из %s в %s", e.From, e.To)
+}
+
+// CanTransition сообщает, может ли тендер перейти из статуса from в статус to
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusStore — хранилище, в котором меняется статус тендера
+type StatusStore interface {
+	ChangeTenderStatus(ctx context.Context, arg db.ChangeTenderStatusParams) (bool, error)
+}
+
+// Transition переводит тендер из статуса from в статус to и записывает событие с автором
+// и причиной. Только через него меняется статус тендера. Возвращает TransitionError для
+// недопустимого перехода и ErrStatusChanged, если тендер уже не в статусе from.
+func Transition(ctx context.Context, store StatusStore, tenderID int32, from, to string, actorID int64, reason string) error {
+	return TransitionWith(ctx, store, tenderID, from, to, actorID, reason, nil)
+}
+
+// TransitionWith выполняет переход, как Transition, и в той же транзакции вызывает
+// apply с запросами этой транзакции. Ошибка apply отменяет и переход.
+func TransitionWith(ctx context.Context, store StatusStore, tenderID int32, from, to string, actorID int64, reason string, apply func(q *db.Queries) error) error {
+	if !CanTransition(from, to) {
+		return &TransitionError{From: from, To: to}
+	}
+
+	changed, err := store.ChangeTenderStatus(ctx, db.ChangeTenderStatusParams{
+		TenderID:   tenderID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID: pgtype.Int8{
+			Int64: actorID,
+			Valid: actorID != SystemActor,
+		},
+		Reason: reason,
+		Apply:  apply,
+	})
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrStatusChanged
+	}
+	return nil
+}
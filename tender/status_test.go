@@ -0,0 +1,37 @@
+package tender
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusPendingApproval, StatusActivePending, true},
+		{StatusPendingApproval, StatusRejected, true},
+		{StatusPendingApproval, StatusCancelled, true},
+		{StatusPendingApproval, StatusActive, false},
+		{StatusRejected, StatusPendingApproval, true},
+		{StatusRejected, StatusCancelled, true},
+		{StatusRejected, StatusActivePending, false},
+		{StatusActivePending, StatusActive, true},
+		{StatusActivePending, StatusPendingApproval, true},
+		{StatusActivePending, StatusCancelled, true},
+		{StatusActivePending, StatusCompleted, false},
+		{StatusActive, StatusCompleted, true},
+		{StatusActive, StatusFailed, true},
+		{StatusActive, StatusCancelled, true},
+		{StatusActive, StatusPendingApproval, false},
+		{StatusCompleted, StatusActive, false},
+		{StatusFailed, StatusActive, false},
+		{StatusCancelled, StatusPendingApproval, false},
+		{StatusActive, StatusActive, false},
+		{"unknown", StatusActive, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
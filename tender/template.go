@@ -0,0 +1,93 @@
+package tender
+
+import (
+	"strings"
+	"time"
+
+	"tender_bot_go/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MaxTemplateNameLength — наибольшая длина названия шаблона
+const MaxTemplateNameLength = 100
+
+// ValidateTemplateName проверяет название, под которым организатор сохраняет шаблон
+func ValidateTemplateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return invalid("name", "Название шаблона не может быть пустым")
+	}
+	if len([]rune(name)) > MaxTemplateNameLength {
+		return invalid("name", "Название шаблона не должно быть длиннее 100 символов")
+	}
+	return nil
+}
+
+// TemplateParams возвращает параметры шаблона по тендеру: условия, классификации и лоты
+// без дат, статусов и текущих цен. Срок торгов сохраняется как длительность от даты начала.
+func TemplateParams(name string, original db.Tender, lots []db.TenderLot, classifications []string, organizerID int64, conditionsPath string) (db.CreateTenderTemplateParams, []db.CreateTenderTemplateLotParams) {
+	// nil записался бы как NULL, а колонка классификаций шаблона обязательная
+	if classifications == nil {
+		classifications = []string{}
+	}
+	params := db.CreateTenderTemplateParams{
+		OrganizerID:      organizerID,
+		Name:             strings.TrimSpace(name),
+		Title:            original.Title,
+		Description:      original.Description,
+		Type:             original.Type,
+		ExtensionMinutes: original.ExtensionMinutes,
+		ConditionsPath: pgtype.Text{
+			String: conditionsPath,
+			Valid:  conditionsPath != "",
+		},
+		Classifications: classifications,
+	}
+	if original.EndAt.Valid && original.StartAt.Valid {
+		params.DurationMinutes = pgtype.Int4{
+			Int32: int32(original.EndAt.Time.Sub(original.StartAt.Time) / time.Minute),
+			Valid: true,
+		}
+	}
+
+	lotParams := make([]db.CreateTenderTemplateLotParams, 0, len(lots))
+	for _, lot := range lots {
+		lotParams = append(lotParams, db.CreateTenderTemplateLotParams{
+			Title:          lot.Title,
+			StartPrice:     lot.StartPrice,
+			MinBidDecrease: lot.MinBidDecrease,
+			MinBidStepType: lot.MinBidStepType,
+			Classification: lot.Classification,
+		})
+	}
+	return params, lotParams
+}
+
+// TemplateDraft возвращает черновик нового тендера по шаблону с датой начала startAt.
+// Срок окончания отсчитывается от даты начала на сохранённую в шаблоне длительность.
+func TemplateDraft(template db.TenderTemplate, lots []db.TenderTemplateLot, startAt time.Time) Draft {
+	draft := Draft{
+		Title:            template.Title,
+		Description:      template.Description.String,
+		StartAt:          startAt,
+		ConditionsPath:   template.ConditionsPath.String,
+		Type:             template.Type,
+		ExtensionMinutes: template.ExtensionMinutes,
+		OrganizerID:      template.OrganizerID,
+		Classifications:  template.Classifications,
+	}
+	if template.DurationMinutes.Valid {
+		draft.EndAt = startAt.Add(time.Duration(template.DurationMinutes.Int32) * time.Minute)
+	}
+
+	for _, lot := range lots {
+		draft.Lots = append(draft.Lots, LotDraft{
+			Title:          lot.Title,
+			StartPrice:     lot.StartPrice,
+			MinBidDecrease: lot.MinBidDecrease,
+			MinBidStepType: lot.MinBidStepType,
+			Classification: lot.Classification.String,
+		})
+	}
+	return draft
+}
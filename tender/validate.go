@@ -0,0 +1,336 @@
+package tender
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tender_bot_go/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Draft — данные нового тендера до сохранения в БД. Заполняется мастером
+// организатора в боте или телом запроса POST /tenders.
+type Draft struct {
+	Title          string
+	Description    string
+	StartAt        time.Time
+	ConditionsPath string
+	// Type — db.TenderTypeOpen или db.TenderTypeSealed
+	Type string
+	// EndAt — срок приёма предложений закрытого тендера или необязательный
+	// жёсткий срок окончания открытого
+	EndAt time.Time
+	// ExtensionMinutes — на сколько минут продлевается открытый тендер при ставке
+	// в последние минуты перед EndAt (антиснайпинг), 0 — без продления
+	ExtensionMinutes int32
+	// OrganizerID — Telegram ID организатора, которому принадлежит тендер
+	OrganizerID int64
+	// Lots — лоты тендера, по каждому идут отдельные торги
+	Lots []LotDraft
+	// Classifications — классификации тендера помимо классификаций лотов
+	Classifications []string
+}
+
+// LotDraft — лот нового тендера: своя стартовая цена, шаг и классификация
+type LotDraft struct {
+	Title          string  `json:"title"`
+	StartPrice     float64 `json:"start_price"`
+	MinBidDecrease float64 `json:"min_bid_decrease"`
+	MinBidStepType string  `json:"min_bid_step_type"`
+	Classification string  `json:"classification"`
+}
+
+// MaxLots — сколько лотов можно добавить в один тендер
+const MaxLots = 20
+
+// ValidationError — ошибка в данных тендера, текст которой можно показать пользователю
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+// IsValidationError сообщает, что ошибка вызвана некорректными данными, а не сбоем
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+func ValidateTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return invalid("title", "Название тендера не может быть пустым")
+	}
+	if len([]rune(title)) > 255 {
+		return invalid("title", "Название тендера не должно быть длиннее 255 символов")
+	}
+	return nil
+}
+
+func ValidateLotTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return invalid("title", "Название лота не может быть пустым")
+	}
+	if len([]rune(title)) > 255 {
+		return invalid("title", "Название лота не должно быть длиннее 255 символов")
+	}
+	return nil
+}
+
+func ValidateStartPrice(price float64) error {
+	if price <= 0 {
+		return invalid("start_price", "Стартовая цена должна быть больше нуля")
+	}
+	return nil
+}
+
+func ValidateStartAt(startAt, now time.Time) error {
+	if startAt.IsZero() {
+		return invalid("start_at", "Не указана дата начала тендера")
+	}
+	if startAt.Before(now) {
+		return invalid("start_at", "Дата начала тендера должна быть в будущем")
+	}
+	return nil
+}
+
+// ValidateType проверяет тип тендера
+func ValidateType(tenderType string) error {
+	if tenderType != db.TenderTypeOpen && tenderType != db.TenderTypeSealed {
+		return invalid("type", "Тип тендера должен быть open или sealed")
+	}
+	return nil
+}
+
+// ValidateEndAt проверяет срок окончания тендера: закрытому тендеру он обязателен,
+// открытому — нет. Срок должен быть позже даты начала.
+func ValidateEndAt(tenderType string, startAt, endAt time.Time) error {
+	if endAt.IsZero() {
+		if tenderType == db.TenderTypeSealed {
+			return invalid("end_at", "Не указан срок приёма предложений")
+		}
+		return nil
+	}
+	if !endAt.After(startAt) {
+		if tenderType == db.TenderTypeSealed {
+			return invalid("end_at", "Срок приёма предложений должен быть позже даты начала")
+		}
+		return invalid("end_at", "Срок окончания тендера должен быть позже даты начала")
+	}
+	return nil
+}
+
+// MaxExtensionMinutes — наибольшее продление открытого тендера за одну ставку
+const MaxExtensionMinutes = 60
+
+// ValidateExtension проверяет окно антиснайпинга: оно задаётся только для открытого
+// тендера со сроком окончания
+func ValidateExtension(tenderType string, endAt time.Time, minutes int32) error {
+	if minutes == 0 {
+		return nil
+	}
+	if tenderType != db.TenderTypeOpen || endAt.IsZero() {
+		return invalid("extension_minutes", "Продление задаётся только для открытого тендера со сроком окончания")
+	}
+	if minutes < 0 || minutes > MaxExtensionMinutes {
+		return invalid("extension_minutes", fmt.Sprintf("Продление должно быть от 0 до %d минут", MaxExtensionMinutes))
+	}
+	return nil
+}
+
+// ParseBidStep разбирает шаг понижения ставки: "5000" — сумма в рублях, "1%" — процент от текущей цены
+func ParseBidStep(text string) (string, float64, error) {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", ".")
+	if strings.HasSuffix(text, "%") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(text, "%")), 64)
+		if err != nil {
+			return "", 0, invalid("min_bid_decrease", "Процент должен быть числом")
+		}
+		return db.BidStepPercent, value, nil
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return "", 0, invalid("min_bid_decrease", "Шаг должен быть числом")
+	}
+	return db.BidStepAmount, value, nil
+}
+
+// ValidateBidStep проверяет шаг понижения ставки относительно стартовой цены
+func ValidateBidStep(stepType string, value, startPrice float64) error {
+	switch stepType {
+	case db.BidStepPercent:
+		if value <= 0 || value >= 100 {
+			return invalid("min_bid_decrease", "Процент должен быть больше 0 и меньше 100")
+		}
+	case db.BidStepAmount:
+		if value <= 0 {
+			return invalid("min_bid_decrease", "Шаг должен быть положительным числом")
+		}
+		if value >= startPrice {
+			return invalid("min_bid_decrease", "Шаг понижения должен быть меньше стартовой цены")
+		}
+	default:
+		return invalid("min_bid_step_type", "Тип шага должен быть amount или percent")
+	}
+	return nil
+}
+
+// Validate проверяет лот тендера указанного типа
+func (l LotDraft) Validate(tenderType string) error {
+	if err := ValidateLotTitle(l.Title); err != nil {
+		return err
+	}
+	if err := ValidateStartPrice(l.StartPrice); err != nil {
+		return err
+	}
+	// В закрытом тендере шага понижения нет
+	if tenderType == db.TenderTypeOpen {
+		if err := ValidateBidStep(l.MinBidStepType, l.MinBidDecrease, l.StartPrice); err != nil {
+			return err
+		}
+	}
+	if l.Classification == "" {
+		return invalid("classification", "Не выбрана классификация лота")
+	}
+	return nil
+}
+
+// Validate проверяет все поля тендера перед сохранением
+func (d Draft) Validate(now time.Time) error {
+	if err := ValidateTitle(d.Title); err != nil {
+		return err
+	}
+	if err := ValidateType(d.Type); err != nil {
+		return err
+	}
+	if len(d.Lots) == 0 {
+		return invalid("lots", "В тендере должен быть хотя бы один лот")
+	}
+	if len(d.Lots) > MaxLots {
+		return invalid("lots", fmt.Sprintf("В тендере может быть не больше %d лотов", MaxLots))
+	}
+	for i, lot := range d.Lots {
+		if err := lot.Validate(d.Type); err != nil {
+			var validationErr *ValidationError
+			errors.As(err, &validationErr)
+			return invalid(fmt.Sprintf("lots[%d].%s", i, validationErr.Field),
+				fmt.Sprintf("Лот №%d: %s", i+1, validationErr.Message))
+		}
+	}
+	if err := ValidateStartAt(d.StartAt, now); err != nil {
+		return err
+	}
+	if err := ValidateEndAt(d.Type, d.StartAt, d.EndAt); err != nil {
+		return err
+	}
+	if err := ValidateExtension(d.Type, d.EndAt, d.ExtensionMinutes); err != nil {
+		return err
+	}
+	if d.OrganizerID == 0 {
+		return invalid("organizer_id", "Не указан организатор тендера")
+	}
+	return nil
+}
+
+// CreateParams возвращает параметры запроса CreateTender для проверенного черновика.
+// Цены тендера — суммы цен лотов, классификация и шаг берутся из первого лота.
+func (d Draft) CreateParams() db.CreateTenderParams {
+	var startPrice float64
+	for _, lot := range d.Lots {
+		startPrice += lot.StartPrice
+	}
+	first := d.Lots[0]
+	stepType, stepValue := first.MinBidStepType, first.MinBidDecrease
+	if d.Type == db.TenderTypeSealed {
+		stepType, stepValue = db.BidStepAmount, 0
+	}
+
+	return db.CreateTenderParams{
+		Title: d.Title,
+		Description: pgtype.Text{
+			String: d.Description,
+			Valid:  true,
+		},
+		StartPrice: startPrice,
+		StartAt: pgtype.Timestamptz{
+			Time:  d.StartAt,
+			Valid: true,
+		},
+		ConditionsPath: pgtype.Text{
+			String: d.ConditionsPath,
+			Valid:  d.ConditionsPath != "",
+		},
+		CurrentPrice: startPrice,
+		Classification: pgtype.Text{
+			String: first.Classification,
+			Valid:  first.Classification != "",
+		},
+		MinBidDecrease: stepValue,
+		MinBidStepType: stepType,
+		OrganizerID: pgtype.Int8{
+			Int64: d.OrganizerID,
+			Valid: true,
+		},
+		Type: d.Type,
+		EndAt: pgtype.Timestamptz{
+			Time:  d.EndAt,
+			Valid: !d.EndAt.IsZero(),
+		},
+		ExtensionMinutes: d.ExtensionMinutes,
+	}
+}
+
+// ClassificationCodes возвращает все классификации тендера без повторов: сначала
+// классификации лотов, затем дополнительные
+func (d Draft) ClassificationCodes() []string {
+	var codes []string
+	seen := make(map[string]bool)
+	add := func(code string) {
+		if code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	for _, lot := range d.Lots {
+		add(lot.Classification)
+	}
+	for _, code := range d.Classifications {
+		add(code)
+	}
+	return codes
+}
+
+// LotParams возвращает параметры запросов CreateTenderLot для лотов черновика.
+// TenderID и номер лота заполняет db.CreateTenderWithLots.
+func (d Draft) LotParams() []db.CreateTenderLotParams {
+	params := make([]db.CreateTenderLotParams, 0, len(d.Lots))
+	for _, lot := range d.Lots {
+		stepType, stepValue := lot.MinBidStepType, lot.MinBidDecrease
+		if d.Type == db.TenderTypeSealed {
+			stepType, stepValue = db.BidStepAmount, 0
+		}
+		params = append(params, db.CreateTenderLotParams{
+			Title:          lot.Title,
+			StartPrice:     lot.StartPrice,
+			CurrentPrice:   lot.StartPrice,
+			MinBidDecrease: stepValue,
+			MinBidStepType: stepType,
+			Classification: pgtype.Text{
+				String: lot.Classification,
+				Valid:  lot.Classification != "",
+			},
+		})
+	}
+	return params
+}
@@ -0,0 +1,118 @@
+package tender
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tender_bot_go/db"
+)
+
+func TestParseBidStep(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantType string
+		want     float64
+		wantErr  bool
+	}{
+		{"5%", db.BidStepPercent, 5, false},
+		{" 2,5 % ", db.BidStepPercent, 2.5, false},
+		{"100", db.BidStepAmount, 100, false},
+		{"10,50", db.BidStepAmount, 10.5, false},
+		{"%", "", 0, true},
+		{"abc", "", 0, true},
+		{"", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		stepType, value, err := ParseBidStep(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBidStep(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if stepType != tt.wantType || value != tt.want {
+			t.Errorf("ParseBidStep(%q) = %s %v, want %s %v", tt.text, stepType, value, tt.wantType, tt.want)
+		}
+	}
+}
+
+func TestValidateBidStep(t *testing.T) {
+	tests := []struct {
+		stepType   string
+		value      float64
+		startPrice float64
+		valid      bool
+	}{
+		{db.BidStepPercent, 5, 1000, true},
+		{db.BidStepPercent, 0, 1000, false},
+		{db.BidStepPercent, 100, 1000, false},
+		{db.BidStepAmount, 50, 1000, true},
+		{db.BidStepAmount, 0, 1000, false},
+		{db.BidStepAmount, -10, 1000, false},
+		{db.BidStepAmount, 1000, 1000, false},
+		{db.BidStepAmount, 1500, 1000, false},
+		{"unknown", 5, 1000, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateBidStep(tt.stepType, tt.value, tt.startPrice)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateBidStep(%s, %v, %v) = %v, want valid %v", tt.stepType, tt.value, tt.startPrice, err, tt.valid)
+		}
+	}
+}
+
+func TestDraftValidate(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	valid := func() Draft {
+		return Draft{
+			Title:       "Поставка бумаги",
+			StartAt:     now.Add(24 * time.Hour),
+			Type:        db.TenderTypeOpen,
+			OrganizerID: 1,
+			Lots: []LotDraft{
+				{Title: "Бумага А4", StartPrice: 1000, MinBidDecrease: 50, MinBidStepType: db.BidStepAmount, Classification: "17.12"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(d *Draft)
+		wantField string
+	}{
+		{"корректный тендер", func(d *Draft) {}, ""},
+		{"пустое название", func(d *Draft) { d.Title = "  " }, "title"},
+		{"нулевая цена лота", func(d *Draft) { d.Lots[0].StartPrice = 0 }, "lots[0].start_price"},
+		{"отрицательная цена лота", func(d *Draft) { d.Lots[0].StartPrice = -100 }, "lots[0].start_price"},
+		{"начало в прошлом", func(d *Draft) { d.StartAt = now.Add(-time.Hour) }, "start_at"},
+		{"окончание раньше начала", func(d *Draft) { d.EndAt = d.StartAt.Add(-time.Hour) }, "end_at"},
+		{"без лотов", func(d *Draft) { d.Lots = nil }, "lots"},
+		{"шаг не меньше цены", func(d *Draft) { d.Lots[0].MinBidDecrease = 1000 }, "lots[0].min_bid_decrease"},
+		{"неизвестный тип шага", func(d *Draft) { d.Lots[0].MinBidStepType = "unknown" }, "lots[0].min_bid_step_type"},
+		{"шаг не проверяется в закрытом тендере", func(d *Draft) {
+			d.Type = db.TenderTypeSealed
+			d.EndAt = d.StartAt.Add(time.Hour)
+			d.Lots[0].MinBidDecrease = 0
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.modify(&d)
+			err := d.Validate(now)
+
+			var field string
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				field = validationErr.Field
+			} else if err != nil {
+				t.Fatalf("Validate() = %v, want ValidationError", err)
+			}
+			if field != tt.wantField {
+				t.Errorf("Validate() field = %q, want %q (%v)", field, tt.wantField, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package validation проверяет реквизиты организаций: ИНН, ОГРН и ОГРНИП
+// по контрольным суммам, установленным ФНС
+package validation
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Длины реквизитов
+const (
+	// LegalINNLength — ИНН юридического лица
+	LegalINNLength = 10
+	// PersonINNLength — ИНН индивидуального предпринимателя (физического лица)
+	PersonINNLength = 12
+	// OGRNLength — ОГРН юридического лица
+	OGRNLength = 13
+	// OGRNIPLength — ОГРНИП индивидуального предпринимателя
+	OGRNIPLength = 15
+)
+
+// Коэффициенты контрольных цифр ИНН
+var (
+	legalINNWeights = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+	personINN11     = []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+	personINN12     = []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+)
+
+// ValidateINN проверяет ИНН юридического лица (10 цифр) или индивидуального
+// предпринимателя (12 цифр): только цифры, длину и контрольные цифры
+func ValidateINN(inn string) error {
+	digits, err := parseDigits(inn, "ИНН")
+	if err != nil {
+		return err
+	}
+
+	switch len(digits) {
+	case LegalINNLength:
+		if checkDigit(digits, legalINNWeights) != digits[9] {
+			return errors.New("ИНН не прошёл проверку контрольной цифры — проверьте, нет ли опечатки")
+		}
+	case PersonINNLength:
+		if checkDigit(digits, personINN11) != digits[10] || checkDigit(digits, personINN12) != digits[11] {
+			return errors.New("ИНН не прошёл проверку контрольных цифр — проверьте, нет ли опечатки")
+		}
+	default:
+		return fmt.Errorf("ИНН должен содержать 10 цифр (организация) или 12 цифр (ИП), а в нём %d", len(digits))
+	}
+	return nil
+}
+
+// ValidateOGRN проверяет ОГРН (13 цифр) или ОГРНИП (15 цифр) и его соответствие
+// ИНН: у организации с 10-значным ИНН должен быть ОГРН, у ИП с 12-значным — ОГРНИП
+func ValidateOGRN(ogrn, inn string) error {
+	digits, err := parseDigits(ogrn, "ОГРН")
+	if err != nil {
+		return err
+	}
+
+	switch len(digits) {
+	case OGRNLength:
+		if len(inn) == PersonINNLength {
+			return errors.New("Для ИП с 12-значным ИНН нужен ОГРНИП из 15 цифр")
+		}
+		if digits[0] != 1 && digits[0] != 5 {
+			return errors.New("ОГРН организации должен начинаться с цифры 1 или 5")
+		}
+		if remainder(digits[:12], 11)%10 != digits[12] {
+			return errors.New("ОГРН не прошёл проверку контрольной цифры — проверьте, нет ли опечатки")
+		}
+	case OGRNIPLength:
+		if len(inn) == LegalINNLength {
+			return errors.New("Для организации с 10-значным ИНН нужен ОГРН из 13 цифр")
+		}
+		if digits[0] != 3 {
+			return errors.New("ОГРНИП должен начинаться с цифры 3")
+		}
+		if remainder(digits[:14], 13)%10 != digits[14] {
+			return errors.New("ОГРНИП не прошёл проверку контрольной цифры — проверьте, нет ли опечатки")
+		}
+	default:
+		return fmt.Errorf("ОГРН должен содержать 13 цифр, а ОГРНИП — 15, а в нём %d", len(digits))
+	}
+	return nil
+}
+
+// parseDigits переводит строку в цифры и сообщает, если в ней есть другие символы
+func parseDigits(value, name string) ([]int, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%s не может быть пустым", name)
+	}
+	digits := make([]int, 0, len(value))
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return nil, fmt.Errorf("%s должен состоять только из цифр, без пробелов и букв", name)
+		}
+		digits = append(digits, int(r-'0'))
+	}
+	return digits, nil
+}
+
+// checkDigit считает контрольную цифру ИНН: взвешенная сумма первых цифр по модулю 11,
+// а от остатка 10 берётся 0
+func checkDigit(digits, weights []int) int {
+	sum := 0
+	for i, weight := range weights {
+		sum += digits[i] * weight
+	}
+	return sum % 11 % 10
+}
+
+// remainder возвращает остаток от деления числа из цифр на divisor без переполнения
+func remainder(digits []int, divisor int) int {
+	r := 0
+	for _, d := range digits {
+		r = (r*10 + d) % divisor
+	}
+	return r
+}
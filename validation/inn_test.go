@@ -0,0 +1,62 @@
+package validation
+
+import "testing"
+
+func TestValidateINN(t *testing.T) {
+	tests := []struct {
+		name  string
+		inn   string
+		valid bool
+	}{
+		{"организация", "7707083893", true},
+		{"ИП", "500100732259", true},
+		{"неверная контрольная цифра организации", "7707083894", false},
+		{"неверная 11-я цифра ИП", "500100732269", false},
+		{"неверная 12-я цифра ИП", "500100732258", false},
+		{"буквы", "77070838AB", false},
+		{"пробел", "7707 083893", false},
+		{"пустой", "", false},
+		{"11 цифр", "77070838931", false},
+		{"9 цифр", "770708389", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateINN(tt.inn)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateINN(%q) = %v, ожидалась валидность %v", tt.inn, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValidateOGRN(t *testing.T) {
+	tests := []struct {
+		name  string
+		ogrn  string
+		inn   string
+		valid bool
+	}{
+		{"ОГРН организации", "1027700132195", "7707083893", true},
+		{"ОГРНИП", "304500116000157", "500100732259", true},
+		{"неверная контрольная цифра ОГРН", "1027700132196", "7707083893", false},
+		{"неверная контрольная цифра ОГРНИП", "304500116000158", "500100732259", false},
+		{"ОГРН с неверной первой цифрой", "2027700132195", "7707083893", false},
+		{"ОГРНИП с неверной первой цифрой", "104500116000157", "500100732259", false},
+		{"ОГРН для ИНН ИП", "1027700132195", "500100732259", false},
+		{"ОГРНИП для ИНН организации", "304500116000157", "7707083893", false},
+		{"буквы", "10277001321OO", "7707083893", false},
+		{"пробел", "1027700 132195", "7707083893", false},
+		{"14 цифр", "10277001321950", "7707083893", false},
+		{"пустой", "", "7707083893", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOGRN(tt.ogrn, tt.inn)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateOGRN(%q, %q) = %v, ожидалась валидность %v", tt.ogrn, tt.inn, err, tt.valid)
+			}
+		})
+	}
+}
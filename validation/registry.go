@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"context"
+	"errors"
+
+	"tender_bot_go/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Реквизиты уже использует другой пользователь
+var (
+	ErrINNRegistered  = errors.New("Организация с таким ИНН уже зарегистрирована в боте. Если это ваша организация, обратитесь к администратору")
+	ErrINNPending     = errors.New("Заявка на регистрацию с таким ИНН уже подана и ждёт проверки администратором")
+	ErrOGRNRegistered = errors.New("Организация с таким ОГРН уже зарегистрирована в боте. Если это ваша организация, обратитесь к администратору")
+	ErrOGRNPending    = errors.New("Заявка на регистрацию с таким ОГРН уже подана и ждёт проверки администратором")
+)
+
+// RegistryStore — запросы, по которым ищутся реквизиты других пользователей и заявок
+type RegistryStore interface {
+	GetUserIDByInn(ctx context.Context, arg db.GetUserIDByInnParams) (int64, error)
+	GetPendingUserIDByInn(ctx context.Context, arg db.GetPendingUserIDByInnParams) (int64, error)
+	GetUserIDByOgrn(ctx context.Context, arg db.GetUserIDByOgrnParams) (int64, error)
+	GetPendingUserIDByOgrn(ctx context.Context, arg db.GetPendingUserIDByOgrnParams) (int64, error)
+}
+
+// CheckINNAvailable проверяет, что ИНН не занят другим пользователем и не указан в чужой
+// заявке на регистрацию. Возвращает ErrINNRegistered, ErrINNPending или ошибку базы.
+func CheckINNAvailable(ctx context.Context, store RegistryStore, inn string, telegramID int64) error {
+	value := pgtype.Text{String: inn, Valid: true}
+
+	_, err := store.GetUserIDByInn(ctx, db.GetUserIDByInnParams{Inn: value, TelegramID: telegramID})
+	if err := lookupResult(err, ErrINNRegistered); err != nil {
+		return err
+	}
+
+	_, err = store.GetPendingUserIDByInn(ctx, db.GetPendingUserIDByInnParams{Inn: value, TelegramID: telegramID})
+	return lookupResult(err, ErrINNPending)
+}
+
+// CheckOGRNAvailable — то же, что CheckINNAvailable, для ОГРН и ОГРНИП
+func CheckOGRNAvailable(ctx context.Context, store RegistryStore, ogrn string, telegramID int64) error {
+	value := pgtype.Text{String: ogrn, Valid: true}
+
+	_, err := store.GetUserIDByOgrn(ctx, db.GetUserIDByOgrnParams{Ogrn: value, TelegramID: telegramID})
+	if err := lookupResult(err, ErrOGRNRegistered); err != nil {
+		return err
+	}
+
+	_, err = store.GetPendingUserIDByOgrn(ctx, db.GetPendingUserIDByOgrnParams{Ogrn: value, TelegramID: telegramID})
+	return lookupResult(err, ErrOGRNPending)
+}
+
+// lookupResult превращает результат поиска в ошибку: найденная запись — taken,
+// отсутствие записи — nil, остальные ошибки базы возвращаются как есть
+func lookupResult(err error, taken error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return taken
+}